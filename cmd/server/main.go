@@ -3,29 +3,107 @@ package main
 // main.goはサーバー起動のメインファイル
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
+	"Shittaka_back/internal/infrastructure/config"
 	"Shittaka_back/internal/infrastructure/di"
+	"Shittaka_back/internal/infrastructure/lifecycle"
+	"Shittaka_back/internal/infrastructure/logging"
+	"Shittaka_back/internal/infrastructure/tracing"
+	"Shittaka_back/internal/presentation/http/accesstoken"
+	"Shittaka_back/internal/presentation/http/middleware"
+	"Shittaka_back/internal/presentation/http/requestid"
 	"Shittaka_back/internal/presentation/http/router"
 )
 
 func main() {
+	// 設定を読み込み（問題があればログに出力して終了する）
+	cfg := config.LoadConfig()
+
+	// ログはJSONで出力し、リクエストIDとトレースIDをコンテキストから付与する
+	slog.SetDefault(logging.New(os.Stdout, logging.ParseLevel(cfg.Log.Level),
+		logging.ContextAttr{Key: "request_id", Value: requestid.FromContext},
+		logging.ContextAttr{Key: "trace_id", Value: tracing.TraceIDFromContext},
+	))
+
+	// トレースを設定（出力先が none なら無効）
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.ServiceName)
+	if err != nil {
+		slog.Error("failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+
 	// DIコンテナを初期化
-	container := di.NewContainer()
-	genreHandler := di.NewGenreHandler()
-	questionHandler := di.NewQuestionHandler()
-	answerHandler := di.NewAnswerHandler()
-	choiceHandler := di.NewChoiceHandler()
+	container := di.NewContainer(cfg)
+	genreHandler := di.NewGenreHandler(container.Genres)
+	questionHandler := di.NewQuestionHandler(cfg, container.Metrics, container.Questions)
+	answerHandler := di.NewAnswerHandler(cfg, container.Metrics, container.Questions)
+	choiceHandler := di.NewChoiceHandler(cfg, container.Metrics, container.Questions)
+	tagHandler := di.NewTagHandler(cfg, container.Metrics)
+	healthHandler := di.NewHealthHandler(cfg)
+
+	slog.Info("server starting", "port", cfg.Server.Port, "supabase_url", cfg.Supabase.URL)
 
-	log.Printf("Server starting on port %s", container.Config.Port)
-	log.Printf("Supabase URL: %s", container.Config.SupabaseURL)
+	// メトリクスを無効にした場合は /metrics を公開せず、HTTPリクエストも記録しない
+	var metricsHandler router.Metrics
+	if cfg.Features.Metrics {
+		metricsHandler = container.Metrics
+	}
 
 	// ルーターを設定
-	mux := router.SetupRoutes(container.AuthHandler, container.ProfileHandler, genreHandler, questionHandler, answerHandler, choiceHandler)
+	handler := router.SetupRoutes(router.Handlers{
+		Auth:     container.AuthHandler,
+		Profile:  container.ProfileHandler,
+		Genre:    genreHandler,
+		Question: questionHandler,
+		Answer:   answerHandler,
+		Choice:   choiceHandler,
+		Tag:      tagHandler,
+		Health:   healthHandler,
+		Metrics:  metricsHandler,
+
+		DisableDocs: !cfg.Features.Docs,
+		CORS: middleware.CORSOptions{
+			AllowedOrigins:   cfg.CORS.AllowedOrigins,
+			AllowCredentials: cfg.CORS.AllowCredentials,
+			MaxAge:           cfg.CORS.MaxAge,
+		},
+		HSTSMaxAge:  cfg.Security.HSTSMaxAge,
+		RateLimit:   di.NewRateLimit(cfg),
+		Idempotency: di.NewIdempotency(cfg),
+
+		TokenVerifier:     accesstoken.NewVerifier(cfg.Supabase.JWTSecret),
+		ClientIPHeader:    cfg.Server.ClientIPHeader,
+		MaxBodyBytes:      int64(cfg.Server.MaxBodyBytes),
+		PublicCacheMaxAge: cfg.Cache.PublicMaxAge,
+	})
+
+	server := &http.Server{
+		Addr:              ":" + cfg.Server.Port,
+		Handler:           handler,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+
+	// 停止は登録と逆の順で行う（HTTPサーバーが処理中のリクエストを返し終えてから、送信待ちのスパンを送る）
+	manager := lifecycle.NewManager(cfg.Server.ShutdownTimeout)
+	manager.Add(lifecycle.Component{Name: "tracing", Stop: shutdownTracing})
+	manager.Add(lifecycle.HTTPServer(server))
+
+	// SIGTERM（Fly.io のマシン停止）と SIGINT（Ctrl+C）で終了する
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
 
-	// サーバーを起動
-	if err := http.ListenAndServe(":"+container.Config.Port, mux); err != nil {
-		log.Fatal("Server failed to start:", err)
+	if err := manager.Run(ctx); err != nil {
+		slog.Error("server stopped with error", "error", err)
+		os.Exit(1)
 	}
+	slog.Info("server stopped")
 }
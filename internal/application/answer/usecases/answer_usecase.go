@@ -4,56 +4,97 @@ import (
 	"context"
 
 	"Shittaka_back/internal/application/answer/dto"
+	"Shittaka_back/internal/application/metrics"
 	"Shittaka_back/internal/domain/answer/entities"
 	"Shittaka_back/internal/domain/answer/repositories"
+	"Shittaka_back/internal/domain/answer/services"
+	choiceEntities "Shittaka_back/internal/domain/choices/entities"
+	choiceRepositories "Shittaka_back/internal/domain/choices/repositories"
+	questionRepositories "Shittaka_back/internal/domain/question/repositories"
 	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/domain/shared/validation"
+
+	"go.opentelemetry.io/otel"
 )
 
+// tracer はユースケースのスパンのトレーサー
+var tracer = otel.Tracer("Shittaka_back/internal/application/answer/usecases")
+
 // AnswerUsecase は回答ユースケース
 type AnswerUsecase struct {
-	answerRepo repositories.AnswerRepository
+	answerRepo     repositories.AnswerRepository
+	questionRepo   questionRepositories.QuestionRepository
+	choiceRepo     choiceRepositories.ChoiceRepository
+	gradingService *services.GradingService
+	recorder       metrics.Recorder
 }
 
 // NewAnswerUsecase は新しいAnswerUsecaseを作成
-func NewAnswerUsecase(answerRepo repositories.AnswerRepository) *AnswerUsecase {
+func NewAnswerUsecase(answerRepo repositories.AnswerRepository, questionRepo questionRepositories.QuestionRepository, choiceRepo choiceRepositories.ChoiceRepository, gradingService *services.GradingService, recorder metrics.Recorder) *AnswerUsecase {
 	return &AnswerUsecase{
-		answerRepo: answerRepo,
+		answerRepo:     answerRepo,
+		questionRepo:   questionRepo,
+		choiceRepo:     choiceRepo,
+		gradingService: gradingService,
+		recorder:       recorder,
 	}
 }
 
-// CreateAnswer は新しい回答を作成する（認証が必要）
+// CreateAnswer は解答を採点して保存する（認証が必要）
 func (u *AnswerUsecase) CreateAnswer(ctx context.Context, req dto.CreateAnswerRequest, userID string, userToken string) (*dto.AnswerResponse, error) {
+	ctx, span := tracer.Start(ctx, "AnswerUsecase.CreateAnswer")
+	defer span.End()
+
 	// バリデーション
 	if err := u.validateCreateAnswerRequest(req); err != nil {
 		return nil, err
 	}
 
 	// 回答エンティティを作成
-	answer := entities.NewAnswer(userID, req.QuestionID, req.ChoiceID)
+	answer := entities.NewAnswer(userID, req.QuestionID, toSubmission(req))
 
 	// エンティティレベルでのバリデーション
 	if err := answer.Validate(); err != nil {
 		return nil, err
 	}
 
+	// 問題と（必要なら）選択肢を取得して採点
+	question, err := u.questionRepo.GetByID(ctx, req.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var choices []choiceEntities.Choice
+	if question.Type.UsesChoices() {
+		choices, err = u.choiceRepo.GetByQuestionID(ctx, question.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := u.gradingService.Grade(question, choices, answer.Submission)
+	if err != nil {
+		return nil, err
+	}
+	answer.IsCorrect = result.IsCorrect
+	answer.Score = result.Score
+
 	// リポジトリに保存（ユーザートークンを渡してRLS適用）
 	createdAnswer, err := u.answerRepo.Create(ctx, answer, userToken)
 	if err != nil {
 		return nil, err
 	}
+	u.recorder.AnswerSubmitted(result.IsCorrect)
 
 	// レスポンスDTOに変換
-	return &dto.AnswerResponse{
-		ID:         createdAnswer.ID,
-		UserID:     createdAnswer.UserID,
-		QuestionID: createdAnswer.QuestionID,
-		ChoiceID:   createdAnswer.ChoiceID,
-		AnsweredAt: createdAnswer.AnsweredAt,
-	}, nil
+	return toAnswerResponse(createdAnswer), nil
 }
 
 // GetAnswersByUser はユーザーの回答一覧を取得する
 func (u *AnswerUsecase) GetAnswersByUser(ctx context.Context, userID string) ([]*dto.AnswerResponse, error) {
+	ctx, span := tracer.Start(ctx, "AnswerUsecase.GetAnswersByUser")
+	defer span.End()
+
 	answers, err := u.answerRepo.GetByUserID(ctx, userID)
 	if err != nil {
 		return nil, err
@@ -62,13 +103,7 @@ func (u *AnswerUsecase) GetAnswersByUser(ctx context.Context, userID string) ([]
 	// レスポンスDTOに変換
 	responses := make([]*dto.AnswerResponse, len(answers))
 	for i, answer := range answers {
-		responses[i] = &dto.AnswerResponse{
-			ID:         answer.ID,
-			UserID:     answer.UserID,
-			QuestionID: answer.QuestionID,
-			ChoiceID:   answer.ChoiceID,
-			AnsweredAt: answer.AnsweredAt,
-		}
+		responses[i] = toAnswerResponse(answer)
 	}
 
 	return responses, nil
@@ -76,6 +111,9 @@ func (u *AnswerUsecase) GetAnswersByUser(ctx context.Context, userID string) ([]
 
 // GetAnswersByQuestion は問題の回答一覧を取得する
 func (u *AnswerUsecase) GetAnswersByQuestion(ctx context.Context, questionID int64) ([]*dto.AnswerResponse, error) {
+	ctx, span := tracer.Start(ctx, "AnswerUsecase.GetAnswersByQuestion")
+	defer span.End()
+
 	answers, err := u.answerRepo.GetByQuestionID(ctx, questionID)
 	if err != nil {
 		return nil, err
@@ -84,13 +122,7 @@ func (u *AnswerUsecase) GetAnswersByQuestion(ctx context.Context, questionID int
 	// レスポンスDTOに変換
 	responses := make([]*dto.AnswerResponse, len(answers))
 	for i, answer := range answers {
-		responses[i] = &dto.AnswerResponse{
-			ID:         answer.ID,
-			UserID:     answer.UserID,
-			QuestionID: answer.QuestionID,
-			ChoiceID:   answer.ChoiceID,
-			AnsweredAt: answer.AnsweredAt,
-		}
+		responses[i] = toAnswerResponse(answer)
 	}
 
 	return responses, nil
@@ -98,13 +130,41 @@ func (u *AnswerUsecase) GetAnswersByQuestion(ctx context.Context, questionID int
 
 // validateCreateAnswerRequest は回答作成リクエストをバリデーション
 func (u *AnswerUsecase) validateCreateAnswerRequest(req dto.CreateAnswerRequest) error {
-	if req.QuestionID == 0 {
-		return shared.NewValidationError("question_id", "問題IDは必須です")
+	v := validation.New()
+	v.RequiredID("question_id", req.QuestionID)
+	hasAnswer := req.ChoiceID != 0 || len(req.ChoiceIDs) > 0 || req.BooleanAnswer != nil || req.TextAnswer != "" || req.NumericAnswer != nil
+	v.Check(hasAnswer, shared.NewValidationError("answer", shared.ValidationRequired))
+	return v.Err()
+}
+
+// toSubmission はリクエストDTOを解答内容に変換（choice_id と choice_ids はまとめて扱う）
+func toSubmission(req dto.CreateAnswerRequest) entities.Submission {
+	choiceIDs := req.ChoiceIDs
+	if req.ChoiceID != 0 {
+		choiceIDs = append([]int64{req.ChoiceID}, choiceIDs...)
 	}
 
-	if req.ChoiceID == 0 {
-		return shared.NewValidationError("choice_id", "選択肢IDは必須です")
+	return entities.Submission{
+		ChoiceIDs:     choiceIDs,
+		BooleanAnswer: req.BooleanAnswer,
+		TextAnswer:    req.TextAnswer,
+		NumericAnswer: req.NumericAnswer,
 	}
+}
 
-	return nil
-}
\ No newline at end of file
+// toAnswerResponse は回答エンティティをレスポンスDTOに変換
+func toAnswerResponse(answer *entities.Answer) *dto.AnswerResponse {
+	return &dto.AnswerResponse{
+		ID:            answer.ID,
+		UserID:        answer.UserID,
+		QuestionID:    answer.QuestionID,
+		ChoiceID:      answer.ChoiceID,
+		ChoiceIDs:     answer.ChoiceIDs,
+		BooleanAnswer: answer.BooleanAnswer,
+		TextAnswer:    answer.TextAnswer,
+		NumericAnswer: answer.NumericAnswer,
+		IsCorrect:     answer.IsCorrect,
+		Score:         answer.Score,
+		AnsweredAt:    answer.AnsweredAt,
+	}
+}
@@ -13,6 +13,12 @@ type SignUpRequest struct {
 type SignInRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	ClientIP string `json:"-"` // ログインの失敗をIPアドレスごとに数えるために使う
+}
+
+// RefreshRequest はトークン更新リクエストのDTO
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
 }
 
 // AuthResponse は認証レスポンスのDTO
@@ -4,37 +4,52 @@ package usecases
 
 import (
 	"Shittaka_back/internal/application/auth/dto"
+	"Shittaka_back/internal/application/metrics"
 	"Shittaka_back/internal/domain/auth/entities"
 	"Shittaka_back/internal/domain/auth/repositories"
 	"Shittaka_back/internal/domain/auth/services"
 	"context"
+
+	"go.opentelemetry.io/otel"
 )
 
+// tracer はユースケースのスパンのトレーサー
+var tracer = otel.Tracer("Shittaka_back/internal/application/auth/usecases")
+
 // AuthUsecase は認証に関するユースケース
 type AuthUsecase struct {
 	authService *services.AuthService
+	recorder    metrics.Recorder
 }
 
 // NewAuthUsecase は新しいAuthUsecaseを作成
-func NewAuthUsecase(authService *services.AuthService) *AuthUsecase {
+func NewAuthUsecase(authService *services.AuthService, recorder metrics.Recorder) *AuthUsecase {
 	return &AuthUsecase{
 		authService: authService,
+		recorder:    recorder,
 	}
 }
 
 // SignUp はユーザー登録ユースケース
 func (u *AuthUsecase) SignUp(ctx context.Context, req dto.SignUpRequest) (*dto.AuthResponse, error) {
+	ctx, span := tracer.Start(ctx, "AuthUsecase.SignUp")
+	defer span.End()
+
 	authResult, err := u.authService.SignUp(ctx, req.Email, req.Password, req.Username)
 	if err != nil {
 		return nil, err
 	}
+	u.recorder.UserSignedUp()
 
 	return u.toAuthResponse(authResult), nil
 }
 
 // SignIn はユーザーログインユースケース
 func (u *AuthUsecase) SignIn(ctx context.Context, req dto.SignInRequest) (*dto.AuthResponse, error) {
-	authResult, err := u.authService.SignIn(ctx, req.Email, req.Password)
+	ctx, span := tracer.Start(ctx, "AuthUsecase.SignIn")
+	defer span.End()
+
+	authResult, err := u.authService.SignIn(ctx, req.Email, req.Password, req.ClientIP)
 	if err != nil {
 		return nil, err
 	}
@@ -44,11 +59,30 @@ func (u *AuthUsecase) SignIn(ctx context.Context, req dto.SignInRequest) (*dto.A
 
 // SignOut はユーザーログアウトユースケース
 func (u *AuthUsecase) SignOut(ctx context.Context, token string) error {
+	ctx, span := tracer.Start(ctx, "AuthUsecase.SignOut")
+	defer span.End()
+
 	return u.authService.SignOut(ctx, token)
 }
 
+// Refresh はトークン更新ユースケース
+func (u *AuthUsecase) Refresh(ctx context.Context, req dto.RefreshRequest) (*dto.AuthResponse, error) {
+	ctx, span := tracer.Start(ctx, "AuthUsecase.Refresh")
+	defer span.End()
+
+	authResult, err := u.authService.Refresh(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.toAuthResponse(authResult), nil
+}
+
 // GetCurrentUser は現在のユーザー情報を取得するユースケース
 func (u *AuthUsecase) GetCurrentUser(ctx context.Context, token string) (*dto.UserDTO, error) {
+	ctx, span := tracer.Start(ctx, "AuthUsecase.GetCurrentUser")
+	defer span.End()
+
 	user, err := u.authService.GetCurrentUser(ctx, token)
 	if err != nil {
 		return nil, err
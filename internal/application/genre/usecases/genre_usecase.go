@@ -4,14 +4,19 @@ package usecases
 
 import (
 	"context"
-	"strings"
 
 	"Shittaka_back/internal/application/genre/dto"
 	"Shittaka_back/internal/domain/genre/entities"
 	"Shittaka_back/internal/domain/genre/repositories"
 	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/domain/shared/validation"
+
+	"go.opentelemetry.io/otel"
 )
 
+// tracer はユースケースのスパンのトレーサー
+var tracer = otel.Tracer("Shittaka_back/internal/application/genre/usecases")
+
 // GenreUsecase はジャンルユースケース
 type GenreUsecase struct {
 	genreRepo repositories.GenreRepository
@@ -26,6 +31,9 @@ func NewGenreUsecase(genreRepo repositories.GenreRepository) *GenreUsecase {
 
 // CreateGenre は新しいジャンルを作成する（認証が必要）
 func (u *GenreUsecase) CreateGenre(ctx context.Context, req dto.CreateGenreRequest, userToken string) (*dto.GenreResponse, error) {
+	ctx, span := tracer.Start(ctx, "GenreUsecase.CreateGenre")
+	defer span.End()
+
 	// バリデーション
 	if err := u.validateCreateGenreRequest(req); err != nil {
 		return nil, err
@@ -37,7 +45,7 @@ func (u *GenreUsecase) CreateGenre(ctx context.Context, req dto.CreateGenreReque
 		return nil, err
 	}
 	if existingGenre != nil {
-		return nil, shared.NewDomainError("GENRE_EXISTS", "ジャンルが既に存在します")
+		return nil, shared.NewDomainError("GENRE_EXISTS")
 	}
 
 	// ジャンルエンティティを作成
@@ -58,6 +66,9 @@ func (u *GenreUsecase) CreateGenre(ctx context.Context, req dto.CreateGenreReque
 
 // GetAllGenres は全てのジャンルを取得する
 func (u *GenreUsecase) GetAllGenres(ctx context.Context) ([]*dto.GenreResponse, error) {
+	ctx, span := tracer.Start(ctx, "GenreUsecase.GetAllGenres")
+	defer span.End()
+
 	genres, err := u.genreRepo.FindAll(ctx)
 	if err != nil {
 		return nil, err
@@ -76,15 +87,11 @@ func (u *GenreUsecase) GetAllGenres(ctx context.Context) ([]*dto.GenreResponse,
 
 // validateCreateGenreRequest はジャンル作成リクエストをバリデーション
 func (u *GenreUsecase) validateCreateGenreRequest(req dto.CreateGenreRequest) error {
-	if strings.TrimSpace(req.Name) == "" {
-		return shared.NewValidationError("name", "ジャンル名は必須です")
+	v := validation.New()
+	if v.Required("name", req.Name) {
+		v.MaxLength("name", req.Name, 50)
 	}
-
-	if len(req.Name) > 50 {
-		return shared.NewValidationError("name", "ジャンル名は50文字以内で入力してください")
-	}
-
-	return nil
+	return v.Err()
 }
 
 // isNotFoundError はエラーがNot Foundエラーかどうかを判定
@@ -0,0 +1,35 @@
+package dto
+
+// health_dto.goはヘルスチェック関連のデータ転送オブジェクトを定義
+
+import "time"
+
+// ヘルスチェックの状態
+const (
+	StatusOK       = "ok"       // 全ての依存サービスに接続できる
+	StatusDegraded = "degraded" // 接続できない依存サービスがある
+	StatusDown     = "down"     // 依存サービスに接続できない
+)
+
+// CheckResult は依存サービスごとの確認結果
+type CheckResult struct {
+	Name    string
+	Status  string
+	Latency time.Duration
+}
+
+// ReadinessResponse はリクエストを受け付けられるかどうかの確認結果
+type ReadinessResponse struct {
+	Status    string
+	Checks    []CheckResult
+	CheckedAt time.Time
+}
+
+// VersionResponse はビルド情報
+type VersionResponse struct {
+	Version    string
+	Commit     string
+	CommitTime string
+	Modified   bool
+	GoVersion  string
+}
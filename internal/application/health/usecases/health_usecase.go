@@ -0,0 +1,126 @@
+package usecases
+
+// health_usecase.goはヘルスチェックに関するユースケースを定義
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"Shittaka_back/internal/application/health/dto"
+)
+
+// Checker は依存サービス（PostgREST や GoTrue など）への疎通を確認する
+type Checker interface {
+	// Name は結果に表示する依存サービスの名前
+	Name() string
+
+	// Check は依存サービスに接続できなければエラーを返す
+	Check(ctx context.Context) error
+}
+
+// HealthUsecase はヘルスチェックのユースケース
+type HealthUsecase struct {
+	checkers []Checker
+	timeout  time.Duration // 依存サービスごとの確認のタイムアウト
+	cacheTTL time.Duration // 確認結果を使い回す時間
+	now      func() time.Time
+
+	mu     sync.Mutex
+	cached *dto.ReadinessResponse
+}
+
+// NewHealthUsecase は新しいHealthUsecaseを作成
+func NewHealthUsecase(checkers []Checker, timeout, cacheTTL time.Duration) *HealthUsecase {
+	return &HealthUsecase{
+		checkers: checkers,
+		timeout:  timeout,
+		cacheTTL: cacheTTL,
+		now:      time.Now,
+	}
+}
+
+// Readiness は全ての依存サービスへの疎通を並行して確認する
+// 監視からの頻繁なリクエストで依存サービスに負荷をかけないよう、結果は cacheTTL の間使い回す
+func (u *HealthUsecase) Readiness(ctx context.Context) *dto.ReadinessResponse {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.cached != nil && u.now().Sub(u.cached.CheckedAt) < u.cacheTTL {
+		return u.cached
+	}
+
+	// 呼び出し元が切断しても、使い回す結果が中断されたものにならないようにする
+	ctx = context.WithoutCancel(ctx)
+
+	results := make([]dto.CheckResult, len(u.checkers))
+	var wg sync.WaitGroup
+	for i, checker := range u.checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = u.check(ctx, checker)
+		}()
+	}
+	wg.Wait()
+
+	status := dto.StatusOK
+	for _, result := range results {
+		if result.Status != dto.StatusOK {
+			status = dto.StatusDegraded
+		}
+	}
+
+	u.cached = &dto.ReadinessResponse{
+		Status:    status,
+		Checks:    results,
+		CheckedAt: u.now(),
+	}
+	return u.cached
+}
+
+// check は1つの依存サービスへの疎通をタイムアウト付きで確認する
+// 失敗の詳細（接続先のURLなど）は結果に含めずログに出力する
+func (u *HealthUsecase) check(ctx context.Context, checker Checker) dto.CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, u.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Check(ctx)
+	result := dto.CheckResult{
+		Name:    checker.Name(),
+		Status:  dto.StatusOK,
+		Latency: time.Since(start),
+	}
+	if err != nil {
+		result.Status = dto.StatusDown
+		slog.WarnContext(ctx, "dependency check failed", "dependency", result.Name, "error", err)
+	}
+	return result
+}
+
+// Version はバイナリに埋め込まれたビルド情報を返す
+func (u *HealthUsecase) Version() *dto.VersionResponse {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return &dto.VersionResponse{Version: "unknown"}
+	}
+
+	version := &dto.VersionResponse{
+		Version:   info.Main.Version,
+		GoVersion: info.GoVersion,
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			version.Commit = setting.Value
+		case "vcs.time":
+			version.CommitTime = setting.Value
+		case "vcs.modified":
+			version.Modified = setting.Value == "true"
+		}
+	}
+	return version
+}
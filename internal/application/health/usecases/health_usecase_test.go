@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"Shittaka_back/internal/application/health/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChecker は指定したエラーを返し、呼ばれた回数を数える Checker
+type fakeChecker struct {
+	name  string
+	err   error
+	delay time.Duration
+	calls int
+}
+
+func (c *fakeChecker) Name() string { return c.name }
+
+func (c *fakeChecker) Check(ctx context.Context) error {
+	c.calls++
+	select {
+	case <-time.After(c.delay):
+		return c.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestHealthUsecase_Readiness(t *testing.T) {
+	t.Run("全て接続できれば ok", func(t *testing.T) {
+		u := NewHealthUsecase([]Checker{&fakeChecker{name: "postgrest"}, &fakeChecker{name: "gotrue"}}, time.Second, 0)
+
+		readiness := u.Readiness(context.Background())
+		assert.Equal(t, dto.StatusOK, readiness.Status)
+		require.Len(t, readiness.Checks, 2)
+		assert.Equal(t, "postgrest", readiness.Checks[0].Name)
+		assert.Equal(t, dto.StatusOK, readiness.Checks[0].Status)
+	})
+
+	t.Run("失敗とタイムアウトは down になり全体は degraded", func(t *testing.T) {
+		u := NewHealthUsecase([]Checker{
+			&fakeChecker{name: "postgrest", err: errors.New("connection refused")},
+			&fakeChecker{name: "gotrue", delay: time.Second},
+		}, 10*time.Millisecond, 0)
+
+		readiness := u.Readiness(context.Background())
+		assert.Equal(t, dto.StatusDegraded, readiness.Status)
+		assert.Equal(t, dto.StatusDown, readiness.Checks[0].Status)
+		assert.Equal(t, dto.StatusDown, readiness.Checks[1].Status)
+		assert.Less(t, readiness.Checks[1].Latency, time.Second)
+	})
+
+	t.Run("結果はキャッシュの有効期間内は使い回す", func(t *testing.T) {
+		checker := &fakeChecker{name: "postgrest"}
+		u := NewHealthUsecase([]Checker{checker}, time.Second, 5*time.Second)
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		u.now = func() time.Time { return now }
+
+		u.Readiness(context.Background())
+		now = now.Add(4 * time.Second)
+		u.Readiness(context.Background())
+		assert.Equal(t, 1, checker.calls)
+
+		now = now.Add(2 * time.Second)
+		u.Readiness(context.Background())
+		assert.Equal(t, 2, checker.calls)
+	})
+}
+
+func TestHealthUsecase_Version(t *testing.T) {
+	version := NewHealthUsecase(nil, time.Second, 0).Version()
+	assert.NotEmpty(t, version.GoVersion)
+}
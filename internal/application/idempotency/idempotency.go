@@ -0,0 +1,48 @@
+package idempotency
+
+// idempotency.goは Idempotency-Key ヘッダーによる再送の重複防止で保存する記録と、その保存先のインターフェースを定義
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+// Record はキーごとに保存する最初のリクエストとレスポンス
+type Record struct {
+	// RequestHash は最初のリクエストのハッシュ（同じキーで別の内容を送った場合の判定に使う）
+	RequestHash string
+
+	// Completed は最初のリクエストの処理が終わっているか（false なら処理中）
+	Completed bool
+
+	// 処理が終わっている場合の最初のレスポンス
+	StatusCode int
+	Header     map[string]string
+	Body       []byte
+}
+
+// Store はキー（ユーザーと Idempotency-Key の組）ごとの記録を保存する
+// 既定はプロセス内のメモリに保存する実装で、複数のマシンで共有する場合はSupabaseのテーブルに保存する
+type Store interface {
+	// Reserve はキーを処理中として ttl の間確保する
+	// 確保できた場合は (nil, nil) を、既に記録がある場合はその記録を返す
+	// 確保は他のリクエストと競合しないよう、ストアの中で不可分に行う
+	Reserve(ctx context.Context, key, requestHash string, ttl time.Duration) (*Record, error)
+
+	// Complete は処理を終えたレスポンスを ttl の間保存する
+	Complete(ctx context.Context, key string, record Record, ttl time.Duration) error
+
+	// Release は確保を解除する（処理が失敗して、同じキーで再試行できるようにする場合）
+	Release(ctx context.Context, key string) error
+}
+
+// RequestHash はリクエストのメソッド・パス・ボディからハッシュを計算する
+func RequestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,50 @@
+package metrics
+
+// metrics.goはアプリケーションが記録するメトリクスのインターフェースを定義
+
+import "time"
+
+// Recorder はメトリクスを記録する
+// 実装（Prometheus など）は infrastructure 層にあり、テストでは Noop を使う
+type Recorder interface {
+	// ObserveHTTPRequest はHTTPリクエストの処理結果と処理時間を記録する
+	// route にはパスではなくルートのパターン（"GET /api/questions/{id}" など）を渡す
+	ObserveHTTPRequest(method, route string, status int, duration time.Duration)
+
+	// ObserveRepositoryCall はリポジトリ（Supabase）呼び出しの処理時間と失敗を記録する
+	ObserveRepositoryCall(repository, method string, duration time.Duration, err error)
+
+	// AnswerSubmitted は回答の提出を正誤とあわせて記録する
+	AnswerSubmitted(correct bool)
+
+	// UserSignedUp はユーザー登録を記録する
+	UserSignedUp()
+
+	// QuestionCreated は問題の作成を記録する
+	QuestionCreated()
+
+	// ObserveCacheLookup はキャッシュの参照結果（CacheHit / CacheMiss / CacheShared）を記録する
+	ObserveCacheLookup(cache, result string)
+}
+
+// キャッシュの参照結果（ObserveCacheLookup の result）
+const (
+	CacheHit    = "hit"    // 保持している値を返した
+	CacheMiss   = "miss"   // 読み込んだ
+	CacheShared = "shared" // 同時に起きた同じキーの読み込みの結果を共有した
+)
+
+// Noop は何も記録しない Recorder
+type Noop struct{}
+
+func (Noop) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {}
+
+func (Noop) ObserveRepositoryCall(repository, method string, duration time.Duration, err error) {}
+
+func (Noop) AnswerSubmitted(correct bool) {}
+
+func (Noop) UserSignedUp() {}
+
+func (Noop) QuestionCreated() {}
+
+func (Noop) ObserveCacheLookup(cache, result string) {}
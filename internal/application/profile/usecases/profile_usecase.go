@@ -6,8 +6,13 @@ import (
 	"Shittaka_back/internal/application/profile/dto"
 	"Shittaka_back/internal/domain/profile/entities"
 	"Shittaka_back/internal/domain/profile/repositories"
+
+	"go.opentelemetry.io/otel"
 )
 
+// tracer はユースケースのスパンのトレーサー
+var tracer = otel.Tracer("Shittaka_back/internal/application/profile/usecases")
+
 // ProfileUsecase はプロフィールに関するユースケース
 type ProfileUsecase struct {
 	profileRepo repositories.ProfileRepository
@@ -22,6 +27,9 @@ func NewProfileUsecase(profileRepo repositories.ProfileRepository) *ProfileUseca
 
 // GetProfile はプロフィールを取得する
 func (u *ProfileUsecase) GetProfile(ctx context.Context, id string) (*dto.ProfileResponse, error) {
+	ctx, span := tracer.Start(ctx, "ProfileUsecase.GetProfile")
+	defer span.End()
+
 	profile, err := u.profileRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -35,6 +43,9 @@ func (u *ProfileUsecase) GetProfile(ctx context.Context, id string) (*dto.Profil
 
 // CreateProfile は新しいプロフィールを作成する
 func (u *ProfileUsecase) CreateProfile(ctx context.Context, req dto.CreateProfileRequest) (*dto.ProfileResponse, error) {
+	ctx, span := tracer.Start(ctx, "ProfileUsecase.CreateProfile")
+	defer span.End()
+
 	// プロフィールエンティティを作成
 	profile := entities.NewProfile(req.ID, req.Name)
 
@@ -57,6 +68,9 @@ func (u *ProfileUsecase) CreateProfile(ctx context.Context, req dto.CreateProfil
 
 // UpdateProfile はプロフィールを更新する
 func (u *ProfileUsecase) UpdateProfile(ctx context.Context, id string, req dto.UpdateProfileRequest) (*dto.ProfileResponse, error) {
+	ctx, span := tracer.Start(ctx, "ProfileUsecase.UpdateProfile")
+	defer span.End()
+
 	// 既存のプロフィールを取得
 	existingProfile, err := u.profileRepo.GetByID(ctx, id)
 	if err != nil {
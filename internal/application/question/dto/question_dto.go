@@ -4,17 +4,50 @@ import "time"
 
 // CreateQuestionRequest は問題作成リクエスト
 type CreateQuestionRequest struct {
-	GenreID     int64  `json:"genre_id"`
-	Title       string `json:"title"`
-	Body        string `json:"body"`
-	Explanation string `json:"explanation"`
+	GenreID     int64    `json:"genre_id"`
+	Title       string   `json:"title"`
+	Body        string   `json:"body"`
+	Explanation string   `json:"explanation"`
+	Tags        []string `json:"tags"`
+
+	// 問題形式と正解情報（type 省略時は single_choice）
+	Type             string   `json:"type"`
+	PartialCredit    bool     `json:"partial_credit"`
+	AcceptedAnswers  []string `json:"accepted_answers"`
+	CorrectBoolean   *bool    `json:"correct_boolean"`
+	NumericAnswer    *float64 `json:"numeric_answer"`
+	NumericTolerance float64  `json:"numeric_tolerance"`
+
+	// ShuffleChoices は回答者ごとに選択肢の表示順をシャッフルするかどうか
+	ShuffleChoices bool `json:"shuffle_choices"`
 }
 
 // UpdateQuestionRequest は問題更新リクエスト
+// Tags が nil の場合はタグを変更せず、空スライスの場合は全て外す
 type UpdateQuestionRequest struct {
-	Title       string `json:"title"`
-	Body        string `json:"body"`
-	Explanation string `json:"explanation"`
+	Title       string   `json:"title"`
+	Body        string   `json:"body"`
+	Explanation string   `json:"explanation"`
+	Tags        []string `json:"tags"`
+
+	// 正解情報（nil の項目は変更しない。問題形式は変更できない）
+	PartialCredit    *bool    `json:"partial_credit"`
+	AcceptedAnswers  []string `json:"accepted_answers"`
+	CorrectBoolean   *bool    `json:"correct_boolean"`
+	NumericAnswer    *float64 `json:"numeric_answer"`
+	NumericTolerance *float64 `json:"numeric_tolerance"`
+
+	ShuffleChoices *bool `json:"shuffle_choices"`
+
+	// Version は編集を始めたときの版（If-Match ヘッダーで指定する）
+	// 保存されている版と一致しなければ、他の変更を上書きしないよう更新しない
+	Version int `json:"-"`
+}
+
+// QuestionFilter は問題一覧の絞り込み条件
+type QuestionFilter struct {
+	// Tags は指定した全てのタグが付いた問題に絞り込む
+	Tags []string
 }
 
 // QuestionResponse は問題レスポンス
@@ -29,4 +62,16 @@ type QuestionResponse struct {
 	Views          int       `json:"views"`
 	CorrectCount   int       `json:"correct_count"`
 	IncorrectCount int       `json:"incorrect_count"`
-}
\ No newline at end of file
+	Tags           []string  `json:"tags"`
+
+	Type             string   `json:"type"`
+	PartialCredit    bool     `json:"partial_credit"`
+	AcceptedAnswers  []string `json:"accepted_answers"`
+	CorrectBoolean   *bool    `json:"correct_boolean"`
+	NumericAnswer    *float64 `json:"numeric_answer"`
+	NumericTolerance float64  `json:"numeric_tolerance"`
+	ShuffleChoices   bool     `json:"shuffle_choices"`
+
+	Version   int       `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
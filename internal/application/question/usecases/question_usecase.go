@@ -2,78 +2,114 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"sort"
 	"strings"
 
+	"Shittaka_back/internal/application/metrics"
 	"Shittaka_back/internal/application/question/dto"
 	"Shittaka_back/internal/domain/question/entities"
 	"Shittaka_back/internal/domain/question/repositories"
 	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/domain/shared/validation"
+	tagEntities "Shittaka_back/internal/domain/tag/entities"
+	tagRepositories "Shittaka_back/internal/domain/tag/repositories"
+
+	"go.opentelemetry.io/otel"
 )
 
+// tracer はユースケースのスパンのトレーサー
+var tracer = otel.Tracer("Shittaka_back/internal/application/question/usecases")
+
+// maxTitleLength は問題タイトルの最大文字数
+const maxTitleLength = 200
+
 // QuestionUsecase は問題ユースケース
 type QuestionUsecase struct {
 	questionRepo repositories.QuestionRepository
+	tagRepo      tagRepositories.TagRepository
+	recorder     metrics.Recorder
 }
 
 // NewQuestionUsecase は新しいQuestionUsecaseを作成
-func NewQuestionUsecase(questionRepo repositories.QuestionRepository) *QuestionUsecase {
+func NewQuestionUsecase(questionRepo repositories.QuestionRepository, tagRepo tagRepositories.TagRepository, recorder metrics.Recorder) *QuestionUsecase {
 	return &QuestionUsecase{
 		questionRepo: questionRepo,
+		tagRepo:      tagRepo,
+		recorder:     recorder,
 	}
 }
 
 // CreateQuestion は新しい問題を作成する（認証が必要）
 func (u *QuestionUsecase) CreateQuestion(ctx context.Context, req dto.CreateQuestionRequest, userID string, userToken string) (*dto.QuestionResponse, error) {
-	// バリデーション
-	if err := u.validateCreateQuestionRequest(req); err != nil {
-		return nil, err
-	}
+	ctx, span := tracer.Start(ctx, "QuestionUsecase.CreateQuestion")
+	defer span.End()
 
-	// 問題エンティティを作成
+	// 問題エンティティを作成（不明な問題形式は検証で type のエラーになる）
 	question := entities.NewQuestion(req.GenreID, userID, req.Title, req.Body, req.Explanation)
-
-	// エンティティレベルでのバリデーション
-	if err := question.Validate(); err != nil {
+	question.Type, _ = entities.ParseQuestionType(req.Type)
+	question.PartialCredit = req.PartialCredit
+	question.AcceptedAnswers = cleanAcceptedAnswers(req.AcceptedAnswers)
+	question.CorrectBoolean = req.CorrectBoolean
+	question.NumericAnswer = req.NumericAnswer
+	question.NumericTolerance = req.NumericTolerance
+	question.ShuffleChoices = req.ShuffleChoices
+
+	// 全ての項目をまとめて検証し、エラーを1度に返す（タグの正規化・上限数の検証もここで行う）
+	v := validation.New()
+	question.ValidateInto(v)
+	v.MaxLength("title", req.Title, maxTitleLength)
+	tags := tagEntities.NormalizeTagNames(v, req.Tags)
+	if err := v.Err(); err != nil {
 		return nil, err
 	}
 
-	// リポジトリに保存（ユーザートークンを渡してRLS適用）
-	createdQuestion, err := u.questionRepo.Create(ctx, question, userToken)
+	// 問題とタグを1トランザクションで保存（ユーザートークンを渡してRLS適用）
+	createdQuestion, err := u.questionRepo.Create(ctx, question, tags, userToken)
 	if err != nil {
 		return nil, err
 	}
+	u.recorder.QuestionCreated()
 
 	// レスポンスDTOに変換
-	return &dto.QuestionResponse{
-		ID:             createdQuestion.ID,
-		GenreID:        createdQuestion.GenreID,
-		UserID:         createdQuestion.UserID,
-		Title:          createdQuestion.Title,
-		Body:           createdQuestion.Body,
-		Explanation:    createdQuestion.Explanation,
-		CreatedAt:      createdQuestion.CreatedAt,
-		Views:          createdQuestion.Views,
-		CorrectCount:   createdQuestion.CorrectCount,
-		IncorrectCount: createdQuestion.IncorrectCount,
-	}, nil
+	return u.toQuestionResponse(createdQuestion, tags), nil
 }
 
-// UpdateQuestion は問題を更新する（作成者のみ）
-func (u *QuestionUsecase) UpdateQuestion(ctx context.Context, id int64, req dto.UpdateQuestionRequest, userID string, userToken string) error {
+// UpdateQuestion は問題を更新し、更新後の版を返す（作成者のみ）
+// req.Version が保存されている版と一致しない場合は PRECONDITION_FAILED（current_version に現在の版）を返す
+func (u *QuestionUsecase) UpdateQuestion(ctx context.Context, id int64, req dto.UpdateQuestionRequest, userID string, userToken string) (int, error) {
+	ctx, span := tracer.Start(ctx, "QuestionUsecase.UpdateQuestion")
+	defer span.End()
+
 	// バリデーション
 	if err := u.validateUpdateQuestionRequest(req); err != nil {
-		return err
+		return 0, err
 	}
 
 	// 既存の問題を取得
 	existingQuestion, err := u.questionRepo.GetByID(ctx, id)
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	// キャッシュした問題は他のマシンでの更新より古いことがあるため、版が一致しなければ読み込み直して確かめる
+	if existingQuestion.Version != req.Version {
+		if invalidator, ok := u.questionRepo.(repositories.Invalidator); ok {
+			invalidator.Invalidate(id)
+			if existingQuestion, err = u.questionRepo.GetByID(ctx, id); err != nil {
+				return 0, err
+			}
+		}
 	}
 
 	// 作成者かどうかチェック
 	if existingQuestion.UserID != userID {
-		return shared.NewDomainError("FORBIDDEN", "この問題を更新する権限がありません")
+		return 0, shared.NewDomainError("FORBIDDEN")
+	}
+
+	// 編集を始めた後に他の更新が保存されていれば、上書きしない
+	if existingQuestion.Version != req.Version {
+		return 0, preconditionFailed(existingQuestion.Version)
 	}
 
 	// 問題を更新（空でない場合のみ更新）
@@ -86,13 +122,70 @@ func (u *QuestionUsecase) UpdateQuestion(ctx context.Context, id int64, req dto.
 	if req.Explanation != "" {
 		existingQuestion.Explanation = req.Explanation
 	}
+	if req.PartialCredit != nil {
+		existingQuestion.PartialCredit = *req.PartialCredit
+	}
+	if req.AcceptedAnswers != nil {
+		existingQuestion.AcceptedAnswers = cleanAcceptedAnswers(req.AcceptedAnswers)
+	}
+	if req.CorrectBoolean != nil {
+		existingQuestion.CorrectBoolean = req.CorrectBoolean
+	}
+	if req.NumericAnswer != nil {
+		existingQuestion.NumericAnswer = req.NumericAnswer
+	}
+	if req.NumericTolerance != nil {
+		existingQuestion.NumericTolerance = *req.NumericTolerance
+	}
+	if req.ShuffleChoices != nil {
+		existingQuestion.ShuffleChoices = *req.ShuffleChoices
+	}
+
+	// 全ての項目をまとめて検証し、エラーを1度に返す
+	// 正解情報が問題形式と整合しているかと、タグ（nil の場合は変更しない）もここで検証する
+	v := validation.New()
+	existingQuestion.ValidateInto(v)
+	v.MaxLength("title", req.Title, maxTitleLength)
+	var tags []string
+	if req.Tags != nil {
+		tags = tagEntities.NormalizeTagNames(v, req.Tags)
+	}
+	if err := v.Err(); err != nil {
+		return 0, err
+	}
 
-	// リポジトリで更新
-	return u.questionRepo.Update(ctx, existingQuestion, userToken)
+	// 問題とタグ（指定されている場合のみ）を1トランザクションで更新
+	// 読み込んでから保存するまでの間に他の更新が保存された場合も上書きしない
+	if err := u.questionRepo.Update(ctx, existingQuestion, tags, userToken); err != nil {
+		var domainErr shared.DomainError
+		if errors.As(err, &domainErr) && domainErr.Code == "PRECONDITION_FAILED" {
+			return 0, u.currentVersionConflict(ctx, id)
+		}
+		return 0, err
+	}
+
+	return existingQuestion.Version, nil
+}
+
+// currentVersionConflict は更新が競合した問題の現在の版を取得し、PRECONDITION_FAILED を返す
+func (u *QuestionUsecase) currentVersionConflict(ctx context.Context, id int64) error {
+	current, err := u.questionRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return preconditionFailed(current.Version)
+}
+
+// preconditionFailed は現在の版を付けた PRECONDITION_FAILED を返す
+func preconditionFailed(currentVersion int) error {
+	return shared.NewDomainError("PRECONDITION_FAILED").With("current_version", currentVersion)
 }
 
 // DeleteQuestion は問題を削除する（作成者のみ）
 func (u *QuestionUsecase) DeleteQuestion(ctx context.Context, id int64, userID string, userToken string) error {
+	ctx, span := tracer.Start(ctx, "QuestionUsecase.DeleteQuestion")
+	defer span.End()
+
 	// 既存の問題を取得
 	existingQuestion, err := u.questionRepo.GetByID(ctx, id)
 	if err != nil {
@@ -101,7 +194,7 @@ func (u *QuestionUsecase) DeleteQuestion(ctx context.Context, id int64, userID s
 
 	// 作成者かどうかチェック
 	if existingQuestion.UserID != userID {
-		return shared.NewDomainError("FORBIDDEN", "この問題を削除する権限がありません")
+		return shared.NewDomainError("FORBIDDEN")
 	}
 
 	// リポジトリで削除
@@ -110,108 +203,162 @@ func (u *QuestionUsecase) DeleteQuestion(ctx context.Context, id int64, userID s
 
 // GetQuestion は問題を取得する
 func (u *QuestionUsecase) GetQuestion(ctx context.Context, id int64) (*dto.QuestionResponse, error) {
+	ctx, span := tracer.Start(ctx, "QuestionUsecase.GetQuestion")
+	defer span.End()
+
 	question, err := u.questionRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	tagsByQuestion, err := u.tagRepo.FindByQuestionIDs(ctx, []int64{question.ID})
+	if err != nil {
+		return nil, err
+	}
+
 	// レスポンスDTOに変換
-	return &dto.QuestionResponse{
-		ID:             question.ID,
-		GenreID:        question.GenreID,
-		UserID:         question.UserID,
-		Title:          question.Title,
-		Body:           question.Body,
-		Explanation:    question.Explanation,
-		CreatedAt:      question.CreatedAt,
-		Views:          question.Views,
-		CorrectCount:   question.CorrectCount,
-		IncorrectCount: question.IncorrectCount,
-	}, nil
+	return u.toQuestionResponse(question, tagsByQuestion[question.ID]), nil
 }
 
 // GetQuestionsByUser はユーザーの問題一覧を取得する
 func (u *QuestionUsecase) GetQuestionsByUser(ctx context.Context, userID string, userToken string) ([]*dto.QuestionResponse, error) {
+	ctx, span := tracer.Start(ctx, "QuestionUsecase.GetQuestionsByUser")
+	defer span.End()
+
 	questions, err := u.questionRepo.GetByUserID(ctx, userID, userToken)
 	if err != nil {
 		return nil, err
 	}
 
-	// レスポンスDTOに変換
-	responses := make([]*dto.QuestionResponse, len(questions))
-	for i, question := range questions {
-		responses[i] = &dto.QuestionResponse{
-			ID:             question.ID,
-			GenreID:        question.GenreID,
-			UserID:         question.UserID,
-			Title:          question.Title,
-			Body:           question.Body,
-			Explanation:    question.Explanation,
-			CreatedAt:      question.CreatedAt,
-			Views:          question.Views,
-			CorrectCount:   question.CorrectCount,
-			IncorrectCount: question.IncorrectCount,
+	return u.toQuestionResponses(ctx, questions)
+}
+
+// GetAllQuestions は問題一覧を取得する（タグによる絞り込みに対応）
+func (u *QuestionUsecase) GetAllQuestions(ctx context.Context, filter dto.QuestionFilter) ([]*dto.QuestionResponse, error) {
+	ctx, span := tracer.Start(ctx, "QuestionUsecase.GetAllQuestions")
+	defer span.End()
+
+	var questions []*entities.Question
+	var err error
+	if len(filter.Tags) > 0 {
+		questions, err = u.findQuestionsByTags(ctx, filter.Tags)
+	} else {
+		questions, err = u.questionRepo.GetAll(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return u.toQuestionResponses(ctx, questions)
+}
+
+// findQuestionsByTags は指定した全てのタグが付いた問題を取得する
+func (u *QuestionUsecase) findQuestionsByTags(ctx context.Context, names []string) ([]*entities.Question, error) {
+	var matched map[int64]bool
+	for _, name := range names {
+		normalized := tagEntities.NormalizeTagName(name)
+		if normalized == "" {
+			continue
+		}
+
+		ids, err := u.tagRepo.FindQuestionIDsByName(ctx, normalized)
+		if err != nil {
+			return nil, err
+		}
+
+		current := make(map[int64]bool, len(ids))
+		for _, id := range ids {
+			if matched == nil || matched[id] {
+				current[id] = true
+			}
 		}
+		matched = current
 	}
 
-	return responses, nil
+	// 有効なタグが1つも無い場合は絞り込まない
+	if matched == nil {
+		return u.questionRepo.GetAll(ctx)
+	}
+
+	ids := make([]int64, 0, len(matched))
+	for id := range matched {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return u.questionRepo.GetByIDs(ctx, ids)
 }
 
-// GetAllQuestions は全ての問題を取得する
-func (u *QuestionUsecase) GetAllQuestions(ctx context.Context) ([]*dto.QuestionResponse, error) {
-	questions, err := u.questionRepo.GetAll(ctx)
+// toQuestionResponses は問題エンティティの一覧をタグ付きのレスポンスDTOに変換
+func (u *QuestionUsecase) toQuestionResponses(ctx context.Context, questions []*entities.Question) ([]*dto.QuestionResponse, error) {
+	ids := make([]int64, len(questions))
+	for i, question := range questions {
+		ids[i] = question.ID
+	}
+
+	tagsByQuestion, err := u.tagRepo.FindByQuestionIDs(ctx, ids)
 	if err != nil {
 		return nil, err
 	}
 
-	// レスポンスDTOに変換
 	responses := make([]*dto.QuestionResponse, len(questions))
 	for i, question := range questions {
-		responses[i] = &dto.QuestionResponse{
-			ID:             question.ID,
-			GenreID:        question.GenreID,
-			UserID:         question.UserID,
-			Title:          question.Title,
-			Body:           question.Body,
-			Explanation:    question.Explanation,
-			CreatedAt:      question.CreatedAt,
-			Views:          question.Views,
-			CorrectCount:   question.CorrectCount,
-			IncorrectCount: question.IncorrectCount,
-		}
+		responses[i] = u.toQuestionResponse(question, tagsByQuestion[question.ID])
 	}
 
 	return responses, nil
 }
 
-// validateCreateQuestionRequest は問題作成リクエストをバリデーション
-func (u *QuestionUsecase) validateCreateQuestionRequest(req dto.CreateQuestionRequest) error {
-	if req.GenreID == 0 {
-		return shared.NewValidationError("genre_id", "ジャンルIDは必須です")
+// toQuestionResponse は問題エンティティをレスポンスDTOに変換
+func (u *QuestionUsecase) toQuestionResponse(question *entities.Question, tags []string) *dto.QuestionResponse {
+	if tags == nil {
+		tags = []string{}
 	}
 
-	if strings.TrimSpace(req.Title) == "" {
-		return shared.NewValidationError("title", "問題タイトルは必須です")
+	return &dto.QuestionResponse{
+		ID:             question.ID,
+		GenreID:        question.GenreID,
+		UserID:         question.UserID,
+		Title:          question.Title,
+		Body:           question.Body,
+		Explanation:    question.Explanation,
+		CreatedAt:      question.CreatedAt,
+		Views:          question.Views,
+		CorrectCount:   question.CorrectCount,
+		IncorrectCount: question.IncorrectCount,
+		Tags:           tags,
+
+		Type:             string(question.Type),
+		PartialCredit:    question.PartialCredit,
+		AcceptedAnswers:  question.AcceptedAnswers,
+		CorrectBoolean:   question.CorrectBoolean,
+		NumericAnswer:    question.NumericAnswer,
+		NumericTolerance: question.NumericTolerance,
+		ShuffleChoices:   question.ShuffleChoices,
+
+		Version:   question.Version,
+		UpdatedAt: question.UpdatedAt,
 	}
+}
 
-	if len(req.Title) > 200 {
-		return shared.NewValidationError("title", "問題タイトルは200文字以内で入力してください")
+// cleanAcceptedAnswers は記述問題の許容解答から空の要素を取り除く
+func cleanAcceptedAnswers(answers []string) []string {
+	cleaned := make([]string, 0, len(answers))
+	for _, answer := range answers {
+		if trimmed := strings.TrimSpace(answer); trimmed != "" {
+			cleaned = append(cleaned, trimmed)
+		}
 	}
-
-	return nil
+	return cleaned
 }
 
 // validateUpdateQuestionRequest は問題更新リクエストをバリデーション
 func (u *QuestionUsecase) validateUpdateQuestionRequest(req dto.UpdateQuestionRequest) error {
 	// 全てのフィールドが空の場合はエラー
-	if strings.TrimSpace(req.Title) == "" && req.Body == "" && req.Explanation == "" {
-		return shared.NewValidationError("fields", "更新する内容を入力してください")
-	}
-
-	// タイトルが指定されている場合の文字数チェック
-	if strings.TrimSpace(req.Title) != "" && len(req.Title) > 200 {
-		return shared.NewValidationError("title", "問題タイトルは200文字以内で入力してください")
+	if strings.TrimSpace(req.Title) == "" && req.Body == "" && req.Explanation == "" && req.Tags == nil &&
+		req.PartialCredit == nil && req.AcceptedAnswers == nil && req.CorrectBoolean == nil &&
+		req.NumericAnswer == nil && req.NumericTolerance == nil && req.ShuffleChoices == nil {
+		return shared.NewValidationError("fields", shared.ValidationNoChanges)
 	}
-
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,306 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"Shittaka_back/internal/application/metrics"
+	"Shittaka_back/internal/application/question/dto"
+	"Shittaka_back/internal/domain/question/entities"
+	"Shittaka_back/internal/domain/shared"
+	tagEntities "Shittaka_back/internal/domain/tag/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeQuestionRepository は版が一致する場合のみ更新する、メモリ上の QuestionRepository
+// 問題とタグは1トランザクションとして同時に保存する
+type fakeQuestionRepository struct {
+	mu        sync.Mutex
+	questions map[int64]entities.Question
+	tags      map[int64][]string
+
+	// beforeUpdate は Update の直前に呼ばれる（他の更新と競合した場合の再現に使う）
+	beforeUpdate func()
+}
+
+func newFakeQuestionRepository(questions ...entities.Question) *fakeQuestionRepository {
+	r := &fakeQuestionRepository{questions: make(map[int64]entities.Question), tags: make(map[int64][]string)}
+	for _, q := range questions {
+		r.questions[q.ID] = q
+	}
+	return r
+}
+
+func (r *fakeQuestionRepository) Create(ctx context.Context, question *entities.Question, tags []string, userToken string) (*entities.Question, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	created := *question
+	created.ID = int64(len(r.questions) + 1)
+	created.Version = 1
+	r.questions[created.ID] = created
+	r.tags[created.ID] = tags
+	return &created, nil
+}
+
+func (r *fakeQuestionRepository) GetByID(ctx context.Context, id int64) (*entities.Question, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	q, ok := r.questions[id]
+	if !ok {
+		return nil, shared.NewDomainError("NOT_FOUND").With("resource", "question")
+	}
+	return &q, nil
+}
+
+func (r *fakeQuestionRepository) GetByUserID(ctx context.Context, userID string, userToken string) ([]*entities.Question, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeQuestionRepository) Update(ctx context.Context, question *entities.Question, tags []string, userToken string) error {
+	if r.beforeUpdate != nil {
+		r.beforeUpdate()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.questions[question.ID]
+	if !ok || stored.Version != question.Version {
+		return shared.NewDomainError("PRECONDITION_FAILED")
+	}
+	question.Version++
+	question.UpdatedAt = time.Now()
+	r.questions[question.ID] = *question
+	if tags != nil {
+		r.tags[question.ID] = tags
+	}
+	return nil
+}
+
+// bump は他のユーザーによる更新の代わりに版を進める
+func (r *fakeQuestionRepository) bump(id int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	q := r.questions[id]
+	q.Version++
+	r.questions[id] = q
+}
+
+func (r *fakeQuestionRepository) Delete(ctx context.Context, id int64, userToken string) error {
+	return errors.New("not implemented")
+}
+
+func (r *fakeQuestionRepository) GetAll(ctx context.Context) ([]*entities.Question, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeQuestionRepository) GetByIDs(ctx context.Context, ids []int64) ([]*entities.Question, error) {
+	return nil, errors.New("not implemented")
+}
+
+// fakeTagRepository はタグを記録しない TagRepository
+type fakeTagRepository struct{}
+
+func (fakeTagRepository) Search(ctx context.Context, prefix string, limit int) ([]*tagEntities.Tag, error) {
+	return nil, nil
+}
+
+func (fakeTagRepository) FindByQuestionIDs(ctx context.Context, questionIDs []int64) (map[int64][]string, error) {
+	return map[int64][]string{}, nil
+}
+
+func (fakeTagRepository) FindQuestionIDsByName(ctx context.Context, name string) ([]int64, error) {
+	return nil, nil
+}
+
+// requirePreconditionFailed は PRECONDITION_FAILED と現在の版を確認する
+func requirePreconditionFailed(t *testing.T, err error, currentVersion int) {
+	t.Helper()
+	var domainErr shared.DomainError
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "PRECONDITION_FAILED", domainErr.Code)
+	assert.Equal(t, currentVersion, domainErr.Params["current_version"])
+}
+
+func TestQuestionUsecase_UpdateQuestionVersion(t *testing.T) {
+	question := entities.Question{ID: 1, GenreID: 1, UserID: "user-1", Title: "元のタイトル", Body: "本文", Type: entities.QuestionTypeSingleChoice, Version: 3}
+	ctx := context.Background()
+
+	t.Run("版が一致すれば更新して新しい版を返す", func(t *testing.T) {
+		repo := newFakeQuestionRepository(question)
+		u := NewQuestionUsecase(repo, fakeTagRepository{}, metrics.Noop{})
+
+		version, err := u.UpdateQuestion(ctx, 1, dto.UpdateQuestionRequest{Title: "新しいタイトル", Version: 3}, "user-1", "token")
+		require.NoError(t, err)
+		assert.Equal(t, 4, version)
+
+		stored, err := repo.GetByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "新しいタイトル", stored.Title)
+		assert.Equal(t, 4, stored.Version)
+	})
+
+	t.Run("古い版では更新せず現在の版を返す", func(t *testing.T) {
+		repo := newFakeQuestionRepository(question)
+		u := NewQuestionUsecase(repo, fakeTagRepository{}, metrics.Noop{})
+
+		_, err := u.UpdateQuestion(ctx, 1, dto.UpdateQuestionRequest{Title: "新しいタイトル", Version: 2}, "user-1", "token")
+		requirePreconditionFailed(t, err, 3)
+
+		stored, err := repo.GetByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "元のタイトル", stored.Title)
+	})
+
+	t.Run("読み込んだ後に他の更新が保存されれば上書きしない", func(t *testing.T) {
+		repo := newFakeQuestionRepository(question)
+		repo.beforeUpdate = func() { repo.bump(1) }
+		u := NewQuestionUsecase(repo, fakeTagRepository{}, metrics.Noop{})
+
+		_, err := u.UpdateQuestion(ctx, 1, dto.UpdateQuestionRequest{Title: "新しいタイトル", Version: 3}, "user-1", "token")
+		requirePreconditionFailed(t, err, 4)
+	})
+
+	t.Run("作成者以外は版より先に拒否する", func(t *testing.T) {
+		u := NewQuestionUsecase(newFakeQuestionRepository(question), fakeTagRepository{}, metrics.Noop{})
+
+		_, err := u.UpdateQuestion(ctx, 1, dto.UpdateQuestionRequest{Title: "新しいタイトル", Version: 1}, "user-2", "token")
+		var domainErr shared.DomainError
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, "FORBIDDEN", domainErr.Code)
+	})
+}
+
+func TestQuestionUsecase_TagsAreSavedWithQuestion(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("作成時は正規化したタグを問題と一緒に保存する", func(t *testing.T) {
+		correct := true
+		repo := newFakeQuestionRepository()
+		u := NewQuestionUsecase(repo, fakeTagRepository{}, metrics.Noop{})
+
+		created, err := u.CreateQuestion(ctx, dto.CreateQuestionRequest{
+			GenreID:        1,
+			Title:          "地球は丸い？",
+			Body:           "本文",
+			Type:           string(entities.QuestionTypeTrueFalse),
+			CorrectBoolean: &correct,
+			Tags:           []string{"Go", " web ", "go"},
+		}, "user-1", "token")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go", "web"}, created.Tags)
+		assert.Equal(t, []string{"go", "web"}, repo.tags[created.ID])
+	})
+
+	question := entities.Question{ID: 1, GenreID: 1, UserID: "user-1", Title: "元のタイトル", Body: "本文", Type: entities.QuestionTypeSingleChoice, Version: 1}
+
+	t.Run("タグを指定しなければ変更しない", func(t *testing.T) {
+		repo := newFakeQuestionRepository(question)
+		repo.tags[1] = []string{"go"}
+		u := NewQuestionUsecase(repo, fakeTagRepository{}, metrics.Noop{})
+
+		_, err := u.UpdateQuestion(ctx, 1, dto.UpdateQuestionRequest{Title: "新しいタイトル", Version: 1}, "user-1", "token")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go"}, repo.tags[1])
+	})
+
+	t.Run("空のタグを指定すると全て外す", func(t *testing.T) {
+		repo := newFakeQuestionRepository(question)
+		repo.tags[1] = []string{"go"}
+		u := NewQuestionUsecase(repo, fakeTagRepository{}, metrics.Noop{})
+
+		_, err := u.UpdateQuestion(ctx, 1, dto.UpdateQuestionRequest{Tags: []string{}, Version: 1}, "user-1", "token")
+		require.NoError(t, err)
+		assert.Empty(t, repo.tags[1])
+	})
+
+	t.Run("版が一致しなければタグも変更しない", func(t *testing.T) {
+		repo := newFakeQuestionRepository(question)
+		repo.tags[1] = []string{"go"}
+		u := NewQuestionUsecase(repo, fakeTagRepository{}, metrics.Noop{})
+
+		_, err := u.UpdateQuestion(ctx, 1, dto.UpdateQuestionRequest{Tags: []string{"web"}, Version: 0}, "user-1", "token")
+		requirePreconditionFailed(t, err, 1)
+		assert.Equal(t, []string{"go"}, repo.tags[1])
+	})
+}
+
+func TestQuestionUsecase_CreateQuestionReportsAllErrors(t *testing.T) {
+	u := NewQuestionUsecase(newFakeQuestionRepository(), fakeTagRepository{}, metrics.Noop{})
+
+	_, err := u.CreateQuestion(context.Background(), dto.CreateQuestionRequest{
+		Title: strings.Repeat("あ", maxTitleLength+1),
+		Type:  "essay",
+		Tags:  []string{strings.Repeat("t", tagEntities.MaxTagNameLength+1), "a", "b", "c", "d", "e"},
+	}, "user-1", "token")
+
+	var errs shared.ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	got := make([]string, len(errs))
+	for i, e := range errs {
+		got[i] = e.Field + ":" + e.Code
+	}
+	assert.Equal(t, []string{
+		"genre_id:" + shared.ValidationRequired,
+		"type:" + shared.ValidationInvalidValue,
+		"title:" + shared.ValidationMaxLength,
+		"tags:" + shared.ValidationMaxLength,
+		"tags:" + shared.ValidationMaxItems,
+	}, got)
+}
+
+// staleQuestionRepository は他のマシンでの更新を知らない、キャッシュしたリポジトリの代わり
+// Invalidate されるまで最初に読み込んだ問題を返し続ける
+type staleQuestionRepository struct {
+	*fakeQuestionRepository
+	cached map[int64]entities.Question
+}
+
+func (r *staleQuestionRepository) GetByID(ctx context.Context, id int64) (*entities.Question, error) {
+	if q, ok := r.cached[id]; ok {
+		return &q, nil
+	}
+	q, err := r.fakeQuestionRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.cached[id] = *q
+	return q, nil
+}
+
+func (r *staleQuestionRepository) Invalidate(id int64) {
+	delete(r.cached, id)
+}
+
+func TestQuestionUsecase_UpdateQuestionRereadsStaleCache(t *testing.T) {
+	ctx := context.Background()
+	question := entities.Question{ID: 1, GenreID: 1, UserID: "user-1", Title: "元のタイトル", Body: "本文", Type: entities.QuestionTypeSingleChoice, Version: 3}
+
+	repo := &staleQuestionRepository{fakeQuestionRepository: newFakeQuestionRepository(question), cached: make(map[int64]entities.Question)}
+	_, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+
+	// キャッシュした後に、他のマシンで本文が更新された
+	repo.mu.Lock()
+	updated := repo.questions[1]
+	updated.Body = "他のマシンで更新した本文"
+	updated.Version = 4
+	repo.questions[1] = updated
+	repo.mu.Unlock()
+
+	u := NewQuestionUsecase(repo, fakeTagRepository{}, metrics.Noop{})
+	version, err := u.UpdateQuestion(ctx, 1, dto.UpdateQuestionRequest{Title: "新しいタイトル", Version: 4}, "user-1", "token")
+	require.NoError(t, err)
+	assert.Equal(t, 5, version)
+
+	// 古いキャッシュの本文で上書きしない
+	stored, err := repo.fakeQuestionRepository.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "新しいタイトル", stored.Title)
+	assert.Equal(t, "他のマシンで更新した本文", stored.Body)
+}
@@ -0,0 +1,90 @@
+package ratelimit
+
+// ratelimit.goはトークンバケット方式のレート制限の方針と、バケットの状態を保存するストアのインターフェースを定義
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Policy はレート制限の方針
+// バケットには最大 Burst 個のトークンがあり、1分あたり RequestsPerMinute 個の割合で補充される
+// 1リクエストごとに1トークンを使い、トークンがなければ拒否する
+type Policy struct {
+	// Name は方針の名前（ストアのキーとログに使う）
+	Name string
+
+	RequestsPerMinute int
+	Burst             int
+}
+
+// interval は1トークンが補充されるまでの時間
+func (p Policy) interval() time.Duration {
+	return time.Minute / time.Duration(p.RequestsPerMinute)
+}
+
+// Decision はリクエストを許可するかの判定結果
+type Decision struct {
+	Allowed bool
+
+	// Limit はバケットの容量（RateLimit-Limit ヘッダーの値）
+	Limit int
+
+	// Remaining は残りのトークン数（RateLimit-Remaining ヘッダーの値）
+	Remaining int
+
+	// Reset はバケットが満杯に戻るまでの時間（RateLimit-Reset ヘッダーの値）
+	Reset time.Duration
+
+	// RetryAfter は拒否した場合に次のトークンが補充されるまでの時間（Retry-After ヘッダーの値）
+	RetryAfter time.Duration
+}
+
+// Store はキー（IPアドレスやユーザーID）ごとのバケットの状態を保存する
+// 既定はプロセス内のメモリに保存する実装で、複数のマシンで制限を共有する場合は共有のストアに差し替える
+type Store interface {
+	// Take はキーのバケットからトークンを1つ取り出し、判定結果を返す
+	// 取り出しは他のリクエストと競合しないよう、ストアの中で不可分に行う
+	Take(ctx context.Context, key string, policy Policy) (Decision, error)
+}
+
+// Bucket はトークンバケットの状態
+// ストアの実装はこの状態を保存し、Take で更新する
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// NewBucket は満杯のバケットを作成
+func NewBucket(policy Policy, now time.Time) Bucket {
+	return Bucket{Tokens: float64(policy.Burst), UpdatedAt: now}
+}
+
+// Take は経過時間分のトークンを補充してから1つ取り出し、判定結果を返す
+func (b *Bucket) Take(policy Policy, now time.Time) Decision {
+	interval := policy.interval()
+	capacity := float64(policy.Burst)
+
+	if elapsed := now.Sub(b.UpdatedAt); elapsed > 0 {
+		b.Tokens = math.Min(capacity, b.Tokens+float64(elapsed)/float64(interval))
+		b.UpdatedAt = now
+	}
+
+	decision := Decision{Limit: policy.Burst}
+	if b.Tokens >= 1 {
+		b.Tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = time.Duration((1 - b.Tokens) * float64(interval))
+	}
+	decision.Remaining = int(b.Tokens)
+	decision.Reset = time.Duration((capacity - b.Tokens) * float64(interval))
+	return decision
+}
+
+// Full はバケットが満杯に戻っているか（保存しておく必要がないか）を返す
+func (b *Bucket) Full(policy Policy, now time.Time) bool {
+	refilled := b.Tokens + float64(now.Sub(b.UpdatedAt))/float64(policy.interval())
+	return refilled >= float64(policy.Burst)
+}
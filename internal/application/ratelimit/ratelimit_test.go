@@ -0,0 +1,37 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucket_Take(t *testing.T) {
+	policy := Policy{Name: "auth", RequestsPerMinute: 6, Burst: 2} // 10秒に1トークン補充
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	bucket := NewBucket(policy, now)
+
+	t.Run("容量までは許可する", func(t *testing.T) {
+		first := bucket.Take(policy, now)
+		assert.True(t, first.Allowed)
+		assert.Equal(t, 2, first.Limit)
+		assert.Equal(t, 1, first.Remaining)
+		assert.Equal(t, 10*time.Second, first.Reset)
+
+		assert.True(t, bucket.Take(policy, now).Allowed)
+	})
+
+	t.Run("トークンがなければ補充までの時間を返す", func(t *testing.T) {
+		denied := bucket.Take(policy, now.Add(4*time.Second))
+		assert.False(t, denied.Allowed)
+		assert.Equal(t, 0, denied.Remaining)
+		assert.Equal(t, 6*time.Second, denied.RetryAfter)
+	})
+
+	t.Run("時間が経てば補充される", func(t *testing.T) {
+		assert.True(t, bucket.Take(policy, now.Add(10*time.Second)).Allowed)
+		assert.False(t, bucket.Full(policy, now.Add(20*time.Second)))
+		assert.True(t, bucket.Full(policy, now.Add(30*time.Second)))
+	})
+}
@@ -0,0 +1,16 @@
+package dto
+
+// tag_dto.goはタグ関連のデータ転送オブジェクトを定義
+
+// SearchTagsRequest はタグ検索リクエスト
+type SearchTagsRequest struct {
+	Prefix string
+	Limit  int
+}
+
+// TagResponse はタグレスポンス
+type TagResponse struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	UsageCount int    `json:"usage_count"`
+}
@@ -0,0 +1,68 @@
+package usecases
+
+// tag_usecase.goはタグ関連のユースケースを定義
+
+import (
+	"context"
+
+	"Shittaka_back/internal/application/tag/dto"
+	"Shittaka_back/internal/domain/tag/entities"
+	"Shittaka_back/internal/domain/tag/repositories"
+
+	"go.opentelemetry.io/otel"
+)
+
+// tracer はユースケースのスパンのトレーサー
+var tracer = otel.Tracer("Shittaka_back/internal/application/tag/usecases")
+
+const (
+	// defaultTagSearchLimit はタグ検索の既定の取得件数
+	defaultTagSearchLimit = 20
+	// maxTagSearchLimit はタグ検索で指定できる最大件数
+	maxTagSearchLimit = 100
+)
+
+// TagUsecase はタグユースケース
+type TagUsecase struct {
+	tagRepo repositories.TagRepository
+}
+
+// NewTagUsecase は新しいTagUsecaseを作成
+func NewTagUsecase(tagRepo repositories.TagRepository) *TagUsecase {
+	return &TagUsecase{
+		tagRepo: tagRepo,
+	}
+}
+
+// SearchTags はタグを使用数付きで検索する（prefixによるオートコンプリート対応）
+func (u *TagUsecase) SearchTags(ctx context.Context, req dto.SearchTagsRequest) ([]*dto.TagResponse, error) {
+	ctx, span := tracer.Start(ctx, "TagUsecase.SearchTags")
+	defer span.End()
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultTagSearchLimit
+	}
+	if limit > maxTagSearchLimit {
+		limit = maxTagSearchLimit
+	}
+
+	// 保存時と同じ規則で正規化してから前方一致検索する
+	prefix := entities.NormalizeTagName(req.Prefix)
+
+	tags, err := u.tagRepo.Search(ctx, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.TagResponse, len(tags))
+	for i, tag := range tags {
+		responses[i] = &dto.TagResponse{
+			ID:         tag.ID,
+			Name:       tag.Name,
+			UsageCount: tag.UsageCount,
+		}
+	}
+
+	return responses, nil
+}
@@ -2,38 +2,58 @@ package entities
 
 import (
 	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/domain/shared/validation"
 	"time"
 )
 
+// Submission は利用者が送信した解答内容
+// 問題形式に応じていずれか1つを使用する
+type Submission struct {
+	ChoiceIDs     []int64  `json:"choice_ids"`     // single_choice / multi_select
+	BooleanAnswer *bool    `json:"boolean_answer"` // true_false
+	TextAnswer    string   `json:"text_answer"`    // free_text
+	NumericAnswer *float64 `json:"numeric_answer"` // numeric
+}
+
+// IsEmpty は解答内容が何も指定されていないかを返す
+func (s Submission) IsEmpty() bool {
+	return len(s.ChoiceIDs) == 0 && s.BooleanAnswer == nil && s.TextAnswer == "" && s.NumericAnswer == nil
+}
+
 // Answer は回答履歴のドメインエンティティ
 type Answer struct {
 	ID         int64     `json:"id"`
 	UserID     string    `json:"user_id"`
 	QuestionID int64     `json:"question_id"`
-	ChoiceID   int64     `json:"choice_id"`
+	ChoiceID   int64     `json:"choice_id"` // 単一選択時の選択肢ID（互換性のため保持）
 	AnsweredAt time.Time `json:"answered_at"`
+	Submission
+
+	IsCorrect bool    `json:"is_correct"`
+	Score     float64 `json:"score"` // 0〜1（部分点を含む）
 }
 
 // NewAnswer は新しいAnswerエンティティを作成
-func NewAnswer(userID string, questionID, choiceID int64) *Answer {
+func NewAnswer(userID string, questionID int64, submission Submission) *Answer {
+	var choiceID int64
+	if len(submission.ChoiceIDs) == 1 {
+		choiceID = submission.ChoiceIDs[0]
+	}
+
 	return &Answer{
 		UserID:     userID,
 		QuestionID: questionID,
 		ChoiceID:   choiceID,
+		Submission: submission,
 		AnsweredAt: time.Now(),
 	}
 }
 
 // Validate はAnswerエンティティのバリデーションを行う
 func (a *Answer) Validate() error {
-	if a.UserID == "" {
-		return shared.NewValidationError("user_id", "user_id is required")
-	}
-	if a.QuestionID == 0 {
-		return shared.NewValidationError("question_id", "question_id is required")
-	}
-	if a.ChoiceID == 0 {
-		return shared.NewValidationError("choice_id", "choice_id is required")
-	}
-	return nil
-}
\ No newline at end of file
+	v := validation.New()
+	v.Required("user_id", a.UserID)
+	v.RequiredID("question_id", a.QuestionID)
+	v.Check(!a.Submission.IsEmpty(), shared.NewValidationError("answer", shared.ValidationRequired))
+	return v.Err()
+}
@@ -0,0 +1,178 @@
+package services
+
+// grading_service.goは解答の採点を行うドメインサービスを定義
+
+import (
+	"math"
+	"strings"
+
+	"Shittaka_back/internal/domain/answer/entities"
+	choiceEntities "Shittaka_back/internal/domain/choices/entities"
+	questionEntities "Shittaka_back/internal/domain/question/entities"
+	"Shittaka_back/internal/domain/shared"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// numericEpsilon は浮動小数点の丸め誤差を吸収するための値
+const numericEpsilon = 1e-9
+
+// GradeResult は採点結果
+type GradeResult struct {
+	IsCorrect bool
+	Score     float64 // 0〜1
+}
+
+// GradingService は問題形式に応じて解答を採点するドメインサービス
+type GradingService struct{}
+
+// NewGradingService は新しいGradingServiceを作成
+func NewGradingService() *GradingService {
+	return &GradingService{}
+}
+
+// Grade は問題と選択肢をもとに解答を採点する
+// choices は選択肢を使う問題形式の場合のみ必要
+func (s *GradingService) Grade(question *questionEntities.Question, choices []choiceEntities.Choice, submission entities.Submission) (GradeResult, error) {
+	switch question.Type {
+	case questionEntities.QuestionTypeSingleChoice, "":
+		return s.gradeSingleChoice(choices, submission)
+	case questionEntities.QuestionTypeMultiSelect:
+		return s.gradeMultiSelect(question, choices, submission)
+	case questionEntities.QuestionTypeTrueFalse:
+		return s.gradeTrueFalse(question, submission)
+	case questionEntities.QuestionTypeFreeText:
+		return s.gradeFreeText(question, submission)
+	case questionEntities.QuestionTypeNumeric:
+		return s.gradeNumeric(question, submission)
+	default:
+		return GradeResult{}, shared.NewDomainError("UNSUPPORTED_QUESTION_TYPE")
+	}
+}
+
+// gradeSingleChoice は単一選択問題を採点する
+func (s *GradingService) gradeSingleChoice(choices []choiceEntities.Choice, submission entities.Submission) (GradeResult, error) {
+	if len(submission.ChoiceIDs) != 1 {
+		return GradeResult{}, shared.NewValidationError("choice_id", shared.ValidationExactlyOne)
+	}
+
+	choice, ok := findChoice(choices, submission.ChoiceIDs[0])
+	if !ok {
+		return GradeResult{}, shared.NewValidationError("choice_id", shared.ValidationUnknownID)
+	}
+
+	return result(choice.IsCorrect), nil
+}
+
+// gradeMultiSelect は複数選択問題を採点する
+// 正解を全て選び、不正解を1つも選ばなかった場合のみ正解とする
+// 部分点が有効な場合は (選んだ正解数 - 選んだ不正解数) / 正解数 を0以上に丸めてスコアとする
+func (s *GradingService) gradeMultiSelect(question *questionEntities.Question, choices []choiceEntities.Choice, submission entities.Submission) (GradeResult, error) {
+	if len(submission.ChoiceIDs) == 0 {
+		return GradeResult{}, shared.NewValidationError("choice_ids", shared.ValidationMinItems).With("min", 1)
+	}
+
+	selected := make(map[int64]bool, len(submission.ChoiceIDs))
+	for _, id := range submission.ChoiceIDs {
+		if _, ok := findChoice(choices, id); !ok {
+			return GradeResult{}, shared.NewValidationError("choice_ids", shared.ValidationUnknownID)
+		}
+		selected[id] = true
+	}
+
+	totalCorrect, selectedCorrect, selectedIncorrect := 0, 0, 0
+	for _, choice := range choices {
+		if choice.IsCorrect {
+			totalCorrect++
+		}
+		if !selected[choice.ID] {
+			continue
+		}
+		if choice.IsCorrect {
+			selectedCorrect++
+		} else {
+			selectedIncorrect++
+		}
+	}
+
+	if totalCorrect == 0 {
+		return GradeResult{}, shared.NewDomainError("INVALID_ANSWER_KEY")
+	}
+
+	isCorrect := selectedCorrect == totalCorrect && selectedIncorrect == 0
+	if isCorrect || !question.PartialCredit {
+		return result(isCorrect), nil
+	}
+
+	score := float64(selectedCorrect-selectedIncorrect) / float64(totalCorrect)
+	return GradeResult{IsCorrect: false, Score: math.Max(0, score)}, nil
+}
+
+// gradeTrueFalse は○×問題を採点する
+func (s *GradingService) gradeTrueFalse(question *questionEntities.Question, submission entities.Submission) (GradeResult, error) {
+	if submission.BooleanAnswer == nil {
+		return GradeResult{}, shared.NewValidationError("boolean_answer", shared.ValidationRequired)
+	}
+	if question.CorrectBoolean == nil {
+		return GradeResult{}, shared.NewDomainError("INVALID_ANSWER_KEY")
+	}
+
+	return result(*submission.BooleanAnswer == *question.CorrectBoolean), nil
+}
+
+// gradeFreeText は記述問題を採点する（正規化した上で許容解答のいずれかと一致すれば正解）
+func (s *GradingService) gradeFreeText(question *questionEntities.Question, submission entities.Submission) (GradeResult, error) {
+	answer := NormalizeTextAnswer(submission.TextAnswer)
+	if answer == "" {
+		return GradeResult{}, shared.NewValidationError("text_answer", shared.ValidationRequired)
+	}
+
+	for _, accepted := range question.AcceptedAnswers {
+		if NormalizeTextAnswer(accepted) == answer {
+			return result(true), nil
+		}
+	}
+
+	return result(false), nil
+}
+
+// gradeNumeric は数値問題を採点する（許容誤差以内であれば正解）
+func (s *GradingService) gradeNumeric(question *questionEntities.Question, submission entities.Submission) (GradeResult, error) {
+	if submission.NumericAnswer == nil {
+		return GradeResult{}, shared.NewValidationError("numeric_answer", shared.ValidationRequired)
+	}
+	if question.NumericAnswer == nil {
+		return GradeResult{}, shared.NewDomainError("INVALID_ANSWER_KEY")
+	}
+
+	diff := math.Abs(*submission.NumericAnswer - *question.NumericAnswer)
+	return result(diff <= question.NumericTolerance+numericEpsilon), nil
+}
+
+// NormalizeTextAnswer は記述解答を比較用に正規化する
+// 全角/半角の統一（NFKC）、前後の空白除去、小文字化、連続する空白の1文字化を行う
+func NormalizeTextAnswer(s string) string {
+	s = norm.NFKC.String(s)
+	s = strings.ToLower(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// ヘルパー関数
+
+// findChoice は選択肢一覧からIDで選択肢を探す
+func findChoice(choices []choiceEntities.Choice, id int64) (choiceEntities.Choice, bool) {
+	for _, choice := range choices {
+		if choice.ID == id {
+			return choice, true
+		}
+	}
+	return choiceEntities.Choice{}, false
+}
+
+// result は正誤から満点または0点の採点結果を作る
+func result(isCorrect bool) GradeResult {
+	if isCorrect {
+		return GradeResult{IsCorrect: true, Score: 1}
+	}
+	return GradeResult{IsCorrect: false, Score: 0}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"testing"
+
+	"Shittaka_back/internal/domain/answer/entities"
+	choiceEntities "Shittaka_back/internal/domain/choices/entities"
+	questionEntities "Shittaka_back/internal/domain/question/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func boolPtr(b bool) *bool        { return &b }
+func floatPtr(f float64) *float64 { return &f }
+
+func TestGradingService_Grade(t *testing.T) {
+	choices := []choiceEntities.Choice{
+		{ID: 1, QuestionID: 10, Text: "A", IsCorrect: true},
+		{ID: 2, QuestionID: 10, Text: "B", IsCorrect: false},
+		{ID: 3, QuestionID: 10, Text: "C", IsCorrect: true},
+	}
+
+	tests := []struct {
+		name       string
+		question   questionEntities.Question
+		submission entities.Submission
+		want       GradeResult
+		wantErr    bool
+	}{
+		{
+			name:       "単一選択の正解",
+			question:   questionEntities.Question{Type: questionEntities.QuestionTypeSingleChoice},
+			submission: entities.Submission{ChoiceIDs: []int64{1}},
+			want:       GradeResult{IsCorrect: true, Score: 1},
+		},
+		{
+			name:       "単一選択で他の問題の選択肢",
+			question:   questionEntities.Question{Type: questionEntities.QuestionTypeSingleChoice},
+			submission: entities.Submission{ChoiceIDs: []int64{99}},
+			wantErr:    true,
+		},
+		{
+			name:       "複数選択で全て正解",
+			question:   questionEntities.Question{Type: questionEntities.QuestionTypeMultiSelect},
+			submission: entities.Submission{ChoiceIDs: []int64{3, 1}},
+			want:       GradeResult{IsCorrect: true, Score: 1},
+		},
+		{
+			name:       "複数選択で一部のみ（部分点なし）",
+			question:   questionEntities.Question{Type: questionEntities.QuestionTypeMultiSelect},
+			submission: entities.Submission{ChoiceIDs: []int64{1}},
+			want:       GradeResult{IsCorrect: false, Score: 0},
+		},
+		{
+			name:       "複数選択で一部のみ（部分点あり）",
+			question:   questionEntities.Question{Type: questionEntities.QuestionTypeMultiSelect, PartialCredit: true},
+			submission: entities.Submission{ChoiceIDs: []int64{1}},
+			want:       GradeResult{IsCorrect: false, Score: 0.5},
+		},
+		{
+			name:       "複数選択で不正解を含む（部分点あり）",
+			question:   questionEntities.Question{Type: questionEntities.QuestionTypeMultiSelect, PartialCredit: true},
+			submission: entities.Submission{ChoiceIDs: []int64{1, 2}},
+			want:       GradeResult{IsCorrect: false, Score: 0},
+		},
+		{
+			name:       "○×問題",
+			question:   questionEntities.Question{Type: questionEntities.QuestionTypeTrueFalse, CorrectBoolean: boolPtr(false)},
+			submission: entities.Submission{BooleanAnswer: boolPtr(false)},
+			want:       GradeResult{IsCorrect: true, Score: 1},
+		},
+		{
+			name:       "記述問題は全角・大文字・空白の違いを無視",
+			question:   questionEntities.Question{Type: questionEntities.QuestionTypeFreeText, AcceptedAnswers: []string{"Tokyo Tower"}},
+			submission: entities.Submission{TextAnswer: "  ｔｏｋｙｏ　　tower "},
+			want:       GradeResult{IsCorrect: true, Score: 1},
+		},
+		{
+			name:       "数値問題は許容誤差以内なら正解",
+			question:   questionEntities.Question{Type: questionEntities.QuestionTypeNumeric, NumericAnswer: floatPtr(3.14), NumericTolerance: 0.01},
+			submission: entities.Submission{NumericAnswer: floatPtr(3.15)},
+			want:       GradeResult{IsCorrect: true, Score: 1},
+		},
+		{
+			name:       "数値問題で許容誤差を超える",
+			question:   questionEntities.Question{Type: questionEntities.QuestionTypeNumeric, NumericAnswer: floatPtr(3.14), NumericTolerance: 0.01},
+			submission: entities.Submission{NumericAnswer: floatPtr(3.2)},
+			want:       GradeResult{IsCorrect: false, Score: 0},
+		},
+		{
+			name:       "数値問題で解答なし",
+			question:   questionEntities.Question{Type: questionEntities.QuestionTypeNumeric, NumericAnswer: floatPtr(1)},
+			submission: entities.Submission{TextAnswer: "1"},
+			wantErr:    true,
+		},
+	}
+
+	service := NewGradingService()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.Grade(&tt.question, choices, tt.submission)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.IsCorrect, got.IsCorrect)
+			assert.InDelta(t, tt.want.Score, got.Score, 1e-9)
+		})
+	}
+}
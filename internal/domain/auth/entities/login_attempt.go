@@ -0,0 +1,12 @@
+package entities
+
+// login_attempt.goはログインの失敗の記録を定義
+
+import "time"
+
+// LoginAttempt はメールアドレスまたはIPアドレスごとのログインの失敗の記録
+// ログインできない期間は回数と最後の失敗の日時から決まるため保存しない（LoginThrottle が計算する）
+type LoginAttempt struct {
+	Failures     int       // 続けて失敗した回数（成功するか、忘れるまでの間）
+	LastFailedAt time.Time // 最後に失敗した日時
+}
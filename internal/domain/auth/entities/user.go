@@ -3,7 +3,7 @@ package entities
 // user.goはユーザーのドメインエンティティを定義
 
 import (
-	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/domain/shared/validation"
 	"time"
 )
 
@@ -30,11 +30,8 @@ func NewUser(id, email, username string) *User {
 
 // Validate はUserエンティティのバリデーションを行う
 func (u *User) Validate() error {
-	if u.Email == "" {
-		return shared.NewValidationError("email", "email is required")
-	}
-	if u.ID == "" {
-		return shared.NewValidationError("id", "id is required")
-	}
-	return nil
+	v := validation.New()
+	v.Required("email", u.Email)
+	v.Required("id", u.ID)
+	return v.Err()
 }
@@ -0,0 +1,24 @@
+package repositories
+
+// login_attempt_repository.goはログインの失敗の記録に関するリポジトリのインターフェースを定義
+
+import (
+	"Shittaka_back/internal/domain/auth/entities"
+	"context"
+	"time"
+)
+
+// LoginAttemptRepository はログインの失敗の記録に関するリポジトリのインターフェース
+// キーは "email:<メールアドレス>" や "ip:<IPアドレス>" の形式
+type LoginAttemptRepository interface {
+	// Find はキーの記録を取得する（記録がなければ nil）
+	Find(ctx context.Context, key string) (*entities.LoginAttempt, error)
+
+	// Increment はキーの失敗の回数を1増やし、最後に失敗した日時を now にした記録を返す
+	// 最後の失敗から window が過ぎていれば1回目として数え直す（window が過ぎた記録は削除してよい）
+	// 同時に呼ばれても回数を取りこぼさないよう、読み込みと書き込みを不可分に行うこと
+	Increment(ctx context.Context, key string, now time.Time, window time.Duration) (*entities.LoginAttempt, error)
+
+	// Delete はキーの記録を削除する
+	Delete(ctx context.Context, key string) error
+}
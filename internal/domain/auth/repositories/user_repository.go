@@ -6,14 +6,22 @@ package repositories
 import (
 	"Shittaka_back/internal/domain/auth/entities"
 	"context"
+	"errors"
 )
 
+// ErrInvalidCredentials はメールアドレスまたはパスワードが正しくないことを表す
+var ErrInvalidCredentials = errors.New("invalid login credentials")
+
+// ErrEmailNotConfirmed はメールアドレスの確認が完了していないことを表す
+var ErrEmailNotConfirmed = errors.New("email not confirmed")
+
 // UserRepository はユーザーに関するリポジトリのインターフェース
 type UserRepository interface {
 	// Create は新しいユーザーを作成
 	Create(ctx context.Context, email, password string, metadata map[string]interface{}) (*entities.User, error)
 
 	// Authenticate はユーザーの認証を行い、トークンを返す
+	// 認証情報が正しくない場合は ErrInvalidCredentials、メールアドレスが未確認の場合は ErrEmailNotConfirmed を返す
 	Authenticate(ctx context.Context, email, password string) (*AuthResult, error)
 
 	// FindByID はIDでユーザーを検索
@@ -33,6 +41,9 @@ type UserRepository interface {
 
 	// GetCurrentUser はアクセストークンから現在のユーザー情報を取得
 	GetCurrentUser(ctx context.Context, token string) (*entities.User, error)
+
+	// Refresh はリフレッシュトークンから新しいトークンを発行する
+	Refresh(ctx context.Context, refreshToken string) (*AuthResult, error)
 }
 
 // AuthResult は認証結果を表す
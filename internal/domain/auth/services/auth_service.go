@@ -7,7 +7,9 @@ import (
 	"Shittaka_back/internal/domain/auth/entities"
 	"Shittaka_back/internal/domain/auth/repositories"
 	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/domain/shared/validation"
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -15,12 +17,14 @@ import (
 // AuthService は認証に関するドメインサービス
 type AuthService struct {
 	userRepo repositories.UserRepository
+	throttle *LoginThrottle
 }
 
 // NewAuthService は新しいAuthServiceを作成
-func NewAuthService(userRepo repositories.UserRepository) *AuthService {
+func NewAuthService(userRepo repositories.UserRepository, throttle *LoginThrottle) *AuthService {
 	return &AuthService{
 		userRepo: userRepo,
+		throttle: throttle,
 	}
 }
 
@@ -34,7 +38,7 @@ func (s *AuthService) SignUp(ctx context.Context, email, password, username stri
 	// 既存ユーザーチェック
 	existingUser, _ := s.userRepo.FindByEmail(ctx, email)
 	if existingUser != nil {
-		return nil, shared.NewDomainError("USER_EXISTS", "user with this email already exists")
+		return nil, shared.NewDomainError("USER_EXISTS")
 	}
 
 	// ユーザー作成
@@ -57,25 +61,43 @@ func (s *AuthService) SignUp(ctx context.Context, email, password, username stri
 }
 
 // SignIn はユーザーログインを行う
-func (s *AuthService) SignIn(ctx context.Context, email, password string) (*repositories.AuthResult, error) {
+// clientIP はログインの失敗をIPアドレスごとに数えるために使う（空ならメールアドレスごとにのみ数える）
+func (s *AuthService) SignIn(ctx context.Context, email, password, clientIP string) (*repositories.AuthResult, error) {
 	// バリデーション
 	if err := s.validateSignInInput(email, password); err != nil {
 		return nil, err
 	}
 
+	// 失敗が続いている場合は、パスワードを確認せずに拒否する
+	if err := s.throttle.Check(ctx, email, clientIP); err != nil {
+		return nil, err
+	}
+
 	// 認証
+	// 失敗として数えるのは認証情報の誤りのみ（GoTrueの障害やメールアドレスの未確認ではロックしない）
 	authResult, err := s.userRepo.Authenticate(ctx, email, password)
-	if err != nil {
-		return nil, shared.NewDomainError("AUTH_FAILED", "invalid credentials or email not confirmed")
+	switch {
+	case errors.Is(err, repositories.ErrInvalidCredentials):
+		if err := s.throttle.Fail(ctx, email, clientIP); err != nil {
+			return nil, err
+		}
+		return nil, shared.NewDomainError("AUTH_FAILED")
+	case errors.Is(err, repositories.ErrEmailNotConfirmed):
+		return nil, shared.NewDomainError("AUTH_FAILED")
+	case err != nil:
+		return nil, err
 	}
 
+	// 失敗の記録は期限が来れば消えるため、消せなくてもログインは成功とする
+	_ = s.throttle.Succeed(ctx, email)
+
 	return authResult, nil
 }
 
 // SignOut はユーザーログアウトを行う
 func (s *AuthService) SignOut(ctx context.Context, token string) error {
 	if token == "" {
-		return shared.NewValidationError("token", "token is required")
+		return shared.NewValidationError("token", shared.ValidationRequired)
 	}
 
 	// "Bearer " プレフィックスを除去
@@ -89,7 +111,7 @@ func (s *AuthService) SignOut(ctx context.Context, token string) error {
 // GetCurrentUser はアクセストークンから現在のユーザー情報を取得
 func (s *AuthService) GetCurrentUser(ctx context.Context, token string) (*entities.User, error) {
 	if token == "" {
-		return nil, shared.NewValidationError("token", "token is required")
+		return nil, shared.NewValidationError("token", shared.ValidationRequired)
 	}
 
 	// "Bearer " プレフィックスを除去
@@ -99,41 +121,43 @@ func (s *AuthService) GetCurrentUser(ctx context.Context, token string) (*entiti
 
 	user, err := s.userRepo.GetCurrentUser(ctx, token)
 	if err != nil {
-		return nil, shared.NewDomainError("AUTH_FAILED", "invalid or expired token")
+		return nil, shared.NewDomainError("INVALID_TOKEN")
 	}
 
 	return user, nil
 }
 
-// validateSignUpInput はサインアップ入力をバリデート
-func (s *AuthService) validateSignUpInput(email, password, username string) error {
-	if email == "" {
-		return shared.NewValidationError("email", "email is required")
-	}
-	if password == "" {
-		return shared.NewValidationError("password", "password is required")
-	}
-	if len(password) < 6 {
-		return shared.NewValidationError("password", "password must be at least 6 characters")
-	}
-	if username == "" {
-		return shared.NewValidationError("username", "username is required")
+// Refresh はリフレッシュトークンから新しいトークンを発行する
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*repositories.AuthResult, error) {
+	if refreshToken == "" {
+		return nil, shared.NewValidationError("refresh_token", shared.ValidationRequired)
 	}
-	if !strings.Contains(email, "@") {
-		return shared.NewValidationError("email", "invalid email format")
+
+	authResult, err := s.userRepo.Refresh(ctx, refreshToken)
+	if err != nil {
+		return nil, shared.NewDomainError("INVALID_TOKEN")
 	}
 
-	return nil
+	return authResult, nil
 }
 
-// validateSignInInput はサインイン入力をバリデート
-func (s *AuthService) validateSignInInput(email, password string) error {
-	if email == "" {
-		return shared.NewValidationError("email", "email is required")
+// validateSignUpInput はサインアップ入力をバリデート
+func (s *AuthService) validateSignUpInput(email, password, username string) error {
+	v := validation.New()
+	if v.Required("email", email) {
+		v.Check(strings.Contains(email, "@"), shared.NewValidationError("email", shared.ValidationInvalidFormat))
 	}
-	if password == "" {
-		return shared.NewValidationError("password", "password is required")
+	if v.Required("password", password) {
+		v.MinLength("password", password, 6)
 	}
+	v.Required("username", username)
+	return v.Err()
+}
 
-	return nil
+// validateSignInInput はサインイン入力をバリデート
+func (s *AuthService) validateSignInInput(email, password string) error {
+	v := validation.New()
+	v.Required("email", email)
+	v.Required("password", password)
+	return v.Err()
 }
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"Shittaka_back/internal/domain/auth/repositories"
+	"Shittaka_back/internal/domain/shared"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUserRepository は Authenticate が決まったエラーを返す UserRepository
+type fakeUserRepository struct {
+	repositories.UserRepository
+	authErr error
+}
+
+func (r *fakeUserRepository) Authenticate(ctx context.Context, email, password string) (*repositories.AuthResult, error) {
+	return nil, r.authErr
+}
+
+func TestAuthService_SignInCountsOnlyInvalidCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		authErr  error
+		wantCode string
+		failures int
+	}{
+		"認証情報の誤りは失敗として数える":     {authErr: repositories.ErrInvalidCredentials, wantCode: "AUTH_FAILED", failures: 1},
+		"メールアドレスの未確認は数えない":     {authErr: repositories.ErrEmailNotConfirmed, wantCode: "AUTH_FAILED", failures: 0},
+		"GoTrueの障害は数えずにそのまま返す": {authErr: shared.NewInfrastructureError("authentication", 503, "unavailable"), failures: 0},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			attempts := newFakeLoginAttemptRepository()
+			throttle, _ := newTestThrottle(attempts)
+			service := NewAuthService(&fakeUserRepository{authErr: tt.authErr}, throttle)
+
+			_, err := service.SignIn(ctx, "user@example.com", "password", "192.0.2.1")
+			if tt.wantCode != "" {
+				var domainErr shared.DomainError
+				require.ErrorAs(t, err, &domainErr)
+				assert.Equal(t, tt.wantCode, domainErr.Code)
+			} else {
+				assert.ErrorIs(t, err, tt.authErr)
+			}
+
+			for _, key := range []string{"email:user@example.com", "ip:192.0.2.1"} {
+				attempt, err := attempts.Find(ctx, key)
+				require.NoError(t, err)
+				if tt.failures == 0 {
+					assert.Nil(t, attempt, key)
+				} else {
+					require.NotNil(t, attempt, key)
+					assert.Equal(t, tt.failures, attempt.Failures, key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+// login_throttle.goはログインの失敗が続いた場合にログインを一時的に制限するドメインサービスを定義
+// 総当たりでパスワードを試されないよう、メールアドレスごととIPアドレスごとに失敗を数える
+
+import (
+	"Shittaka_back/internal/domain/auth/entities"
+	"Shittaka_back/internal/domain/auth/repositories"
+	"Shittaka_back/internal/domain/shared"
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// LockoutPolicy はログインを制限する方針
+type LockoutPolicy struct {
+	// FreeAttempts は待ち時間なしで失敗できる回数
+	FreeAttempts int
+
+	// BackoffBase は FreeAttempts を超えた最初の失敗の後の待ち時間（以降は失敗ごとに倍になる）
+	BackoffBase time.Duration
+
+	// BackoffMax は待ち時間の上限
+	BackoffMax time.Duration
+
+	// LockoutThreshold はメールアドレスごとにロックするまでの失敗の回数
+	LockoutThreshold int
+
+	// IPLockoutThreshold はIPアドレスごとにロックするまでの失敗の回数
+	IPLockoutThreshold int
+
+	// LockoutDuration はロックする時間（最後の失敗からこの時間が過ぎると失敗の回数も忘れる）
+	LockoutDuration time.Duration
+}
+
+// LoginThrottle はログインの失敗を記録し、続いた場合にログインを制限する
+type LoginThrottle struct {
+	attemptRepo repositories.LoginAttemptRepository
+	policy      LockoutPolicy
+	now         func() time.Time
+}
+
+// NewLoginThrottle は新しいLoginThrottleを作成
+func NewLoginThrottle(attemptRepo repositories.LoginAttemptRepository, policy LockoutPolicy) *LoginThrottle {
+	return &LoginThrottle{
+		attemptRepo: attemptRepo,
+		policy:      policy,
+		now:         time.Now,
+	}
+}
+
+// throttleKey は失敗を数えるキーと、ロックするまでの回数
+type throttleKey struct {
+	key       string
+	threshold int
+}
+
+// keys はメールアドレスとIPアドレスのキーを返す（IPアドレスが不明なら数えない）
+func (t *LoginThrottle) keys(email, clientIP string) []throttleKey {
+	keys := []throttleKey{{key: "email:" + strings.ToLower(strings.TrimSpace(email)), threshold: t.policy.LockoutThreshold}}
+	if clientIP != "" {
+		keys = append(keys, throttleKey{key: "ip:" + clientIP, threshold: t.policy.IPLockoutThreshold})
+	}
+	return keys
+}
+
+// Check はログインを試せるかを確認し、制限中なら ACCOUNT_LOCKED を返す
+func (t *LoginThrottle) Check(ctx context.Context, email, clientIP string) error {
+	now := t.now()
+	var lockedUntil time.Time
+	for _, k := range t.keys(email, clientIP) {
+		attempt, err := t.attemptRepo.Find(ctx, k.key)
+		if err != nil {
+			return fmt.Errorf("failed to read login attempts: %w", err)
+		}
+		if attempt == nil {
+			continue
+		}
+		if until := t.lockedUntil(attempt, k.threshold); now.Before(until) && until.After(lockedUntil) {
+			lockedUntil = until
+		}
+	}
+
+	if lockedUntil.IsZero() {
+		return nil
+	}
+	return shared.NewDomainError("ACCOUNT_LOCKED").
+		With("unlock_at", lockedUntil).
+		With("retry_after", int(math.Ceil(lockedUntil.Sub(now).Seconds())))
+}
+
+// Fail はログインの失敗を記録する（次にログインできる日時は失敗の回数から決まる）
+// 同時に失敗したリクエストの回数も取りこぼさないよう、リポジトリで不可分に数える
+// 最後の失敗から LockoutDuration が過ぎていれば数え直す
+func (t *LoginThrottle) Fail(ctx context.Context, email, clientIP string) error {
+	now := t.now()
+	for _, k := range t.keys(email, clientIP) {
+		if _, err := t.attemptRepo.Increment(ctx, k.key, now, t.policy.LockoutDuration); err != nil {
+			return fmt.Errorf("failed to record login failure: %w", err)
+		}
+	}
+	return nil
+}
+
+// Succeed はログインの成功によりメールアドレスの失敗の記録を消す
+// IPアドレスの記録は、同じIPアドレスから別のアカウントを試し続けられないよう残す
+func (t *LoginThrottle) Succeed(ctx context.Context, email string) error {
+	return t.attemptRepo.Delete(ctx, t.keys(email, "")[0].key)
+}
+
+// lockedUntil は記録からログインできるようになる日時を返す
+func (t *LoginThrottle) lockedUntil(attempt *entities.LoginAttempt, threshold int) time.Time {
+	return attempt.LastFailedAt.Add(t.delay(attempt.Failures, threshold))
+}
+
+// delay は失敗の回数に応じた次のログインまでの待ち時間を返す
+func (t *LoginThrottle) delay(failures, threshold int) time.Duration {
+	switch {
+	case failures >= threshold:
+		return t.policy.LockoutDuration
+	case failures <= t.policy.FreeAttempts:
+		return 0
+	}
+
+	delay := t.policy.BackoffBase
+	for i := t.policy.FreeAttempts + 1; i < failures && delay < t.policy.BackoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, t.policy.BackoffMax)
+}
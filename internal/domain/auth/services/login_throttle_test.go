@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"Shittaka_back/internal/domain/auth/entities"
+	"Shittaka_back/internal/domain/shared"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLoginAttemptRepository はテスト用のメモリ上の LoginAttemptRepository（期限は記録を消さずに数え直すことで扱う）
+type fakeLoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]entities.LoginAttempt
+}
+
+func newFakeLoginAttemptRepository() *fakeLoginAttemptRepository {
+	return &fakeLoginAttemptRepository{attempts: make(map[string]entities.LoginAttempt)}
+}
+
+func (r *fakeLoginAttemptRepository) Find(ctx context.Context, key string) (*entities.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+	return &attempt, nil
+}
+
+func (r *fakeLoginAttemptRepository) Increment(ctx context.Context, key string, now time.Time, window time.Duration) (*entities.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok || now.Sub(attempt.LastFailedAt) > window {
+		attempt = entities.LoginAttempt{}
+	}
+	attempt.Failures++
+	attempt.LastFailedAt = now
+	r.attempts[key] = attempt
+	return &attempt, nil
+}
+
+func (r *fakeLoginAttemptRepository) Delete(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+	return nil
+}
+
+var testLockoutPolicy = LockoutPolicy{
+	FreeAttempts:       3,
+	BackoffBase:        time.Second,
+	BackoffMax:         time.Minute,
+	LockoutThreshold:   10,
+	IPLockoutThreshold: 50,
+	LockoutDuration:    15 * time.Minute,
+}
+
+// newTestThrottle は時刻を進められる LoginThrottle を作成
+func newTestThrottle(repo *fakeLoginAttemptRepository) (*LoginThrottle, *time.Time) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	throttle := NewLoginThrottle(repo, testLockoutPolicy)
+	throttle.now = func() time.Time { return now }
+	return throttle, &now
+}
+
+// lockedFor は Check の結果から再試行までの秒数を取り出す（制限されていなければ 0）
+func lockedFor(t *testing.T, err error) int {
+	t.Helper()
+	if err == nil {
+		return 0
+	}
+	var domainErr shared.DomainError
+	require.True(t, errors.As(err, &domainErr))
+	require.Equal(t, "ACCOUNT_LOCKED", domainErr.Code)
+	return domainErr.Params["retry_after"].(int)
+}
+
+func TestLoginThrottle_Backoff(t *testing.T) {
+	ctx := context.Background()
+	throttle, _ := newTestThrottle(newFakeLoginAttemptRepository())
+
+	// 失敗の回数ごとの次のログインまでの待ち時間（秒）
+	want := []int{0, 0, 0, 1, 2, 4, 8, 16, 32, 900}
+	for i, seconds := range want {
+		require.NoError(t, throttle.Fail(ctx, "User@Example.com", ""))
+		assert.Equal(t, seconds, lockedFor(t, throttle.Check(ctx, "user@example.com", "")), "失敗%d回目", i+1)
+	}
+}
+
+func TestLoginThrottle_BackoffMax(t *testing.T) {
+	policy := testLockoutPolicy
+	policy.LockoutThreshold = 100
+	throttle := NewLoginThrottle(newFakeLoginAttemptRepository(), policy)
+
+	assert.Equal(t, time.Minute, throttle.delay(20, policy.LockoutThreshold))
+}
+
+func TestLoginThrottle_UnlocksAfterDelay(t *testing.T) {
+	ctx := context.Background()
+	throttle, now := newTestThrottle(newFakeLoginAttemptRepository())
+
+	for range 4 {
+		require.NoError(t, throttle.Fail(ctx, "user@example.com", ""))
+	}
+
+	err := throttle.Check(ctx, "user@example.com", "")
+	var domainErr shared.DomainError
+	require.True(t, errors.As(err, &domainErr))
+	assert.Equal(t, now.Add(time.Second), domainErr.Params["unlock_at"])
+
+	*now = now.Add(time.Second)
+	assert.NoError(t, throttle.Check(ctx, "user@example.com", ""))
+}
+
+func TestLoginThrottle_ForgetsOldFailures(t *testing.T) {
+	ctx := context.Background()
+	throttle, now := newTestThrottle(newFakeLoginAttemptRepository())
+
+	for range 3 {
+		require.NoError(t, throttle.Fail(ctx, "user@example.com", ""))
+	}
+
+	// 最後の失敗から LockoutDuration を過ぎると数え直す
+	*now = now.Add(16 * time.Minute)
+	require.NoError(t, throttle.Fail(ctx, "user@example.com", ""))
+	assert.NoError(t, throttle.Check(ctx, "user@example.com", ""))
+}
+
+func TestLoginThrottle_SucceedResetsEmailOnly(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeLoginAttemptRepository()
+	throttle, _ := newTestThrottle(repo)
+
+	for range 4 {
+		require.NoError(t, throttle.Fail(ctx, "user@example.com", "192.0.2.1"))
+	}
+	require.NoError(t, throttle.Succeed(ctx, "user@example.com"))
+
+	assert.NotContains(t, repo.attempts, "email:user@example.com")
+	assert.Equal(t, 4, repo.attempts["ip:192.0.2.1"].Failures)
+}
+
+func TestLoginThrottle_IPLockout(t *testing.T) {
+	ctx := context.Background()
+	throttle, _ := newTestThrottle(newFakeLoginAttemptRepository())
+
+	// 同じIPアドレスから別々のアカウントを試し続けるとIPアドレスごとロックされる
+	for i := range testLockoutPolicy.IPLockoutThreshold {
+		require.NoError(t, throttle.Fail(ctx, fmt.Sprintf("user%d@example.com", i), "192.0.2.1"))
+	}
+
+	assert.Equal(t, 900, lockedFor(t, throttle.Check(ctx, "new@example.com", "192.0.2.1")))
+	assert.NoError(t, throttle.Check(ctx, "new@example.com", "192.0.2.2"))
+}
+
+func TestLoginThrottle_ConcurrentFailuresLock(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeLoginAttemptRepository()
+	throttle, _ := newTestThrottle(repo)
+
+	// 同時に失敗しても全ての回数を数え、LockoutThreshold に達したらロックする
+	var wg sync.WaitGroup
+	for range testLockoutPolicy.LockoutThreshold {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, throttle.Fail(ctx, "user@example.com", "192.0.2.1"))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, testLockoutPolicy.LockoutThreshold, repo.attempts["email:user@example.com"].Failures)
+	assert.Equal(t, 900, lockedFor(t, throttle.Check(ctx, "user@example.com", "192.0.2.2")))
+}
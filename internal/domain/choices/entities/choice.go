@@ -1,8 +1,33 @@
 package entities
 
+import "sort"
+
 type Choice struct {
 	ID         int64  `json:"id"`          // 選択肢ID (PK)
 	QuestionID int64  `json:"question_id"` // 紐づく問題のID (FK -> questions.id)
 	Text       string `json:"text"`        // 選択肢の本文
 	IsCorrect  bool   `json:"is_correct"`  // 正解かどうか
+	Position   int    `json:"position"`    // 表示順（1始まり、0は未設定）
+}
+
+// SortByPosition は選択肢を表示順（同じ場合はID順）に並べ替える
+func SortByPosition(choices []Choice) {
+	sort.SliceStable(choices, func(i, j int) bool {
+		if choices[i].Position != choices[j].Position {
+			return choices[i].Position < choices[j].Position
+		}
+		return choices[i].ID < choices[j].ID
+	})
+}
+
+// ChoiceSetDiff は問題の選択肢一式を置き換えるときの差分
+type ChoiceSetDiff struct {
+	Inserts []Choice // 新しく追加する選択肢（IDは未設定）
+	Updates []Choice // 内容または表示順が変わる既存の選択肢
+	Deletes []int64  // 削除する選択肢のID
+}
+
+// IsEmpty は変更がない場合に true を返す
+func (d ChoiceSetDiff) IsEmpty() bool {
+	return len(d.Inserts) == 0 && len(d.Updates) == 0 && len(d.Deletes) == 0
 }
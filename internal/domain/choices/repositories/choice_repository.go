@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	entities "Shittaka_back/internal/domain/choices/entities"
+	"Shittaka_back/internal/domain/shared"
 
 	"github.com/nedpals/supabase-go"
 )
@@ -13,10 +14,13 @@ import (
 // Service層から利用され、DB操作の抽象化を担当する
 type ChoiceRepository interface {
 	GetByQuestionID(ctx context.Context, questionID int64) ([]entities.Choice, error)                  // 問題IDに紐づく選択肢を取得
+	GetByID(ctx context.Context, id int64) (*entities.Choice, error)                                   // IDで選択肢を取得（なければ NOT_FOUND）
 	Create(ctx context.Context, choice entities.Choice) (*entities.Choice, error)                      // 新しい選択肢を作成
 	CreateWithAuth(ctx context.Context, choice entities.Choice, userToken string) (*entities.Choice, error) // 認証付きで新しい選択肢を作成
-	Update(ctx context.Context, choice entities.Choice) (*entities.Choice, error)                      // 既存の選択肢を更新
-	Delete(ctx context.Context, id int64) error                                                        // 選択肢を削除
+	Update(ctx context.Context, choice entities.Choice) (*entities.Choice, error)                      // choice.QuestionID の問題の選択肢を更新（なければ NOT_FOUND）
+	Delete(ctx context.Context, questionID, id int64) error                                            // 問題の選択肢を削除
+	Reorder(ctx context.Context, questionID int64, choiceIDs []int64, userToken string) ([]entities.Choice, error) // choiceIDs の順に表示順を1トランザクションで振り直し、並べ替え後の選択肢を取得
+	ReplaceAll(ctx context.Context, questionID int64, diff entities.ChoiceSetDiff, userToken string) ([]entities.Choice, error) // 差分を1トランザクションで適用し、置き換え後の選択肢を取得
 }
 
 // choiceRepository は ChoiceRepository インターフェースの実装
@@ -39,9 +43,26 @@ func (r *choiceRepository) GetByQuestionID(ctx context.Context, questionID int64
 	if err != nil {
 		return nil, err
 	}
+	entities.SortByPosition(choices) // 表示順に並べ替え
 	return choices, nil
 }
 
+// GetByID は指定された ID の選択肢を DB から取得
+func (r *choiceRepository) GetByID(ctx context.Context, id int64) (*entities.Choice, error) {
+	var choices []entities.Choice
+	err := r.client.DB.From("choices").
+		Select("*").
+		Eq("id", strconv.FormatInt(id, 10)).
+		Execute(&choices)
+	if err != nil {
+		return nil, err
+	}
+	if len(choices) == 0 {
+		return nil, shared.NewDomainError("NOT_FOUND").With("resource", "choice")
+	}
+	return &choices[0], nil
+}
+
 // Create は新しい選択肢を DB に追加
 func (r *choiceRepository) Create(ctx context.Context, choice entities.Choice) (*entities.Choice, error) {
 	var inserted []entities.Choice
@@ -66,17 +87,82 @@ func (r *choiceRepository) Update(ctx context.Context, choice entities.Choice) (
 	err := r.client.DB.From("choices").
 		Update(choice).
 		Eq("id", strconv.FormatInt(choice.ID, 10)). // int64 → string
-		Execute(&updated)                           // ← Execute にポインタを渡す
+		Eq("question_id", strconv.FormatInt(choice.QuestionID, 10)).
+		Execute(&updated) // ← Execute にポインタを渡す
 	if err != nil {
 		return nil, err
 	}
+	if len(updated) == 0 {
+		return nil, shared.NewDomainError("NOT_FOUND").With("resource", "choice")
+	}
 	return &updated[0], nil
 }
 
-// Delete は選択肢を ID 指定で削除
-func (r *choiceRepository) Delete(ctx context.Context, id int64) error {
+// Delete は問題の選択肢を ID 指定で削除
+func (r *choiceRepository) Delete(ctx context.Context, questionID, id int64) error {
 	return r.client.DB.From("choices").
 		Delete().
 		Eq("id", strconv.FormatInt(id, 10)). // ID を条件に削除
+		Eq("question_id", strconv.FormatInt(questionID, 10)).
 		Execute(nil)
 }
+
+// Reorder は reorder_question_choices 関数で表示順を1トランザクションとして振り直す
+func (r *choiceRepository) Reorder(ctx context.Context, questionID int64, choiceIDs []int64, userToken string) ([]entities.Choice, error) {
+	var choices []entities.Choice
+	err := r.client.DB.Rpc("reorder_question_choices", map[string]interface{}{
+		"p_question_id": questionID,
+		"p_choice_ids":  choiceIDs,
+	}).ExecuteWithContext(ctx, &choices) // 並べ替え後の選択肢一覧が返る
+	if err != nil {
+		return nil, err
+	}
+	entities.SortByPosition(choices)
+	return choices, nil
+}
+
+// ReplaceAll は replace_question_choices 関数で差分を1トランザクションとして適用
+func (r *choiceRepository) ReplaceAll(ctx context.Context, questionID int64, diff entities.ChoiceSetDiff, userToken string) ([]entities.Choice, error) {
+	var choices []entities.Choice
+	err := r.client.DB.Rpc("replace_question_choices", replaceChoicesParams(questionID, diff)).
+		ExecuteWithContext(ctx, &choices) // 置き換え後の選択肢一覧が返る
+	if err != nil {
+		return nil, err
+	}
+	entities.SortByPosition(choices)
+	return choices, nil
+}
+
+// replaceChoicesParams は replace_question_choices 関数の引数を組み立てる
+func replaceChoicesParams(questionID int64, diff entities.ChoiceSetDiff) map[string]interface{} {
+	inserts := make([]map[string]interface{}, 0, len(diff.Inserts))
+	for _, choice := range diff.Inserts {
+		inserts = append(inserts, map[string]interface{}{
+			"text":       choice.Text,
+			"is_correct": choice.IsCorrect,
+			"position":   choice.Position,
+		})
+	}
+
+	updates := make([]map[string]interface{}, 0, len(diff.Updates))
+	for _, choice := range diff.Updates {
+		updates = append(updates, map[string]interface{}{
+			"id":         choice.ID,
+			"text":       choice.Text,
+			"is_correct": choice.IsCorrect,
+			"position":   choice.Position,
+		})
+	}
+
+	deletes := diff.Deletes
+	if deletes == nil {
+		deletes = []int64{}
+	}
+
+	return map[string]interface{}{
+		"p_question_id": questionID,
+		"p_inserts":     inserts,
+		"p_updates":     updates,
+		"p_deletes":     deletes,
+	}
+}
@@ -0,0 +1,123 @@
+package services
+
+// choice_diff.goは選択肢一式の置き換えに使う検証と差分計算を定義
+
+import (
+	"strings"
+
+	entities "Shittaka_back/internal/domain/choices/entities"
+	questionEntities "Shittaka_back/internal/domain/question/entities"
+	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/domain/shared/validation"
+)
+
+// MinChoicesPerQuestion は1つの問題に必要な選択肢の最小数
+const MinChoicesPerQuestion = 2
+
+// ValidateChoiceSet は置き換え後の選択肢一式が満たすべき条件を検証する
+// 選択肢は2つ以上、正解は1つ以上（single_choice なら1つだけ）、本文は空でなく、IDの重複は認めない
+func ValidateChoiceSet(questionType questionEntities.QuestionType, desired []entities.Choice) error {
+	v := validation.New()
+	v.Check(len(desired) >= MinChoicesPerQuestion, shared.NewValidationError("choices", shared.ValidationMinItems).With("min", MinChoicesPerQuestion))
+
+	correct, hasEmptyText, hasDuplicate := 0, false, false
+	seen := make(map[int64]bool, len(desired))
+	for _, choice := range desired {
+		if strings.TrimSpace(choice.Text) == "" {
+			hasEmptyText = true
+		}
+		if choice.ID != 0 {
+			if seen[choice.ID] {
+				hasDuplicate = true
+			}
+			seen[choice.ID] = true
+		}
+		if choice.IsCorrect {
+			correct++
+		}
+	}
+
+	// 同じ種類のエラーは選択肢の数にかかわらず1件だけ返す
+	v.Check(!hasEmptyText, shared.NewValidationError("choice_text", shared.ValidationRequired))
+	v.Check(!hasDuplicate, shared.NewValidationError("choices", shared.ValidationDuplicate))
+	v.Check(correct > 0, shared.NewValidationError("choices", shared.ValidationNoCorrect))
+
+	// 正解が複数あると、1つしか選べない回答ではどれを選んでも正解にならない
+	if questionType == questionEntities.QuestionTypeSingleChoice {
+		v.Check(correct <= 1, shared.NewValidationError("choices", shared.ValidationExactlyOne))
+	}
+	return v.Err()
+}
+
+// ValidateChoiceOrder は並べ替え後の選択肢IDの一覧が、問題の選択肢をちょうど1回ずつ含むかを検証する
+// 同じ種類のエラーは要素の数にかかわらず1件だけ返す
+func ValidateChoiceOrder(choices []entities.Choice, choiceIDs []int64) error {
+	known := make(map[int64]bool, len(choices))
+	for _, choice := range choices {
+		known[choice.ID] = true
+	}
+
+	hasUnknown, hasDuplicate := false, false
+	seen := make(map[int64]bool, len(choiceIDs))
+	for _, id := range choiceIDs {
+		if !known[id] {
+			hasUnknown = true
+		}
+		if seen[id] {
+			hasDuplicate = true
+		}
+		seen[id] = true
+	}
+
+	v := validation.New()
+	v.Check(len(choiceIDs) == len(choices), shared.NewValidationError("choice_ids", shared.ValidationIncomplete))
+	v.Check(!hasUnknown, shared.NewValidationError("choice_ids", shared.ValidationUnknownID))
+	v.Check(!hasDuplicate, shared.NewValidationError("choice_ids", shared.ValidationDuplicate))
+	return v.Err()
+}
+
+// DiffChoices は現在の選択肢と置き換え後の選択肢一式から差分を計算する
+// desired の並び順がそのまま表示順（1始まり）になる
+// IDが0の選択肢は追加、既存IDは変更があれば更新、desired に含まれない既存の選択肢は削除となる
+func DiffChoices(questionID int64, existing, desired []entities.Choice) (entities.ChoiceSetDiff, error) {
+	var diff entities.ChoiceSetDiff
+
+	current := make(map[int64]entities.Choice, len(existing))
+	for _, choice := range existing {
+		current[choice.ID] = choice
+	}
+
+	kept := make(map[int64]bool, len(desired))
+	for i, choice := range desired {
+		next := entities.Choice{
+			ID:         choice.ID,
+			QuestionID: questionID,
+			Text:       strings.TrimSpace(choice.Text),
+			IsCorrect:  choice.IsCorrect,
+			Position:   i + 1,
+		}
+
+		if next.ID == 0 {
+			diff.Inserts = append(diff.Inserts, next)
+			continue
+		}
+
+		old, ok := current[next.ID]
+		if !ok {
+			return entities.ChoiceSetDiff{}, shared.NewValidationError("choices", shared.ValidationUnknownID)
+		}
+		kept[next.ID] = true
+
+		if old.Text != next.Text || old.IsCorrect != next.IsCorrect || old.Position != next.Position {
+			diff.Updates = append(diff.Updates, next)
+		}
+	}
+
+	for _, choice := range existing {
+		if !kept[choice.ID] {
+			diff.Deletes = append(diff.Deletes, choice.ID)
+		}
+	}
+
+	return diff, nil
+}
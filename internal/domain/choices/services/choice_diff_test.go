@@ -0,0 +1,150 @@
+package services
+
+import (
+	"testing"
+
+	entities "Shittaka_back/internal/domain/choices/entities"
+	questionEntities "Shittaka_back/internal/domain/question/entities"
+	"Shittaka_back/internal/domain/shared"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffChoices(t *testing.T) {
+	existing := []entities.Choice{
+		{ID: 1, QuestionID: 10, Text: "A", IsCorrect: true, Position: 1},
+		{ID: 2, QuestionID: 10, Text: "B", Position: 2},
+		{ID: 3, QuestionID: 10, Text: "C", Position: 3},
+	}
+
+	t.Run("追加・更新・削除をまとめて計算する", func(t *testing.T) {
+		desired := []entities.Choice{
+			{ID: 1, Text: "A", IsCorrect: true},
+			{ID: 3, Text: "C（修正）"},
+			{Text: "D"},
+		}
+
+		diff, err := DiffChoices(10, existing, desired)
+		require.NoError(t, err)
+
+		assert.Equal(t, []entities.Choice{{QuestionID: 10, Text: "D", Position: 3}}, diff.Inserts)
+		assert.Equal(t, []entities.Choice{{ID: 3, QuestionID: 10, Text: "C（修正）", Position: 2}}, diff.Updates)
+		assert.Equal(t, []int64{2}, diff.Deletes)
+	})
+
+	t.Run("変更がなければ差分は空", func(t *testing.T) {
+		desired := []entities.Choice{
+			{ID: 1, Text: "A", IsCorrect: true},
+			{ID: 2, Text: "B"},
+			{ID: 3, Text: "C"},
+		}
+
+		diff, err := DiffChoices(10, existing, desired)
+		require.NoError(t, err)
+		assert.True(t, diff.IsEmpty())
+	})
+
+	t.Run("並び替えだけでも表示順が更新される", func(t *testing.T) {
+		desired := []entities.Choice{
+			{ID: 2, Text: "B"},
+			{ID: 1, Text: "A", IsCorrect: true},
+			{ID: 3, Text: "C"},
+		}
+
+		diff, err := DiffChoices(10, existing, desired)
+		require.NoError(t, err)
+		assert.Len(t, diff.Updates, 2)
+		assert.Empty(t, diff.Inserts)
+		assert.Empty(t, diff.Deletes)
+	})
+
+	t.Run("他の問題の選択肢IDはエラー", func(t *testing.T) {
+		desired := []entities.Choice{
+			{ID: 1, Text: "A", IsCorrect: true},
+			{ID: 99, Text: "X"},
+		}
+
+		_, err := DiffChoices(10, existing, desired)
+		assert.IsType(t, shared.ValidationError{}, err)
+	})
+}
+
+func TestValidateChoiceSet(t *testing.T) {
+	tests := []struct {
+		name         string
+		questionType questionEntities.QuestionType
+		choices      []entities.Choice
+		wantErr      bool
+	}{
+		{
+			name:    "正常",
+			choices: []entities.Choice{{Text: "A", IsCorrect: true}, {Text: "B"}},
+		},
+		{
+			name:         "複数選択なら正解は複数でもよい",
+			questionType: questionEntities.QuestionTypeMultiSelect,
+			choices:      []entities.Choice{{Text: "A", IsCorrect: true}, {Text: "B", IsCorrect: true}},
+		},
+		{
+			name:         "単一選択で正解が複数",
+			questionType: questionEntities.QuestionTypeSingleChoice,
+			choices:      []entities.Choice{{Text: "A", IsCorrect: true}, {Text: "B", IsCorrect: true}},
+			wantErr:      true,
+		},
+		{
+			name:    "選択肢が1つだけ",
+			choices: []entities.Choice{{Text: "A", IsCorrect: true}},
+			wantErr: true,
+		},
+		{
+			name:    "正解がない",
+			choices: []entities.Choice{{Text: "A"}, {Text: "B"}},
+			wantErr: true,
+		},
+		{
+			name:    "本文が空",
+			choices: []entities.Choice{{Text: "A", IsCorrect: true}, {Text: "  "}},
+			wantErr: true,
+		},
+		{
+			name:    "IDが重複",
+			choices: []entities.Choice{{ID: 1, Text: "A", IsCorrect: true}, {ID: 1, Text: "B"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			questionType := tt.questionType
+			if questionType == "" {
+				questionType = questionEntities.QuestionTypeSingleChoice
+			}
+			err := ValidateChoiceSet(questionType, tt.choices)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateChoiceOrder(t *testing.T) {
+	choices := []entities.Choice{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	assert.NoError(t, ValidateChoiceOrder(choices, []int64{3, 1, 2}))
+
+	t.Run("全てのエラーをまとめて返す", func(t *testing.T) {
+		err := ValidateChoiceOrder(choices, []int64{1, 1, 9, 2})
+
+		var errs shared.ValidationErrors
+		require.ErrorAs(t, err, &errs)
+		codes := make([]string, len(errs))
+		for i, e := range errs {
+			assert.Equal(t, "choice_ids", e.Field)
+			codes[i] = e.Code
+		}
+		assert.Equal(t, []string{shared.ValidationIncomplete, shared.ValidationUnknownID, shared.ValidationDuplicate}, codes)
+	})
+}
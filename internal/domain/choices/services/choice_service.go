@@ -5,40 +5,163 @@ import (
 
 	entities "Shittaka_back/internal/domain/choices/entities"
 	"Shittaka_back/internal/domain/choices/repositories"
+	questionRepositories "Shittaka_back/internal/domain/question/repositories"
+	"Shittaka_back/internal/domain/shared"
 )
 
 // ChoiceService はユースケース層のサービス
 // Repository を利用してアプリケーションの処理をまとめる
 type ChoiceService struct {
-	repo repositories.ChoiceRepository
+	repo         repositories.ChoiceRepository
+	questionRepo questionRepositories.QuestionRepository
 }
 
 // NewChoiceService は ChoiceService のコンストラクタ
-func NewChoiceService(repo repositories.ChoiceRepository) *ChoiceService {
-	return &ChoiceService{repo: repo}
+func NewChoiceService(repo repositories.ChoiceRepository, questionRepo questionRepositories.QuestionRepository) *ChoiceService {
+	return &ChoiceService{repo: repo, questionRepo: questionRepo}
 }
 
-// GetChoices は問題IDに紐づく選択肢を取得
+// GetChoices は問題IDに紐づく選択肢を表示順で取得
 func (s *ChoiceService) GetChoices(ctx context.Context, questionID int64) ([]entities.Choice, error) {
 	return s.repo.GetByQuestionID(ctx, questionID)
 }
 
+// GetChoicesForUser は利用者に表示する順序で選択肢を取得
+// 問題でシャッフルが有効な場合は利用者ごとに固定された順序に並べ替える
+func (s *ChoiceService) GetChoicesForUser(ctx context.Context, questionID int64, userID string) ([]entities.Choice, error) {
+	choices, err := s.repo.GetByQuestionID(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	question, err := s.questionRepo.GetByID(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if question.ShuffleChoices {
+		return ShuffleChoices(choices, userID, questionID), nil
+	}
+
+	return choices, nil
+}
+
 // CreateChoice は新しい選択肢を作成
 func (s *ChoiceService) CreateChoice(ctx context.Context, choice entities.Choice) (*entities.Choice, error) {
 	return s.repo.Create(ctx, choice)
 }
 
 // CreateChoiceWithAuth は認証付きで新しい選択肢を作成
+// 表示順が指定されていない場合は末尾に追加する
 func (s *ChoiceService) CreateChoiceWithAuth(ctx context.Context, choice entities.Choice, userToken string) (*entities.Choice, error) {
+	if choice.Position == 0 {
+		existing, err := s.repo.GetByQuestionID(ctx, choice.QuestionID)
+		if err != nil {
+			return nil, err
+		}
+		choice.Position = nextPosition(existing)
+	}
 	return s.repo.CreateWithAuth(ctx, choice, userToken)
 }
 
 // UpdateChoice は既存の選択肢を更新
+// choice.QuestionID が指定されていれば、その問題の選択肢でない場合は NOT_FOUND を返す（別の問題に移動させない）
 func (s *ChoiceService) UpdateChoice(ctx context.Context, choice entities.Choice) (*entities.Choice, error) {
+	existing, err := s.findInQuestion(ctx, choice.QuestionID, choice.ID)
+	if err != nil {
+		return nil, err
+	}
+	choice.QuestionID = existing.QuestionID
 	return s.repo.Update(ctx, choice)
 }
 
 // DeleteChoice は選択肢を削除
-func (s *ChoiceService) DeleteChoice(ctx context.Context, id int64) error {
-	return s.repo.Delete(ctx, id)
+// questionID が指定されていれば（0 でなければ）、その問題の選択肢でない場合は NOT_FOUND を返す
+func (s *ChoiceService) DeleteChoice(ctx context.Context, questionID, id int64) error {
+	existing, err := s.findInQuestion(ctx, questionID, id)
+	if err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, existing.QuestionID, id)
+}
+
+// findInQuestion は選択肢を取得し、questionID が指定されていればその問題の選択肢かを確認する
+func (s *ChoiceService) findInQuestion(ctx context.Context, questionID, id int64) (*entities.Choice, error) {
+	choice, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if questionID != 0 && choice.QuestionID != questionID {
+		return nil, shared.NewDomainError("NOT_FOUND").With("resource", "choice")
+	}
+	return choice, nil
+}
+
+// ReorderChoices は問題の選択肢を指定された順序に並べ替える（問題の作成者のみ）
+// choiceIDs には問題の全ての選択肢を重複なく指定する必要がある
+func (s *ChoiceService) ReorderChoices(ctx context.Context, questionID int64, choiceIDs []int64, userID string, userToken string) ([]entities.Choice, error) {
+	question, err := s.questionRepo.GetByID(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+	if question.UserID != userID {
+		return nil, shared.NewDomainError("FORBIDDEN")
+	}
+
+	choices, err := s.repo.GetByQuestionID(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateChoiceOrder(choices, choiceIDs); err != nil {
+		return nil, err
+	}
+
+	// 全ての表示順を1回の呼び出し（1トランザクション）で振り直す
+	return s.repo.Reorder(ctx, questionID, choiceIDs, userToken)
+}
+
+// ReplaceChoices は問題の選択肢一式を置き換える（問題の作成者のみ）
+// desired の並び順が表示順になり、IDのない要素は追加、含まれない既存の選択肢は削除される
+func (s *ChoiceService) ReplaceChoices(ctx context.Context, questionID int64, desired []entities.Choice, userID string, userToken string) ([]entities.Choice, error) {
+	question, err := s.questionRepo.GetByID(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+	if question.UserID != userID {
+		return nil, shared.NewDomainError("FORBIDDEN")
+	}
+	if !question.Type.UsesChoices() {
+		return nil, shared.NewValidationError("choices", shared.ValidationNotApplicable)
+	}
+
+	if err := ValidateChoiceSet(question.Type, desired); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetByQuestionID(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := DiffChoices(questionID, existing, desired)
+	if err != nil {
+		return nil, err
+	}
+	if diff.IsEmpty() {
+		return existing, nil
+	}
+
+	return s.repo.ReplaceAll(ctx, questionID, diff, userToken)
+}
+
+// nextPosition は末尾に追加する選択肢の表示順を返す
+func nextPosition(choices []entities.Choice) int {
+	max := 0
+	for _, choice := range choices {
+		if choice.Position > max {
+			max = choice.Position
+		}
+	}
+	return max + 1
 }
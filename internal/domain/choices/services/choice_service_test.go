@@ -8,10 +8,14 @@ import (
 
 	entities "Shittaka_back/internal/domain/choices/entities"
 	"Shittaka_back/internal/domain/choices/repositories"
+	questionEntities "Shittaka_back/internal/domain/question/entities"
+	questionRepositories "Shittaka_back/internal/domain/question/repositories"
+	"Shittaka_back/internal/domain/shared"
 
 	"github.com/joho/godotenv"
 	"github.com/nedpals/supabase-go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestChoiceService_CRUD(t *testing.T) {
@@ -31,7 +35,7 @@ func TestChoiceService_CRUD(t *testing.T) {
 
 	// リポジトリとサービス作成
 	repo := repositories.NewChoiceRepository(client)
-	service := NewChoiceService(repo)
+	service := NewChoiceService(repo, nil) // 問題リポジトリはCRUDでは使用しない
 
 	ctx := context.Background()
 
@@ -69,7 +73,124 @@ func TestChoiceService_CRUD(t *testing.T) {
 	// ---------------------------
 	// 4. Delete
 	// ---------------------------
-	err = service.DeleteChoice(ctx, int64(updated.ID))
+	err = service.DeleteChoice(ctx, int64(updated.QuestionID), int64(updated.ID))
 	assert.NoError(t, err)
 	t.Logf("Deleted Choice ID: %d", updated.ID)
 }
+
+// fakeChoiceRepository はテスト用のメモリ上の ChoiceRepository（取得・更新・削除のみ）
+type fakeChoiceRepository struct {
+	repositories.ChoiceRepository
+	choices  map[int64]entities.Choice
+	reorders int
+}
+
+func (r *fakeChoiceRepository) GetByID(ctx context.Context, id int64) (*entities.Choice, error) {
+	choice, ok := r.choices[id]
+	if !ok {
+		return nil, shared.NewDomainError("NOT_FOUND").With("resource", "choice")
+	}
+	return &choice, nil
+}
+
+func (r *fakeChoiceRepository) Update(ctx context.Context, choice entities.Choice) (*entities.Choice, error) {
+	r.choices[choice.ID] = choice
+	return &choice, nil
+}
+
+func (r *fakeChoiceRepository) Delete(ctx context.Context, questionID, id int64) error {
+	delete(r.choices, id)
+	return nil
+}
+
+func (r *fakeChoiceRepository) GetByQuestionID(ctx context.Context, questionID int64) ([]entities.Choice, error) {
+	var choices []entities.Choice
+	for _, choice := range r.choices {
+		if choice.QuestionID == questionID {
+			choices = append(choices, choice)
+		}
+	}
+	entities.SortByPosition(choices)
+	return choices, nil
+}
+
+func (r *fakeChoiceRepository) Reorder(ctx context.Context, questionID int64, choiceIDs []int64, userToken string) ([]entities.Choice, error) {
+	r.reorders++
+	for i, id := range choiceIDs {
+		choice := r.choices[id]
+		choice.Position = i + 1
+		r.choices[id] = choice
+	}
+	return r.GetByQuestionID(ctx, questionID)
+}
+
+// fakeQuestionRepository は作成者だけを持つ問題を返す QuestionRepository
+type fakeQuestionRepository struct {
+	questionRepositories.QuestionRepository
+	userID string
+}
+
+func (r *fakeQuestionRepository) GetByID(ctx context.Context, id int64) (*questionEntities.Question, error) {
+	return &questionEntities.Question{ID: id, UserID: r.userID}, nil
+}
+
+func TestChoiceService_ScopesToQuestion(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeChoiceRepository{choices: map[int64]entities.Choice{
+		999: {ID: 999, QuestionID: 2, Text: "東京", Position: 1},
+	}}
+	service := NewChoiceService(repo, nil)
+
+	assertNotFound := func(t *testing.T, err error) {
+		t.Helper()
+		var domainErr shared.DomainError
+		require.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, "NOT_FOUND", domainErr.Code)
+	}
+
+	t.Run("別の問題の選択肢は削除できない", func(t *testing.T) {
+		assertNotFound(t, service.DeleteChoice(ctx, 1, 999))
+		assert.Contains(t, repo.choices, int64(999))
+	})
+
+	t.Run("別の問題の選択肢は更新できない（移動させない）", func(t *testing.T) {
+		_, err := service.UpdateChoice(ctx, entities.Choice{ID: 999, QuestionID: 1, Text: "大阪"})
+		assertNotFound(t, err)
+		assert.Equal(t, "東京", repo.choices[999].Text)
+	})
+
+	t.Run("問題IDを指定しなければ選択肢の問題のまま更新する", func(t *testing.T) {
+		updated, err := service.UpdateChoice(ctx, entities.Choice{ID: 999, Text: "大阪"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), updated.QuestionID)
+	})
+
+	t.Run("同じ問題の選択肢は削除できる", func(t *testing.T) {
+		require.NoError(t, service.DeleteChoice(ctx, 2, 999))
+		assert.NotContains(t, repo.choices, int64(999))
+	})
+}
+
+func TestChoiceService_ReorderChoices(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeChoiceRepository{choices: map[int64]entities.Choice{
+		1: {ID: 1, QuestionID: 1, Text: "A", Position: 1},
+		2: {ID: 2, QuestionID: 1, Text: "B", Position: 2},
+		3: {ID: 3, QuestionID: 1, Text: "C", Position: 3},
+	}}
+	service := NewChoiceService(repo, &fakeQuestionRepository{userID: "owner"})
+
+	t.Run("重複したIDは並べ替えない", func(t *testing.T) {
+		_, err := service.ReorderChoices(ctx, 1, []int64{1, 1, 2}, "owner", "token")
+		var validationErr shared.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, 0, repo.reorders)
+	})
+
+	t.Run("全ての表示順を1回の呼び出しで振り直す", func(t *testing.T) {
+		choices, err := service.ReorderChoices(ctx, 1, []int64{3, 1, 2}, "owner", "token")
+		require.NoError(t, err)
+		assert.Equal(t, 1, repo.reorders)
+		assert.Equal(t, []string{"C", "A", "B"}, []string{choices[0].Text, choices[1].Text, choices[2].Text})
+	})
+}
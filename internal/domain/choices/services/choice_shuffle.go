@@ -0,0 +1,34 @@
+package services
+
+// choice_shuffle.goは選択肢の決定的なシャッフルを定義
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"strconv"
+
+	entities "Shittaka_back/internal/domain/choices/entities"
+)
+
+// ShuffleChoices は利用者と問題の組み合わせをシードに選択肢を並べ替える
+// 同じ利用者が同じ問題を再読み込みしても順序は変わらない
+func ShuffleChoices(choices []entities.Choice, userID string, questionID int64) []entities.Choice {
+	shuffled := make([]entities.Choice, len(choices))
+	copy(shuffled, choices)
+
+	// 入力順に依存しないよう、表示順に並べてからシャッフルする
+	entities.SortByPosition(shuffled)
+
+	h := fnv.New64a()
+	h.Write([]byte(userID))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.FormatInt(questionID, 10)))
+	seed := h.Sum64()
+
+	rng := rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+	rng.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	return shuffled
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"testing"
+
+	entities "Shittaka_back/internal/domain/choices/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShuffleChoices(t *testing.T) {
+	choices := []entities.Choice{
+		{ID: 1, Position: 1}, {ID: 2, Position: 2}, {ID: 3, Position: 3},
+		{ID: 4, Position: 4}, {ID: 5, Position: 5}, {ID: 6, Position: 6},
+	}
+
+	ids := func(cs []entities.Choice) []int64 {
+		result := make([]int64, len(cs))
+		for i, c := range cs {
+			result[i] = c.ID
+		}
+		return result
+	}
+
+	first := ShuffleChoices(choices, "user-a", 10)
+
+	// 同じ利用者・同じ問題なら何度呼んでも同じ順序
+	assert.Equal(t, ids(first), ids(ShuffleChoices(choices, "user-a", 10)))
+
+	// 入力の順序に依存しない
+	reversed := []entities.Choice{choices[5], choices[4], choices[3], choices[2], choices[1], choices[0]}
+	assert.Equal(t, ids(first), ids(ShuffleChoices(reversed, "user-a", 10)))
+
+	// 元のスライスは変更しない
+	assert.Equal(t, []int64{1, 2, 3, 4, 5, 6}, ids(choices))
+
+	// 全ての選択肢が含まれる
+	assert.ElementsMatch(t, ids(choices), ids(first))
+}
@@ -1,7 +1,7 @@
 package entities
 
 import (
-	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/domain/shared/validation"
 )
 
 // Profile はユーザープロフィールのドメインエンティティ
@@ -20,11 +20,8 @@ func NewProfile(id, name string) *Profile {
 
 // Validate はProfileエンティティのバリデーションを行う
 func (p *Profile) Validate() error {
-	if p.ID == "" {
-		return shared.NewValidationError("id", "id is required")
-	}
-	if p.Name == "" {
-		return shared.NewValidationError("name", "name is required")
-	}
-	return nil
+	v := validation.New()
+	v.Required("id", p.ID)
+	v.Required("name", p.Name)
+	return v.Err()
 }
\ No newline at end of file
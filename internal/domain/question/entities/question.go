@@ -2,50 +2,126 @@ package entities
 
 import (
 	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/domain/shared/validation"
 	"time"
 )
 
+// QuestionType は問題の形式を表す
+type QuestionType string
+
+const (
+	// QuestionTypeSingleChoice は正解が1つの選択問題
+	QuestionTypeSingleChoice QuestionType = "single_choice"
+	// QuestionTypeMultiSelect は正解を全て選ぶ複数選択問題
+	QuestionTypeMultiSelect QuestionType = "multi_select"
+	// QuestionTypeTrueFalse は○×問題
+	QuestionTypeTrueFalse QuestionType = "true_false"
+	// QuestionTypeFreeText は短い文章で答える記述問題
+	QuestionTypeFreeText QuestionType = "free_text"
+	// QuestionTypeNumeric は数値で答える問題（許容誤差あり）
+	QuestionTypeNumeric QuestionType = "numeric"
+)
+
+// ParseQuestionType は文字列を QuestionType に変換する（空文字は single_choice として扱う）
+func ParseQuestionType(s string) (QuestionType, bool) {
+	switch t := QuestionType(s); t {
+	case "":
+		return QuestionTypeSingleChoice, true
+	case QuestionTypeSingleChoice, QuestionTypeMultiSelect, QuestionTypeTrueFalse, QuestionTypeFreeText, QuestionTypeNumeric:
+		return t, true
+	default:
+		return "", false
+	}
+}
+
+// UsesChoices は選択肢を使って回答する形式かどうかを返す
+func (t QuestionType) UsesChoices() bool {
+	return t == QuestionTypeSingleChoice || t == QuestionTypeMultiSelect
+}
+
 // Question は問題のドメインエンティティ
 type Question struct {
-	ID             int64     `json:"id"`
-	GenreID        int64     `json:"genre_id"`
-	UserID         string    `json:"user_id"`
-	Title          string    `json:"title"`
-	Body           string    `json:"body"`
-	Explanation    string    `json:"explanation"`
-	CreatedAt      time.Time `json:"created_at"`
-	Views          int       `json:"views"`
-	CorrectCount   int       `json:"correct_count"`
-	IncorrectCount int       `json:"incorrect_count"`
+	ID             int64        `json:"id"`
+	GenreID        int64        `json:"genre_id"`
+	UserID         string       `json:"user_id"`
+	Title          string       `json:"title"`
+	Body           string       `json:"body"`
+	Explanation    string       `json:"explanation"`
+	CreatedAt      time.Time    `json:"created_at"`
+	Views          int          `json:"views"`
+	CorrectCount   int          `json:"correct_count"`
+	IncorrectCount int          `json:"incorrect_count"`
+	Type           QuestionType `json:"type"`
+
+	// PartialCredit は multi_select で部分点を与えるかどうか
+	PartialCredit bool `json:"partial_credit"`
+	// AcceptedAnswers は free_text で正解とする解答の一覧
+	AcceptedAnswers []string `json:"accepted_answers"`
+	// CorrectBoolean は true_false の正解
+	CorrectBoolean *bool `json:"correct_boolean"`
+	// NumericAnswer は numeric の正解
+	NumericAnswer *float64 `json:"numeric_answer"`
+	// NumericTolerance は numeric の許容誤差（絶対値）
+	NumericTolerance float64 `json:"numeric_tolerance"`
+
+	// ShuffleChoices は回答者ごとに選択肢の表示順をシャッフルするかどうか
+	ShuffleChoices bool `json:"shuffle_choices"`
+
+	// Version は更新のたびに1ずつ増える版（同時編集で他の変更を上書きしないために使う）
+	Version int `json:"version"`
+	// UpdatedAt は最後に更新した日時
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // NewQuestion は新しいQuestionエンティティを作成
 func NewQuestion(genreID int64, userID, title, body, explanation string) *Question {
+	now := time.Now()
 	return &Question{
 		GenreID:        genreID,
 		UserID:         userID,
 		Title:          title,
 		Body:           body,
 		Explanation:    explanation,
-		CreatedAt:      time.Now(),
+		CreatedAt:      now,
 		Views:          0,
 		CorrectCount:   0,
 		IncorrectCount: 0,
+		Type:           QuestionTypeSingleChoice,
+		Version:        1,
+		UpdatedAt:      now,
 	}
 }
 
 // Validate はQuestionエンティティのバリデーションを行う
 func (q *Question) Validate() error {
-	if q.GenreID == 0 {
-		return shared.NewValidationError("genre_id", "genre_id is required")
-	}
-	if q.UserID == "" {
-		return shared.NewValidationError("user_id", "user_id is required")
-	}
-	if q.Title == "" {
-		return shared.NewValidationError("title", "title is required")
+	v := validation.New()
+	q.ValidateInto(v)
+	return v.Err()
+}
+
+// ValidateInto は Validate と同じ検証を行い、エラーを v に記録する（リクエストの他の項目とまとめて返すため）
+func (q *Question) ValidateInto(v *validation.Validator) {
+	v.RequiredID("genre_id", q.GenreID)
+	v.Required("user_id", q.UserID)
+	v.Required("title", q.Title)
+	q.validateAnswerKey(v)
+}
+
+// validateAnswerKey は問題形式ごとに必要な正解情報が揃っているかを検証する
+func (q *Question) validateAnswerKey(v *validation.Validator) {
+	switch q.Type {
+	case QuestionTypeSingleChoice, QuestionTypeMultiSelect:
+		// 正解は選択肢側で管理する
+	case QuestionTypeTrueFalse:
+		v.Check(q.CorrectBoolean != nil, shared.NewValidationError("correct_boolean", shared.ValidationRequired))
+	case QuestionTypeFreeText:
+		v.Check(len(q.AcceptedAnswers) > 0, shared.NewValidationError("accepted_answers", shared.ValidationMinItems).With("min", 1))
+	case QuestionTypeNumeric:
+		v.Check(q.NumericAnswer != nil, shared.NewValidationError("numeric_answer", shared.ValidationRequired))
+		v.Check(q.NumericTolerance >= 0, shared.NewValidationError("numeric_tolerance", shared.ValidationNonNegative))
+	default:
+		v.Add(shared.NewValidationError("type", shared.ValidationInvalidValue))
 	}
-	return nil
 }
 
 // IncrementViews は閲覧数をインクリメント
@@ -61,4 +137,4 @@ func (q *Question) IncrementCorrectCount() {
 // IncrementIncorrectCount は不正解数をインクリメント
 func (q *Question) IncrementIncorrectCount() {
 	q.IncorrectCount++
-}
\ No newline at end of file
+}
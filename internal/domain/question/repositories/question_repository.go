@@ -1,16 +1,27 @@
 package repositories
 
 import (
-	"context"
 	"Shittaka_back/internal/domain/question/entities"
+	"context"
 )
 
 // QuestionRepository は問題リポジトリのインターフェース
 type QuestionRepository interface {
-	Create(ctx context.Context, question *entities.Question, userToken string) (*entities.Question, error)
+	// Create は問題とタグを1トランザクションで保存する
+	Create(ctx context.Context, question *entities.Question, tags []string, userToken string) (*entities.Question, error)
 	GetByID(ctx context.Context, id int64) (*entities.Question, error)
 	GetByUserID(ctx context.Context, userID string, userToken string) ([]*entities.Question, error)
-	Update(ctx context.Context, question *entities.Question, userToken string) error
+	// Update は question.Version が保存されている版と一致する場合のみ更新し、question の版と更新日時を新しい値にする
+	// tags が nil でなければタグも同じトランザクションで置き換える
+	// 版が一致しない（他の更新が先に保存された）場合は PRECONDITION_FAILED を返す
+	Update(ctx context.Context, question *entities.Question, tags []string, userToken string) error
 	Delete(ctx context.Context, id int64, userToken string) error
 	GetAll(ctx context.Context) ([]*entities.Question, error)
-}
\ No newline at end of file
+	GetByIDs(ctx context.Context, ids []int64) ([]*entities.Question, error)
+}
+
+// Invalidator は読み込みをキャッシュする QuestionRepository が実装する
+// 版が一致しなかった場合などに、キャッシュした問題を捨てて次の GetByID で読み込み直させる
+type Invalidator interface {
+	Invalidate(id int64)
+}
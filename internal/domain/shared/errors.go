@@ -2,40 +2,137 @@ package shared
 
 // errors.goはエラーを定義
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
+
+// Params はエラーメッセージに埋め込むパラメータ（{max} などのプレースホルダーに対応）
+type Params map[string]interface{}
+
+// with はキーを追加したParamsのコピーを返す（元のParamsは変更しない）
+func (p Params) with(key string, value interface{}) Params {
+	params := make(Params, len(p)+1)
+	for k, v := range p {
+		params[k] = v
+	}
+	params[key] = value
+	return params
+}
+
+// バリデーションエラーのコード
+// メッセージはプレゼンテーション層のメッセージカタログでコードから組み立てる
+const (
+	ValidationRequired      = "required"       // 必須項目が未入力
+	ValidationMinLength     = "min_length"     // 文字数が {min} 未満
+	ValidationMaxLength     = "max_length"     // 文字数が {max} を超えている
+	ValidationMinItems      = "min_items"      // 要素数が {min} 未満
+	ValidationMaxItems      = "max_items"      // 要素数が {max} を超えている
+	ValidationInvalidFormat = "invalid_format" // 形式が正しくない
+	ValidationInvalidValue  = "invalid_value"  // 許可されていない値
+	ValidationNonNegative   = "non_negative"   // 0以上である必要がある
+	ValidationDuplicate     = "duplicate"      // 値が重複している
+	ValidationUnknownID     = "unknown_id"     // 対象に属さないIDが含まれている
+	ValidationIncomplete    = "incomplete"     // 全ての要素を指定する必要がある
+	ValidationExactlyOne    = "exactly_one"    // 1つだけ指定する必要がある
+	ValidationNoChanges     = "no_changes"     // 更新する項目がない
+	ValidationNotApplicable = "not_applicable" // この問題形式では指定できない
+	ValidationNoCorrect     = "no_correct"     // 正解が1つも指定されていない
+	ValidationInvalidType   = "invalid_type"   // JSONの型が {expected} ではない
+	ValidationUnknownField  = "unknown_field"  // 定義されていない項目
+)
 
 // ValidationError はバリデーションエラーを表す
 type ValidationError struct {
-	Field   string
-	Message string
+	Field  string
+	Code   string // バリデーションエラーのコード（Validation* 定数）
+	Params Params // メッセージに埋め込むパラメータ
 }
 
 func (e ValidationError) Error() string {
-	return fmt.Sprintf("validation error on field '%s': %s", e.Field, e.Message)
+	if len(e.Params) > 0 {
+		return fmt.Sprintf("validation error on field '%s': %s %v", e.Field, e.Code, map[string]interface{}(e.Params))
+	}
+	return fmt.Sprintf("validation error on field '%s': %s", e.Field, e.Code)
+}
+
+// With はパラメータを追加したValidationErrorを返す
+func (e ValidationError) With(key string, value interface{}) ValidationError {
+	e.Params = e.Params.with(key, value)
+	return e
 }
 
 // NewValidationError は新しいValidationErrorを作成
-func NewValidationError(field, message string) ValidationError {
+func NewValidationError(field, code string) ValidationError {
 	return ValidationError{
-		Field:   field,
-		Message: message,
+		Field: field,
+		Code:  code,
+	}
+}
+
+// ValidationErrors は複数項目のバリデーションエラーをまとめて表す
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
 	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap は個々のバリデーションエラーを返す（errors.As で最初の ValidationError を取り出せる）
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
 }
 
 // DomainError はドメインエラーを表す
 type DomainError struct {
-	Code    string
-	Message string
+	Code   string
+	Params Params // メッセージに埋め込むパラメータ（NOT_FOUND の resource など）
 }
 
 func (e DomainError) Error() string {
-	return fmt.Sprintf("domain error [%s]: %s", e.Code, e.Message)
+	if len(e.Params) > 0 {
+		return fmt.Sprintf("domain error [%s]: %v", e.Code, map[string]interface{}(e.Params))
+	}
+	return fmt.Sprintf("domain error [%s]", e.Code)
+}
+
+// With はパラメータを追加したDomainErrorを返す
+func (e DomainError) With(key string, value interface{}) DomainError {
+	e.Params = e.Params.with(key, value)
+	return e
 }
 
 // NewDomainError は新しいDomainErrorを作成
-func NewDomainError(code, message string) DomainError {
+func NewDomainError(code string) DomainError {
 	return DomainError{
-		Code:    code,
-		Message: message,
+		Code: code,
+	}
+}
+
+// InfrastructureError は外部サービス（Supabase など）とのやり取りで発生したエラーを表す
+// レスポンスボディなどの詳細はログ用であり、クライアントには返さない
+type InfrastructureError struct {
+	Op         string // 失敗した処理（"create question" など）
+	StatusCode int    // 外部サービスが返したHTTPステータス（不明な場合は0）
+	Body       string // 外部サービスのレスポンスボディ
+}
+
+func (e InfrastructureError) Error() string {
+	return fmt.Sprintf("infrastructure error [%s]: status %d: %s", e.Op, e.StatusCode, e.Body)
+}
+
+// NewInfrastructureError は新しいInfrastructureErrorを作成
+func NewInfrastructureError(op string, statusCode int, body string) InfrastructureError {
+	return InfrastructureError{
+		Op:         op,
+		StatusCode: statusCode,
+		Body:       body,
 	}
 }
@@ -0,0 +1,71 @@
+package validation
+
+// validation.goは入力値のバリデーションで全ての項目のエラーをまとめて集める仕組みを提供
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"Shittaka_back/internal/domain/shared"
+)
+
+// Validator はバリデーションエラーを集める
+// 最初のエラーで止めずに全ての項目を検証し、Err でまとめて返す
+// 各メソッドは検証に通ったかどうかを返すので、前の検証に通った場合だけ続ける検証も書ける
+type Validator struct {
+	errs shared.ValidationErrors
+}
+
+// New は新しいValidatorを作成
+func New() *Validator {
+	return &Validator{}
+}
+
+// Required は文字列が空（空白のみを含む）でないことを検証する
+func (v *Validator) Required(field, value string) bool {
+	return v.Check(strings.TrimSpace(value) != "", shared.NewValidationError(field, shared.ValidationRequired))
+}
+
+// RequiredID はIDが指定されている（0でない）ことを検証する
+func (v *Validator) RequiredID(field string, id int64) bool {
+	return v.Check(id != 0, shared.NewValidationError(field, shared.ValidationRequired))
+}
+
+// MinLength は文字数（バイト数ではなく文字数）が min 以上であることを検証する
+// 空文字は Required で検証するため対象外とする
+func (v *Validator) MinLength(field, value string, min int) bool {
+	ok := value == "" || utf8.RuneCountInString(value) >= min
+	return v.Check(ok, shared.NewValidationError(field, shared.ValidationMinLength).With("min", min))
+}
+
+// MaxLength は文字数（バイト数ではなく文字数）が max 以下であることを検証する
+func (v *Validator) MaxLength(field, value string, max int) bool {
+	ok := utf8.RuneCountInString(value) <= max
+	return v.Check(ok, shared.NewValidationError(field, shared.ValidationMaxLength).With("max", max))
+}
+
+// Check は ok が false の場合に err を記録する
+func (v *Validator) Check(ok bool, err shared.ValidationError) bool {
+	if !ok {
+		v.Add(err)
+	}
+	return ok
+}
+
+// Add はバリデーションエラーを記録する
+func (v *Validator) Add(err shared.ValidationError) {
+	v.errs = append(v.errs, err)
+}
+
+// Valid はこれまでの検証でエラーがなかったかを返す
+func (v *Validator) Valid() bool {
+	return len(v.errs) == 0
+}
+
+// Err は記録したエラーを ValidationErrors として返す（エラーがなければnil）
+func (v *Validator) Err() error {
+	if v.Valid() {
+		return nil
+	}
+	return v.errs
+}
@@ -0,0 +1,49 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"Shittaka_back/internal/domain/shared"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidator_CollectsAllErrors(t *testing.T) {
+	v := New()
+	v.RequiredID("genre_id", 0)
+	v.Required("title", "  ")
+	v.MaxLength("body", "abcdef", 5)
+
+	var errs shared.ValidationErrors
+	require.True(t, errors.As(v.Err(), &errs))
+	require.Len(t, errs, 3)
+	assert.Equal(t, "genre_id", errs[0].Field)
+	assert.Equal(t, shared.ValidationRequired, errs[1].Code)
+	assert.Equal(t, 5, errs[2].Params["max"])
+
+	// 個々のエラーも errors.As で取り出せる
+	var first shared.ValidationError
+	assert.True(t, errors.As(v.Err(), &first))
+	assert.Equal(t, "genre_id", first.Field)
+}
+
+func TestValidator_LengthCountsRunes(t *testing.T) {
+	title := "日本語のタイトル" // 8文字・24バイト
+
+	v := New()
+	assert.True(t, v.MaxLength("title", title, 8))
+	assert.False(t, v.MaxLength("title", title, 7))
+	assert.True(t, v.MinLength("title", title, 8))
+	assert.False(t, v.MinLength("title", title, 9))
+}
+
+func TestValidator_NoErrors(t *testing.T) {
+	v := New()
+	v.Required("title", "問題")
+	v.MinLength("password", "", 6) // 空文字は Required の対象
+
+	assert.True(t, v.Valid())
+	assert.NoError(t, v.Err())
+}
@@ -0,0 +1,60 @@
+package entities
+
+// tag.goはタグのドメインエンティティを定義
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/domain/shared/validation"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// MaxTagsPerQuestion は1つの問題に付けられるタグの上限
+	MaxTagsPerQuestion = 5
+	// MaxTagNameLength はタグ名の最大文字数
+	MaxTagNameLength = 30
+)
+
+// Tag はタグエンティティ
+type Tag struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	UsageCount int    `json:"usage_count"`
+}
+
+// NormalizeTagName はタグ名を正規化する
+// 全角英数の半角化（NFKC）、先頭の#除去、小文字化、空白の連続を"-"に置き換える
+func NormalizeTagName(name string) string {
+	name = norm.NFKC.String(name)
+	name = strings.TrimSpace(name)
+	name = strings.TrimLeft(name, "#")
+	name = strings.ToLower(name)
+	return strings.Join(strings.Fields(name), "-")
+}
+
+// NormalizeTagNames はタグ名の一覧を正規化し、重複と空文字を取り除く
+// 問題あたりの上限数とタグ名の長さも検証し、エラーは v に記録する（同じ種類のエラーはタグの数にかかわらず1件だけ）
+func NormalizeTagNames(v *validation.Validator, names []string) []string {
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	tooLong := false
+	for _, name := range names {
+		n := NormalizeTagName(name)
+		if n == "" || seen[n] {
+			continue
+		}
+		if utf8.RuneCountInString(n) > MaxTagNameLength {
+			tooLong = true
+		}
+		seen[n] = true
+		normalized = append(normalized, n)
+	}
+
+	v.Check(!tooLong, shared.NewValidationError("tags", shared.ValidationMaxLength).With("max", MaxTagNameLength))
+	v.Check(len(normalized) <= MaxTagsPerQuestion, shared.NewValidationError("tags", shared.ValidationMaxItems).With("max", MaxTagsPerQuestion))
+	return normalized
+}
@@ -0,0 +1,21 @@
+package repositories
+
+// tag_repository.goはタグリポジトリのインターフェースを定義
+
+import (
+	"context"
+
+	"Shittaka_back/internal/domain/tag/entities"
+)
+
+// TagRepository はタグリポジトリのインターフェース
+type TagRepository interface {
+	// Search は名前の前方一致でタグを検索し、使用数の多い順に返す（prefixが空なら全件）
+	Search(ctx context.Context, prefix string, limit int) ([]*entities.Tag, error)
+
+	// FindByQuestionIDs は問題IDごとのタグ名一覧を取得する
+	FindByQuestionIDs(ctx context.Context, questionIDs []int64) (map[int64][]string, error)
+
+	// FindQuestionIDsByName は指定したタグが付いた問題のIDを取得する
+	FindQuestionIDsByName(ctx context.Context, name string) ([]int64, error)
+}
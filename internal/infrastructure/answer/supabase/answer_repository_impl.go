@@ -7,28 +7,48 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"strconv"
 	"time"
 
 	"Shittaka_back/internal/domain/answer/entities"
 	"Shittaka_back/internal/domain/answer/repositories"
+	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/infrastructure/config"
+	"Shittaka_back/internal/infrastructure/httpclient"
 )
 
 // AnswerRepositoryImpl はSupabaseを使用したAnswerRepositoryの実装
-type AnswerRepositoryImpl struct{}
+type AnswerRepositoryImpl struct {
+	supabase config.SupabaseConfig
+}
 
 // NewAnswerRepository は新しいAnswerRepositoryImplを作成
-func NewAnswerRepository() repositories.AnswerRepository {
-	return &AnswerRepositoryImpl{}
+func NewAnswerRepository(supabase config.SupabaseConfig) repositories.AnswerRepository {
+	return &AnswerRepositoryImpl{supabase: supabase}
 }
 
 // Create は新しい回答を作成（RLS適用のためユーザートークンを使用）
 func (r *AnswerRepositoryImpl) Create(ctx context.Context, answer *entities.Answer, userToken string) (*entities.Answer, error) {
+	choiceIDs := answer.ChoiceIDs
+	if choiceIDs == nil {
+		choiceIDs = []int64{}
+	}
+
 	answerData := map[string]interface{}{
-		"user_id":     answer.UserID,
-		"question_id": answer.QuestionID,
-		"choice_id":   answer.ChoiceID,
+		"user_id":        answer.UserID,
+		"question_id":    answer.QuestionID,
+		"choice_ids":     choiceIDs,
+		"boolean_answer": answer.BooleanAnswer,
+		"numeric_answer": answer.NumericAnswer,
+		"is_correct":     answer.IsCorrect,
+		"score":          answer.Score,
+	}
+	// choice_id は単一選択の場合のみ設定（それ以外は null）
+	if answer.ChoiceID != 0 {
+		answerData["choice_id"] = answer.ChoiceID
+	}
+	if answer.TextAnswer != "" {
+		answerData["text_answer"] = answer.TextAnswer
 	}
 
 	jsonData, err := json.Marshal(answerData)
@@ -36,18 +56,18 @@ func (r *AnswerRepositoryImpl) Create(ctx context.Context, answer *entities.Answ
 		return nil, fmt.Errorf("failed to marshal answer data: %w", err)
 	}
 
-	url := os.Getenv("SUPABASE_URL") + "/rest/v1/answers"
+	url := r.supabase.URL + "/rest/v1/answers"
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
 	req.Header.Set("Authorization", "Bearer "+userToken)
 	req.Header.Set("Prefer", "return=representation")
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -60,7 +80,7 @@ func (r *AnswerRepositoryImpl) Create(ctx context.Context, answer *entities.Answ
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("create answer failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, shared.NewInfrastructureError("create answer", resp.StatusCode, string(body))
 	}
 
 	var answerList []map[string]interface{}
@@ -78,16 +98,16 @@ func (r *AnswerRepositoryImpl) Create(ctx context.Context, answer *entities.Answ
 
 // GetByUserID はユーザーIDで回答一覧を取得
 func (r *AnswerRepositoryImpl) GetByUserID(ctx context.Context, userID string) ([]*entities.Answer, error) {
-	url := fmt.Sprintf("%s/rest/v1/answers?user_id=eq.%s", os.Getenv("SUPABASE_URL"), userID)
+	url := fmt.Sprintf("%s/rest/v1/answers?user_id=eq.%s", r.supabase.URL, userID)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
-	req.Header.Set("Authorization", "Bearer "+os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+r.supabase.AnonKey)
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -100,7 +120,7 @@ func (r *AnswerRepositoryImpl) GetByUserID(ctx context.Context, userID string) (
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("find answers by user failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, shared.NewInfrastructureError("find answers by user", resp.StatusCode, string(body))
 	}
 
 	var answerList []map[string]interface{}
@@ -118,16 +138,16 @@ func (r *AnswerRepositoryImpl) GetByUserID(ctx context.Context, userID string) (
 
 // GetByQuestionID は問題IDで回答一覧を取得
 func (r *AnswerRepositoryImpl) GetByQuestionID(ctx context.Context, questionID int64) ([]*entities.Answer, error) {
-	url := fmt.Sprintf("%s/rest/v1/answers?question_id=eq.%d", os.Getenv("SUPABASE_URL"), questionID)
+	url := fmt.Sprintf("%s/rest/v1/answers?question_id=eq.%d", r.supabase.URL, questionID)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
-	req.Header.Set("Authorization", "Bearer "+os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+r.supabase.AnonKey)
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -140,7 +160,7 @@ func (r *AnswerRepositoryImpl) GetByQuestionID(ctx context.Context, questionID i
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("find answers by question failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, shared.NewInfrastructureError("find answers by question", resp.StatusCode, string(body))
 	}
 
 	var answerList []map[string]interface{}
@@ -164,6 +184,14 @@ func mapToAnswer(m map[string]interface{}) *entities.Answer {
 		QuestionID: getInt64(m, "question_id"),
 		ChoiceID:   getInt64(m, "choice_id"),
 		AnsweredAt: getTime(m, "answered_at"),
+		Submission: entities.Submission{
+			ChoiceIDs:     getInt64Slice(m, "choice_ids"),
+			BooleanAnswer: getBoolPtr(m, "boolean_answer"),
+			TextAnswer:    getString(m, "text_answer"),
+			NumericAnswer: getFloat64Ptr(m, "numeric_answer"),
+		},
+		IsCorrect: getBool(m, "is_correct"),
+		Score:     getFloat64(m, "score"),
 	}
 }
 
@@ -198,6 +226,64 @@ func getInt64(m map[string]interface{}, key string) int64 {
 	return 0
 }
 
+// getInt64Slice は map から []int64 を安全に取得
+func getInt64Slice(m map[string]interface{}, key string) []int64 {
+	result := []int64{}
+	if val, ok := m[key]; ok {
+		if items, ok := val.([]interface{}); ok {
+			for _, item := range items {
+				if f, ok := item.(float64); ok {
+					result = append(result, int64(f))
+				}
+			}
+		}
+	}
+	return result
+}
+
+// getBool は map から bool を安全に取得
+func getBool(m map[string]interface{}, key string) bool {
+	if val, ok := m[key]; ok {
+		if b, ok := val.(bool); ok {
+			return b
+		}
+	}
+	return false
+}
+
+// getBoolPtr は map から *bool を安全に取得（null の場合は nil）
+func getBoolPtr(m map[string]interface{}, key string) *bool {
+	if val, ok := m[key]; ok {
+		if b, ok := val.(bool); ok {
+			return &b
+		}
+	}
+	return nil
+}
+
+// getFloat64 は map から float64 を安全に取得
+func getFloat64(m map[string]interface{}, key string) float64 {
+	if f := getFloat64Ptr(m, key); f != nil {
+		return *f
+	}
+	return 0
+}
+
+// getFloat64Ptr は map から *float64 を安全に取得（null の場合は nil）
+func getFloat64Ptr(m map[string]interface{}, key string) *float64 {
+	if val, ok := m[key]; ok {
+		switch v := val.(type) {
+		case float64:
+			return &v
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return &f
+			}
+		}
+	}
+	return nil
+}
+
 // getTime は map から time.Time を安全に取得
 func getTime(m map[string]interface{}, key string) time.Time {
 	if val, ok := m[key]; ok {
@@ -208,4 +294,4 @@ func getTime(m map[string]interface{}, key string) time.Time {
 		}
 	}
 	return time.Time{}
-}
\ No newline at end of file
+}
@@ -0,0 +1,93 @@
+package memory
+
+// login_attempt_repository_impl.goはログインの失敗の記録をプロセス内のメモリに保存するリポジトリを定義
+
+import (
+	"Shittaka_back/internal/domain/auth/entities"
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval は期限切れの記録を削除する間隔
+const sweepInterval = time.Minute
+
+// record は保存している記録と、その期限
+type record struct {
+	attempt   entities.LoginAttempt
+	expiresAt time.Time
+}
+
+// LoginAttemptRepositoryImpl はログインの失敗の記録をメモリに保存する LoginAttemptRepository
+// 記録はプロセスごとになるため、複数のマシンで動かす場合はマシンごとに数える
+type LoginAttemptRepositoryImpl struct {
+	mu        sync.Mutex
+	records   map[string]record
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewLoginAttemptRepository は新しいLoginAttemptRepositoryImplを作成
+func NewLoginAttemptRepository() *LoginAttemptRepositoryImpl {
+	return &LoginAttemptRepositoryImpl{
+		records: make(map[string]record),
+		now:     time.Now,
+	}
+}
+
+// Find はキーの記録を取得する（記録がないか期限切れなら nil）
+func (r *LoginAttemptRepositoryImpl) Find(ctx context.Context, key string) (*entities.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec, ok := r.records[key]
+	if !ok || !r.now().Before(rec.expiresAt) {
+		return nil, nil
+	}
+	attempt := rec.attempt
+	return &attempt, nil
+}
+
+// Increment はキーの失敗の回数を1増やし、記録を最後の失敗から window の間保存する
+// ロックの範囲で読み込みと書き込みを行うため、同時に呼ばれても回数を取りこぼさない
+func (r *LoginAttemptRepositoryImpl) Increment(ctx context.Context, key string, now time.Time, window time.Duration) (*entities.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sweep(r.now())
+
+	rec, ok := r.records[key]
+	if !ok || now.Sub(rec.attempt.LastFailedAt) > window {
+		rec = record{}
+	}
+	rec.attempt.Failures++
+	rec.attempt.LastFailedAt = now
+	rec.expiresAt = now.Add(window)
+	r.records[key] = rec
+
+	attempt := rec.attempt
+	return &attempt, nil
+}
+
+// Delete はキーの記録を削除する
+func (r *LoginAttemptRepositoryImpl) Delete(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.records, key)
+	return nil
+}
+
+// sweep は期限切れの記録を一定間隔で削除する
+func (r *LoginAttemptRepositoryImpl) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < sweepInterval {
+		return
+	}
+	r.lastSweep = now
+
+	for key, rec := range r.records {
+		if !now.Before(rec.expiresAt) {
+			delete(r.records, key)
+		}
+	}
+}
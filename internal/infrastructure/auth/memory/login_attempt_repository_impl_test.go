@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginAttemptRepository_Increment(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := NewLoginAttemptRepository()
+	repo.now = func() time.Time { return now }
+
+	t.Run("同時に呼ばれても回数を取りこぼさない", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for range 100 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := repo.Increment(ctx, "email:user@example.com", now, time.Minute)
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		attempt, err := repo.Find(ctx, "email:user@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, 100, attempt.Failures)
+	})
+
+	t.Run("window が過ぎたら数え直す", func(t *testing.T) {
+		now = now.Add(2 * time.Minute)
+
+		attempt, err := repo.Increment(ctx, "email:user@example.com", now, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, 1, attempt.Failures)
+		assert.Equal(t, now, attempt.LastFailedAt)
+	})
+}
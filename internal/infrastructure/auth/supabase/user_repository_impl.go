@@ -9,34 +9,37 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
 	"Shittaka_back/internal/domain/auth/entities"
 	"Shittaka_back/internal/domain/auth/repositories"
+	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/infrastructure/config"
+	"Shittaka_back/internal/infrastructure/httpclient"
 
 	"github.com/supabase-community/gotrue-go"
 )
 
 // UserRepositoryImpl はSupabaseを使用したUserRepositoryの実装
 type UserRepositoryImpl struct {
-	client gotrue.Client
+	client   gotrue.Client
+	supabase config.SupabaseConfig
 }
 
 // NewUserRepository は新しいUserRepositoryImplを作成
-func NewUserRepository() *UserRepositoryImpl {
-	baseURL := os.Getenv("SUPABASE_URL")
-	baseURL = strings.TrimSuffix(baseURL, "/")
+func NewUserRepository(supabase config.SupabaseConfig) *UserRepositoryImpl {
+	baseURL := strings.TrimSuffix(supabase.URL, "/")
 	authURL := baseURL + "/auth/v1"
 
 	client := gotrue.New(
 		authURL,
-		os.Getenv("SUPABASE_SERVICE_ROLE_KEY"),
+		supabase.ServiceRoleKey,
 	)
 
 	return &UserRepositoryImpl{
-		client: client,
+		client:   client,
+		supabase: supabase,
 	}
 }
 
@@ -53,17 +56,17 @@ func (r *UserRepositoryImpl) Create(ctx context.Context, email, password string,
 		return nil, fmt.Errorf("failed to marshal signup data: %w", err)
 	}
 
-	authURL := os.Getenv("SUPABASE_URL") + "/auth/v1/signup"
+	authURL := r.supabase.URL + "/auth/v1/signup"
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", authURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("apikey", os.Getenv("SUPABASE_SERVICE_ROLE_KEY"))
-	httpReq.Header.Set("Authorization", "Bearer "+os.Getenv("SUPABASE_SERVICE_ROLE_KEY"))
+	httpReq.Header.Set("apikey", r.supabase.ServiceRoleKey)
+	httpReq.Header.Set("Authorization", "Bearer "+r.supabase.ServiceRoleKey)
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -76,7 +79,7 @@ func (r *UserRepositoryImpl) Create(ctx context.Context, email, password string,
 	}
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("signup failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, shared.NewInfrastructureError("signup", resp.StatusCode, string(body))
 	}
 
 	var supabaseResp map[string]interface{}
@@ -112,16 +115,16 @@ func (r *UserRepositoryImpl) Authenticate(ctx context.Context, email, password s
 		return nil, fmt.Errorf("failed to marshal login data: %w", err)
 	}
 
-	authURL := os.Getenv("SUPABASE_URL") + "/auth/v1/token?grant_type=password"
+	authURL := r.supabase.URL + "/auth/v1/token?grant_type=password"
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", authURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("apikey", os.Getenv("SUPABASE_SERVICE_ROLE_KEY"))
+	httpReq.Header.Set("apikey", r.supabase.ServiceRoleKey)
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -134,11 +137,16 @@ func (r *UserRepositoryImpl) Authenticate(ctx context.Context, email, password s
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		// メール確認エラーの特別な処理
-		if resp.StatusCode == 400 && strings.Contains(string(body), "email_not_confirmed") {
-			return nil, fmt.Errorf("email confirmation required: please check your email and click the confirmation link")
+		// メール確認エラーと認証情報の誤りは、GoTrueの障害と区別して返す
+		if resp.StatusCode == http.StatusBadRequest {
+			switch {
+			case strings.Contains(string(body), "email_not_confirmed") || strings.Contains(string(body), "Email not confirmed"):
+				return nil, repositories.ErrEmailNotConfirmed
+			case strings.Contains(string(body), "invalid_credentials") || strings.Contains(string(body), "invalid_grant"):
+				return nil, repositories.ErrInvalidCredentials
+			}
 		}
-		return nil, fmt.Errorf("authentication failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, shared.NewInfrastructureError("authentication", resp.StatusCode, string(body))
 	}
 
 	var supabaseResp map[string]interface{}
@@ -187,7 +195,7 @@ func (r *UserRepositoryImpl) Delete(ctx context.Context, id string) error {
 
 // Logout はユーザーをログアウトさせる
 func (r *UserRepositoryImpl) Logout(ctx context.Context, token string) error {
-	baseURL := os.Getenv("SUPABASE_URL")
+	baseURL := r.supabase.URL
 	baseURL = strings.TrimSuffix(baseURL, "/")
 	authURL := baseURL + "/auth/v1/logout"
 
@@ -196,11 +204,11 @@ func (r *UserRepositoryImpl) Logout(ctx context.Context, token string) error {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	httpReq.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
+	httpReq.Header.Set("apikey", r.supabase.AnonKey)
 	httpReq.Header.Set("Authorization", "Bearer "+token)
 	httpReq.Header.Set("Content-Type", "application/json")
 
-	httpClient := &http.Client{}
+	httpClient := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := httpClient.Do(httpReq)
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
@@ -213,7 +221,7 @@ func (r *UserRepositoryImpl) Logout(ctx context.Context, token string) error {
 	}
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("logout failed with status %d: %s", resp.StatusCode, string(body))
+		return shared.NewInfrastructureError("logout", resp.StatusCode, string(body))
 	}
 
 	return nil
@@ -221,16 +229,16 @@ func (r *UserRepositoryImpl) Logout(ctx context.Context, token string) error {
 
 // GetCurrentUser はアクセストークンから現在のユーザー情報を取得
 func (r *UserRepositoryImpl) GetCurrentUser(ctx context.Context, token string) (*entities.User, error) {
-	authURL := os.Getenv("SUPABASE_URL") + "/auth/v1/user"
+	authURL := r.supabase.URL + "/auth/v1/user"
 	httpReq, err := http.NewRequestWithContext(ctx, "GET", authURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	httpReq.Header.Set("apikey", os.Getenv("SUPABASE_SERVICE_ROLE_KEY"))
+	httpReq.Header.Set("apikey", r.supabase.ServiceRoleKey)
 	httpReq.Header.Set("Authorization", "Bearer "+token)
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -243,7 +251,7 @@ func (r *UserRepositoryImpl) GetCurrentUser(ctx context.Context, token string) (
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get user info with status %d: %s", resp.StatusCode, string(body))
+		return nil, shared.NewInfrastructureError("get user info", resp.StatusCode, string(body))
 	}
 
 	var supabaseResp map[string]interface{}
@@ -267,18 +275,79 @@ func (r *UserRepositoryImpl) GetCurrentUser(ctx context.Context, token string) (
 	return user, nil
 }
 
+// Refresh はリフレッシュトークンから新しいトークンを発行する
+func (r *UserRepositoryImpl) Refresh(ctx context.Context, refreshToken string) (*repositories.AuthResult, error) {
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"refresh_token": refreshToken,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal refresh data: %w", err)
+	}
+
+	authURL := r.supabase.URL + "/auth/v1/token?grant_type=refresh_token"
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", authURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("apikey", r.supabase.ServiceRoleKey)
+
+	client := httpclient.New(r.supabase.RequestTimeout)
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, shared.NewInfrastructureError("refresh token", resp.StatusCode, string(body))
+	}
+
+	var supabaseResp map[string]interface{}
+	if err := json.Unmarshal(body, &supabaseResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	userMap := getMap(supabaseResp, "user")
+	userMetadata := getMap(userMap, "user_metadata")
+	user := entities.NewUser(
+		getString(userMap, "id"),
+		getString(userMap, "email"),
+		getString(userMetadata, "username"),
+	)
+
+	// Supabaseが返す有効期限を優先し、ない場合はログイン時と同じく24時間とする
+	expiresAt := time.Now().Add(time.Hour * 24).Unix()
+	if value, ok := supabaseResp["expires_at"].(float64); ok {
+		expiresAt = int64(value)
+	}
+
+	return &repositories.AuthResult{
+		User:         user,
+		AccessToken:  getString(supabaseResp, "access_token"),
+		RefreshToken: getString(supabaseResp, "refresh_token"),
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
 // getProfileName はprofilesテーブルからnameを取得
 func (r *UserRepositoryImpl) getProfileName(ctx context.Context, userID string) (string, error) {
-	url := fmt.Sprintf("%s/rest/v1/profiles?id=eq.%s&select=name", os.Getenv("SUPABASE_URL"), userID)
+	url := fmt.Sprintf("%s/rest/v1/profiles?id=eq.%s&select=name", r.supabase.URL, userID)
 	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
-	httpReq.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
-	httpReq.Header.Set("Authorization", "Bearer "+os.Getenv("SUPABASE_ANON_KEY"))
+	httpReq.Header.Set("apikey", r.supabase.AnonKey)
+	httpReq.Header.Set("Authorization", "Bearer "+r.supabase.AnonKey)
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(httpReq)
 	if err != nil {
 		return "", fmt.Errorf("failed to execute request: %w", err)
@@ -291,7 +360,7 @@ func (r *UserRepositoryImpl) getProfileName(ctx context.Context, userID string)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to get profile with status %d: %s", resp.StatusCode, string(body))
+		return "", shared.NewInfrastructureError("get profile", resp.StatusCode, string(body))
 	}
 
 	var profileList []map[string]interface{}
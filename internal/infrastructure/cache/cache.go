@@ -0,0 +1,138 @@
+package cache
+
+// cache.goは読み込んだ値を期限付きで保持する、件数に上限のあるLRUキャッシュを定義
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	appMetrics "Shittaka_back/internal/application/metrics"
+)
+
+// entry は保持している値と、その期限
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// call は読み込み中の値（同じキーの読み込みを1回にまとめる）
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+
+	// stale は読み込み中に無効化されたかどうか（古い値を保持しないようにする）
+	stale bool
+}
+
+// Cache は値を TTL の間保持し、件数が上限を超えたら最も長く使われていない値から捨てるキャッシュ
+// 同じキーの読み込みが同時に起きた場合は、1回だけ読み込んで結果を共有する
+type Cache[K comparable, V any] struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[K]*list.Element
+	order      *list.List // 先頭ほど最近使った値
+	calls      map[K]*call[V]
+	now        func() time.Time
+}
+
+// New は新しいCacheを作成
+func New[K comparable, V any](ttl time.Duration, maxEntries int) *Cache[K, V] {
+	return &Cache[K, V]{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[K]*list.Element),
+		order:      list.New(),
+		calls:      make(map[K]*call[V]),
+		now:        time.Now,
+	}
+}
+
+// Get はキーの値を返し、なければ load で読み込んで保持する
+// 結果（appMetrics.CacheHit / CacheMiss / CacheShared）もあわせて返す
+// 読み込みは呼び出し元のキャンセルに影響されない（同じ読み込みを待つ他の呼び出しがあるため）
+// 読み込みに失敗した場合は保持しない
+func (c *Cache[K, V]) Get(ctx context.Context, key K, load func(ctx context.Context) (V, error)) (V, string, error) {
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		e := elem.Value.(*entry[K, V])
+		if c.now().Before(e.expiresAt) {
+			c.order.MoveToFront(elem)
+			c.mu.Unlock()
+			return e.value, appMetrics.CacheHit, nil
+		}
+		c.remove(elem)
+	}
+
+	if inflight, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		select {
+		case <-inflight.done:
+			return inflight.value, appMetrics.CacheShared, inflight.err
+		case <-ctx.Done():
+			var zero V
+			return zero, appMetrics.CacheShared, ctx.Err()
+		}
+	}
+
+	inflight := &call[V]{done: make(chan struct{})}
+	c.calls[key] = inflight
+	c.mu.Unlock()
+
+	inflight.value, inflight.err = load(context.WithoutCancel(ctx))
+
+	c.mu.Lock()
+	if c.calls[key] == inflight {
+		delete(c.calls, key)
+	}
+	if inflight.err == nil && !inflight.stale {
+		c.add(key, inflight.value)
+	}
+	c.mu.Unlock()
+	close(inflight.done)
+
+	return inflight.value, appMetrics.CacheMiss, inflight.err
+}
+
+// Invalidate はキーの値を捨てる（読み込み中の値も保持しない）
+func (c *Cache[K, V]) Invalidate(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	if inflight, ok := c.calls[key]; ok {
+		inflight.stale = true
+		delete(c.calls, key)
+	}
+}
+
+// Len は保持している値の数を返す（期限切れの値を含む）
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// add は値を保持し、上限を超えた分を最も長く使われていない値から捨てる
+func (c *Cache[K, V]) add(key K, value V) {
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	c.entries[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expiresAt: c.now().Add(c.ttl)})
+
+	for c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+// remove は値を捨てる
+func (c *Cache[K, V]) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*entry[K, V]).key)
+}
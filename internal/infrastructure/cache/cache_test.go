@@ -0,0 +1,179 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	appMetrics "Shittaka_back/internal/application/metrics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// counter は呼ばれた回数を数え、その回数を値として返す読み込み
+type counter struct {
+	calls atomic.Int32
+}
+
+func (c *counter) load(ctx context.Context) (int, error) {
+	return int(c.calls.Add(1)), nil
+}
+
+func TestCache_Get(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	c := New[string, int](time.Minute, 2)
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+	var loads counter
+
+	value, result, err := c.Get(ctx, "a", loads.load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, appMetrics.CacheMiss, result)
+
+	t.Run("TTL の間は保持している値を返す", func(t *testing.T) {
+		now = now.Add(59 * time.Second)
+		value, result, err := c.Get(ctx, "a", loads.load)
+		require.NoError(t, err)
+		assert.Equal(t, 1, value)
+		assert.Equal(t, appMetrics.CacheHit, result)
+	})
+
+	t.Run("TTL が過ぎたら読み込み直す", func(t *testing.T) {
+		now = now.Add(time.Second)
+		value, result, err := c.Get(ctx, "a", loads.load)
+		require.NoError(t, err)
+		assert.Equal(t, 2, value)
+		assert.Equal(t, appMetrics.CacheMiss, result)
+	})
+
+	t.Run("上限を超えたら最も長く使われていない値から捨てる", func(t *testing.T) {
+		_, _, err := c.Get(ctx, "b", loads.load)
+		require.NoError(t, err)
+		_, _, err = c.Get(ctx, "a", loads.load) // a を最近使った値にする
+		require.NoError(t, err)
+		_, _, err = c.Get(ctx, "c", loads.load)
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, c.Len())
+		_, result, _ := c.Get(ctx, "a", loads.load)
+		assert.Equal(t, appMetrics.CacheHit, result)
+		_, result, _ = c.Get(ctx, "b", loads.load)
+		assert.Equal(t, appMetrics.CacheMiss, result)
+	})
+
+	t.Run("無効化したら読み込み直す", func(t *testing.T) {
+		c.Invalidate("a")
+		_, result, err := c.Get(ctx, "a", loads.load)
+		require.NoError(t, err)
+		assert.Equal(t, appMetrics.CacheMiss, result)
+	})
+}
+
+func TestCache_DoesNotKeepErrors(t *testing.T) {
+	c := New[string, int](time.Minute, 10)
+	ctx := context.Background()
+
+	_, _, err := c.Get(ctx, "a", func(ctx context.Context) (int, error) { return 0, errors.New("supabase unavailable") })
+	require.Error(t, err)
+
+	value, result, err := c.Get(ctx, "a", func(ctx context.Context) (int, error) { return 1, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, appMetrics.CacheMiss, result)
+}
+
+func TestCache_CollapsesConcurrentLoads(t *testing.T) {
+	c := New[string, int](time.Minute, 10)
+	ctx := context.Background()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var loads atomic.Int32
+	load := func(ctx context.Context) (int, error) {
+		loads.Add(1)
+		close(started)
+		<-release
+		return 42, nil
+	}
+
+	// 最初の読み込みが始まってから、同じキーを同時に取得する
+	const waiters = 10
+	results := make(chan string, waiters+1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		value, result, err := c.Get(ctx, "a", load)
+		assert.NoError(t, err)
+		assert.Equal(t, 42, value)
+		results <- result
+	}()
+	<-started
+	for range waiters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, result, err := c.Get(ctx, "a", load)
+			assert.NoError(t, err)
+			assert.Equal(t, 42, value)
+			results <- result
+		}()
+	}
+
+	// 待っている呼び出しが揃うまで読み込みを止めておく
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return len(c.calls) == 1
+	}, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	assert.Equal(t, int32(1), loads.Load())
+	counts := map[string]int{}
+	for result := range results {
+		counts[result]++
+	}
+	assert.Equal(t, 1, counts[appMetrics.CacheMiss])
+	assert.Equal(t, waiters, counts[appMetrics.CacheShared]+counts[appMetrics.CacheHit])
+}
+
+func TestCache_InvalidateDuringLoad(t *testing.T) {
+	c := New[string, int](time.Minute, 10)
+	ctx := context.Background()
+
+	// 読み込み中に書き込まれた場合、書き込み前の値は保持しない
+	value, _, err := c.Get(ctx, "a", func(ctx context.Context) (int, error) {
+		c.Invalidate("a")
+		return 1, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestCache_WaiterCanceled(t *testing.T) {
+	c := New[string, int](time.Minute, 10)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go c.Get(context.Background(), "a", func(ctx context.Context) (int, error) {
+		close(started)
+		<-release
+		return 1, nil
+	})
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err := c.Get(ctx, "a", func(ctx context.Context) (int, error) { return 2, nil })
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,179 @@
+package cache
+
+// repositories.goはリポジトリの読み込みをキャッシュするデコレーターを定義
+// 同じプロセスからの書き込みでは該当する値を捨てるが、他のマシンからの書き込みは TTL が過ぎるまで反映されない
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	appMetrics "Shittaka_back/internal/application/metrics"
+	genreEntities "Shittaka_back/internal/domain/genre/entities"
+	genreRepositories "Shittaka_back/internal/domain/genre/repositories"
+	questionEntities "Shittaka_back/internal/domain/question/entities"
+	questionRepositories "Shittaka_back/internal/domain/question/repositories"
+)
+
+// Options はキャッシュの設定
+type Options struct {
+	// TTL は読み込んだ値を保持する時間
+	TTL time.Duration
+
+	// MaxEntries はキャッシュごとに保持する値の数の上限
+	MaxEntries int
+}
+
+// allGenres は全てのジャンルの一覧のキー
+const allGenres = "all"
+
+// cachedGenreRepository は GenreRepository の読み込みをキャッシュする
+type cachedGenreRepository struct {
+	next     genreRepositories.GenreRepository
+	recorder appMetrics.Recorder
+	all      *Cache[string, []genreEntities.Genre]
+	byID     *Cache[int64, genreEntities.Genre]
+}
+
+// NewGenreRepository は GenreRepository の FindAll と FindByID をキャッシュするデコレーターを作成
+// Create で作成すると一覧を捨てる
+func NewGenreRepository(next genreRepositories.GenreRepository, options Options, recorder appMetrics.Recorder) genreRepositories.GenreRepository {
+	return &cachedGenreRepository{
+		next:     next,
+		recorder: recorder,
+		all:      New[string, []genreEntities.Genre](options.TTL, options.MaxEntries),
+		byID:     New[int64, genreEntities.Genre](options.TTL, options.MaxEntries),
+	}
+}
+
+func (r *cachedGenreRepository) Create(ctx context.Context, genre *genreEntities.Genre, userToken string) (*genreEntities.Genre, error) {
+	defer r.all.Invalidate(allGenres)
+	return r.next.Create(ctx, genre, userToken)
+}
+
+func (r *cachedGenreRepository) FindByID(ctx context.Context, id int64) (*genreEntities.Genre, error) {
+	genre, result, err := r.byID.Get(ctx, id, func(ctx context.Context) (genreEntities.Genre, error) {
+		genre, err := r.next.FindByID(ctx, id)
+		if err != nil {
+			return genreEntities.Genre{}, err
+		}
+		return *genre, nil
+	})
+	r.recorder.ObserveCacheLookup("GenreRepository.FindByID", result)
+	if err != nil {
+		return nil, err
+	}
+	return &genre, nil
+}
+
+func (r *cachedGenreRepository) FindAll(ctx context.Context) ([]*genreEntities.Genre, error) {
+	genres, result, err := r.all.Get(ctx, allGenres, func(ctx context.Context) ([]genreEntities.Genre, error) {
+		genres, err := r.next.FindAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]genreEntities.Genre, len(genres))
+		for i, genre := range genres {
+			values[i] = *genre
+		}
+		return values, nil
+	})
+	r.recorder.ObserveCacheLookup("GenreRepository.FindAll", result)
+	if err != nil {
+		return nil, err
+	}
+
+	// 呼び出し元が変更してもキャッシュに影響しないよう、複製を返す
+	copies := make([]*genreEntities.Genre, len(genres))
+	for i := range genres {
+		genre := genres[i]
+		copies[i] = &genre
+	}
+	return copies, nil
+}
+
+// FindByName はユーザーのトークンで検索するためキャッシュしない
+func (r *cachedGenreRepository) FindByName(ctx context.Context, name string, userToken string) (*genreEntities.Genre, error) {
+	return r.next.FindByName(ctx, name, userToken)
+}
+
+// cachedQuestionRepository は QuestionRepository の読み込みをキャッシュする
+type cachedQuestionRepository struct {
+	next     questionRepositories.QuestionRepository
+	recorder appMetrics.Recorder
+	byID     *Cache[int64, questionEntities.Question]
+}
+
+// NewQuestionRepository は QuestionRepository の GetByID をキャッシュするデコレーターを作成
+// Update と Delete では（失敗した場合も）その問題を捨てる
+func NewQuestionRepository(next questionRepositories.QuestionRepository, options Options, recorder appMetrics.Recorder) questionRepositories.QuestionRepository {
+	return &cachedQuestionRepository{
+		next:     next,
+		recorder: recorder,
+		byID:     New[int64, questionEntities.Question](options.TTL, options.MaxEntries),
+	}
+}
+
+func (r *cachedQuestionRepository) Create(ctx context.Context, question *questionEntities.Question, tags []string, userToken string) (*questionEntities.Question, error) {
+	return r.next.Create(ctx, question, tags, userToken)
+}
+
+func (r *cachedQuestionRepository) GetByID(ctx context.Context, id int64) (*questionEntities.Question, error) {
+	question, result, err := r.byID.Get(ctx, id, func(ctx context.Context) (questionEntities.Question, error) {
+		question, err := r.next.GetByID(ctx, id)
+		if err != nil {
+			return questionEntities.Question{}, err
+		}
+		return cloneQuestion(*question), nil
+	})
+	r.recorder.ObserveCacheLookup("QuestionRepository.GetByID", result)
+	if err != nil {
+		return nil, err
+	}
+
+	// 更新時に呼び出し元が項目を書き換えるため、複製を返す
+	question = cloneQuestion(question)
+	return &question, nil
+}
+
+func (r *cachedQuestionRepository) GetByUserID(ctx context.Context, userID string, userToken string) ([]*questionEntities.Question, error) {
+	return r.next.GetByUserID(ctx, userID, userToken)
+}
+
+// Update は版が一致しない場合（他のマシンで更新された場合）も、古い値を使い続けないよう問題を捨てる
+func (r *cachedQuestionRepository) Update(ctx context.Context, question *questionEntities.Question, tags []string, userToken string) error {
+	defer r.byID.Invalidate(question.ID)
+	return r.next.Update(ctx, question, tags, userToken)
+}
+
+func (r *cachedQuestionRepository) Delete(ctx context.Context, id int64, userToken string) error {
+	defer r.byID.Invalidate(id)
+	return r.next.Delete(ctx, id, userToken)
+}
+
+// Invalidate はキャッシュした問題を捨てる（questionRepositories.Invalidator の実装）
+func (r *cachedQuestionRepository) Invalidate(id int64) {
+	r.byID.Invalidate(id)
+}
+
+func (r *cachedQuestionRepository) GetAll(ctx context.Context) ([]*questionEntities.Question, error) {
+	return r.next.GetAll(ctx)
+}
+
+func (r *cachedQuestionRepository) GetByIDs(ctx context.Context, ids []int64) ([]*questionEntities.Question, error) {
+	return r.next.GetByIDs(ctx, ids)
+}
+
+// cloneQuestion はスライスとポインターの項目も含めて問題を複製する
+func cloneQuestion(q questionEntities.Question) questionEntities.Question {
+	q.AcceptedAnswers = slices.Clone(q.AcceptedAnswers)
+	if q.CorrectBoolean != nil {
+		v := *q.CorrectBoolean
+		q.CorrectBoolean = &v
+	}
+	if q.NumericAnswer != nil {
+		v := *q.NumericAnswer
+		q.NumericAnswer = &v
+	}
+	return q
+}
@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appMetrics "Shittaka_back/internal/application/metrics"
+	genreEntities "Shittaka_back/internal/domain/genre/entities"
+	genreRepositories "Shittaka_back/internal/domain/genre/repositories"
+	questionEntities "Shittaka_back/internal/domain/question/entities"
+	questionRepositories "Shittaka_back/internal/domain/question/repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lookupRecorder はキャッシュの結果だけを記録する Recorder
+type lookupRecorder struct {
+	appMetrics.Noop
+	lookups []string
+}
+
+func (r *lookupRecorder) ObserveCacheLookup(cache, result string) {
+	r.lookups = append(r.lookups, cache+":"+result)
+}
+
+// fakeGenreRepository は呼び出し回数を数えるジャンルのリポジトリ
+type fakeGenreRepository struct {
+	genreRepositories.GenreRepository
+	genres   []*genreEntities.Genre
+	findAlls int
+}
+
+func (r *fakeGenreRepository) Create(ctx context.Context, genre *genreEntities.Genre, userToken string) (*genreEntities.Genre, error) {
+	created := &genreEntities.Genre{ID: int64(len(r.genres) + 1), Name: genre.Name}
+	r.genres = append(r.genres, created)
+	return created, nil
+}
+
+func (r *fakeGenreRepository) FindAll(ctx context.Context) ([]*genreEntities.Genre, error) {
+	r.findAlls++
+	genres := make([]*genreEntities.Genre, len(r.genres))
+	for i, genre := range r.genres {
+		copied := *genre
+		genres[i] = &copied
+	}
+	return genres, nil
+}
+
+func TestGenreRepository_FindAll(t *testing.T) {
+	ctx := context.Background()
+	next := &fakeGenreRepository{genres: []*genreEntities.Genre{{ID: 1, Name: "数学"}}}
+	recorder := &lookupRecorder{}
+	repo := NewGenreRepository(next, Options{TTL: time.Minute, MaxEntries: 10}, recorder)
+
+	genres, err := repo.FindAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, genres, 1)
+
+	t.Run("2回目はキャッシュから返す", func(t *testing.T) {
+		genres[0].Name = "書き換え"
+
+		cached, err := repo.FindAll(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "数学", cached[0].Name)
+		assert.Equal(t, 1, next.findAlls)
+		assert.Equal(t, []string{"GenreRepository.FindAll:miss", "GenreRepository.FindAll:hit"}, recorder.lookups)
+	})
+
+	t.Run("作成したら一覧を読み込み直す", func(t *testing.T) {
+		_, err := repo.Create(ctx, genreEntities.NewGenre("英語"), "token")
+		require.NoError(t, err)
+
+		genres, err := repo.FindAll(ctx)
+		require.NoError(t, err)
+		assert.Len(t, genres, 2)
+		assert.Equal(t, 2, next.findAlls)
+	})
+}
+
+// fakeQuestionRepository は呼び出し回数を数える問題のリポジトリ
+type fakeQuestionRepository struct {
+	questionRepositories.QuestionRepository
+	question *questionEntities.Question
+	gets     int
+}
+
+func (r *fakeQuestionRepository) GetByID(ctx context.Context, id int64) (*questionEntities.Question, error) {
+	r.gets++
+	copied := cloneQuestion(*r.question)
+	return &copied, nil
+}
+
+func (r *fakeQuestionRepository) Update(ctx context.Context, question *questionEntities.Question, tags []string, userToken string) error {
+	question.Version++
+	r.question = &questionEntities.Question{}
+	*r.question = cloneQuestion(*question)
+	return nil
+}
+
+func (r *fakeQuestionRepository) Delete(ctx context.Context, id int64, userToken string) error {
+	return nil
+}
+
+func TestQuestionRepository_GetByID(t *testing.T) {
+	ctx := context.Background()
+	correct := true
+	next := &fakeQuestionRepository{question: &questionEntities.Question{
+		ID:              1,
+		Title:           "地球は丸い？",
+		AcceptedAnswers: []string{"はい"},
+		CorrectBoolean:  &correct,
+		Version:         1,
+	}}
+	recorder := &lookupRecorder{}
+	repo := NewQuestionRepository(next, Options{TTL: time.Minute, MaxEntries: 10}, recorder)
+
+	question, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+
+	t.Run("返した問題を書き換えてもキャッシュに影響しない", func(t *testing.T) {
+		question.Title = "書き換え"
+		question.AcceptedAnswers[0] = "いいえ"
+		*question.CorrectBoolean = false
+
+		cached, err := repo.GetByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "地球は丸い？", cached.Title)
+		assert.Equal(t, []string{"はい"}, cached.AcceptedAnswers)
+		assert.True(t, *cached.CorrectBoolean)
+		assert.Equal(t, 1, next.gets)
+		assert.Equal(t, []string{"QuestionRepository.GetByID:miss", "QuestionRepository.GetByID:hit"}, recorder.lookups)
+	})
+
+	t.Run("更新したら読み込み直す", func(t *testing.T) {
+		question, err := repo.GetByID(ctx, 1)
+		require.NoError(t, err)
+		question.Title = "地球は平ら？"
+		require.NoError(t, repo.Update(ctx, question, nil, "token"))
+
+		updated, err := repo.GetByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "地球は平ら？", updated.Title)
+		assert.Equal(t, 2, updated.Version)
+		assert.Equal(t, 2, next.gets)
+	})
+
+	t.Run("削除したら読み込み直す", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, 1, "token"))
+
+		_, err := repo.GetByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 3, next.gets)
+	})
+
+	t.Run("Invalidate したら読み込み直す", func(t *testing.T) {
+		repo.(questionRepositories.Invalidator).Invalidate(1)
+
+		_, err := repo.GetByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 4, next.gets)
+	})
+}
@@ -7,33 +7,37 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"strconv"
 
 	"Shittaka_back/internal/domain/choices/entities"
 	"Shittaka_back/internal/domain/choices/repositories"
+	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/infrastructure/config"
+	"Shittaka_back/internal/infrastructure/httpclient"
 )
 
 // ChoiceRepositoryImpl はSupabaseを使用したChoiceRepositoryの実装
-type ChoiceRepositoryImpl struct{}
+type ChoiceRepositoryImpl struct {
+	supabase config.SupabaseConfig
+}
 
 // NewChoiceRepository は新しいChoiceRepositoryImplを作成
-func NewChoiceRepository() repositories.ChoiceRepository {
-	return &ChoiceRepositoryImpl{}
+func NewChoiceRepository(supabase config.SupabaseConfig) repositories.ChoiceRepository {
+	return &ChoiceRepositoryImpl{supabase: supabase}
 }
 
 // GetByQuestionID は問題IDで選択肢一覧を取得
 func (r *ChoiceRepositoryImpl) GetByQuestionID(ctx context.Context, questionID int64) ([]entities.Choice, error) {
-	url := fmt.Sprintf("%s/rest/v1/choices?question_id=eq.%d", os.Getenv("SUPABASE_URL"), questionID)
+	url := fmt.Sprintf("%s/rest/v1/choices?question_id=eq.%d&order=position.asc,id.asc", r.supabase.URL, questionID)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
-	req.Header.Set("Authorization", "Bearer "+os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+r.supabase.AnonKey)
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -46,7 +50,7 @@ func (r *ChoiceRepositoryImpl) GetByQuestionID(ctx context.Context, questionID i
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("find choices failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, shared.NewInfrastructureError("find choices", resp.StatusCode, string(body))
 	}
 
 	var choiceList []map[string]interface{}
@@ -62,12 +66,53 @@ func (r *ChoiceRepositoryImpl) GetByQuestionID(ctx context.Context, questionID i
 	return choices, nil
 }
 
+// GetByID はIDで選択肢を取得
+func (r *ChoiceRepositoryImpl) GetByID(ctx context.Context, id int64) (*entities.Choice, error) {
+	url := fmt.Sprintf("%s/rest/v1/choices?id=eq.%d", r.supabase.URL, id)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("apikey", r.supabase.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+r.supabase.AnonKey)
+
+	client := httpclient.New(r.supabase.RequestTimeout)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, shared.NewInfrastructureError("find choice", resp.StatusCode, string(body))
+	}
+
+	var choiceList []map[string]interface{}
+	if err := json.Unmarshal(body, &choiceList); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if len(choiceList) == 0 {
+		return nil, shared.NewDomainError("NOT_FOUND").With("resource", "choice")
+	}
+
+	result := mapToChoice(choiceList[0])
+	return &result, nil
+}
+
 // Create は新しい選択肢を作成
 func (r *ChoiceRepositoryImpl) Create(ctx context.Context, choice entities.Choice) (*entities.Choice, error) {
 	choiceData := map[string]interface{}{
 		"question_id": choice.QuestionID,
 		"text":        choice.Text,
 		"is_correct":  choice.IsCorrect,
+		"position":    choice.Position,
 	}
 
 	jsonData, err := json.Marshal(choiceData)
@@ -75,18 +120,18 @@ func (r *ChoiceRepositoryImpl) Create(ctx context.Context, choice entities.Choic
 		return nil, fmt.Errorf("failed to marshal choice data: %w", err)
 	}
 
-	url := os.Getenv("SUPABASE_URL") + "/rest/v1/choices"
+	url := r.supabase.URL + "/rest/v1/choices"
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
-	req.Header.Set("Authorization", "Bearer "+os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+r.supabase.AnonKey)
 	req.Header.Set("Prefer", "return=representation")
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -99,7 +144,7 @@ func (r *ChoiceRepositoryImpl) Create(ctx context.Context, choice entities.Choic
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("create choice failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, shared.NewInfrastructureError("create choice", resp.StatusCode, string(body))
 	}
 
 	var choiceList []map[string]interface{}
@@ -122,6 +167,7 @@ func (r *ChoiceRepositoryImpl) CreateWithAuth(ctx context.Context, choice entiti
 		"question_id": choice.QuestionID,
 		"text":        choice.Text,
 		"is_correct":  choice.IsCorrect,
+		"position":    choice.Position,
 	}
 
 	jsonData, err := json.Marshal(choiceData)
@@ -129,26 +175,18 @@ func (r *ChoiceRepositoryImpl) CreateWithAuth(ctx context.Context, choice entiti
 		return nil, fmt.Errorf("failed to marshal choice data: %w", err)
 	}
 
-	url := os.Getenv("SUPABASE_URL") + "/rest/v1/choices"
+	url := r.supabase.URL + "/rest/v1/choices"
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
 	req.Header.Set("Authorization", "Bearer "+userToken)
 	req.Header.Set("Prefer", "return=representation")
-	
-	// デバッグ用ログ
-	if len(userToken) > 20 {
-		fmt.Printf("CreateWithAuth - UserToken: %s...\n", userToken[:20])
-	} else {
-		fmt.Printf("CreateWithAuth - UserToken: %s\n", userToken)
-	}
-	fmt.Printf("CreateWithAuth - URL: %s\n", url)
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -161,7 +199,7 @@ func (r *ChoiceRepositoryImpl) CreateWithAuth(ctx context.Context, choice entiti
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("create choice failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, shared.NewInfrastructureError("create choice", resp.StatusCode, string(body))
 	}
 
 	var choiceList []map[string]interface{}
@@ -179,29 +217,34 @@ func (r *ChoiceRepositoryImpl) CreateWithAuth(ctx context.Context, choice entiti
 }
 
 // Update は選択肢を更新
+// 別の問題の選択肢を更新しないよう、問題IDも条件にする
 func (r *ChoiceRepositoryImpl) Update(ctx context.Context, choice entities.Choice) (*entities.Choice, error) {
 	choiceData := map[string]interface{}{
 		"text":       choice.Text,
 		"is_correct": choice.IsCorrect,
 	}
+	// 表示順は指定された場合のみ更新
+	if choice.Position > 0 {
+		choiceData["position"] = choice.Position
+	}
 
 	jsonData, err := json.Marshal(choiceData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal choice data: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/rest/v1/choices?id=eq.%d", os.Getenv("SUPABASE_URL"), choice.ID)
+	url := fmt.Sprintf("%s/rest/v1/choices?id=eq.%d&question_id=eq.%d", r.supabase.URL, choice.ID, choice.QuestionID)
 	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
-	req.Header.Set("Authorization", "Bearer "+os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+r.supabase.AnonKey)
 	req.Header.Set("Prefer", "return=representation")
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -214,7 +257,7 @@ func (r *ChoiceRepositoryImpl) Update(ctx context.Context, choice entities.Choic
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("update choice failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, shared.NewInfrastructureError("update choice", resp.StatusCode, string(body))
 	}
 
 	var choiceList []map[string]interface{}
@@ -223,7 +266,7 @@ func (r *ChoiceRepositoryImpl) Update(ctx context.Context, choice entities.Choic
 	}
 
 	if len(choiceList) == 0 {
-		return nil, fmt.Errorf("no choice returned from update operation")
+		return nil, shared.NewDomainError("NOT_FOUND").With("resource", "choice")
 	}
 
 	choiceResp := choiceList[0]
@@ -231,18 +274,19 @@ func (r *ChoiceRepositoryImpl) Update(ctx context.Context, choice entities.Choic
 	return &result, nil
 }
 
-// Delete は選択肢を削除
-func (r *ChoiceRepositoryImpl) Delete(ctx context.Context, id int64) error {
-	url := fmt.Sprintf("%s/rest/v1/choices?id=eq.%d", os.Getenv("SUPABASE_URL"), id)
+// Delete は問題の選択肢を削除
+// 別の問題の選択肢を削除しないよう、問題IDも条件にする
+func (r *ChoiceRepositoryImpl) Delete(ctx context.Context, questionID, id int64) error {
+	url := fmt.Sprintf("%s/rest/v1/choices?id=eq.%d&question_id=eq.%d", r.supabase.URL, id, questionID)
 	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
-	req.Header.Set("Authorization", "Bearer "+os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+r.supabase.AnonKey)
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
@@ -255,12 +299,144 @@ func (r *ChoiceRepositoryImpl) Delete(ctx context.Context, id int64) error {
 	}
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("delete choice failed with status %d: %s", resp.StatusCode, string(body))
+		return shared.NewInfrastructureError("delete choice", resp.StatusCode, string(body))
 	}
 
 	return nil
 }
 
+// Reorder は選択肢の表示順を reorder_question_choices 関数で1トランザクションとして振り直す
+// 関数は問題の選択肢の行をロックするため、同時の並べ替えが混ざらない
+// 読み込んだ後に選択肢が追加・削除されていた場合は関数が拒否し、choice_ids の検証エラーを返す
+func (r *ChoiceRepositoryImpl) Reorder(ctx context.Context, questionID int64, choiceIDs []int64, userToken string) ([]entities.Choice, error) {
+	jsonData, err := json.Marshal(map[string]interface{}{
+		"p_question_id": questionID,
+		"p_choice_ids":  choiceIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal choice data: %w", err)
+	}
+
+	url := r.supabase.URL + "/rest/v1/rpc/reorder_question_choices"
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("apikey", r.supabase.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+userToken)
+
+	client := httpclient.New(r.supabase.RequestTimeout)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusBadRequest && bytes.Contains(body, []byte(`"22023"`)) {
+		return nil, shared.NewValidationError("choice_ids", shared.ValidationIncomplete)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, shared.NewInfrastructureError("reorder choices", resp.StatusCode, string(body))
+	}
+
+	var choiceList []map[string]interface{}
+	if err := json.Unmarshal(body, &choiceList); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	choices := make([]entities.Choice, len(choiceList))
+	for i, choiceData := range choiceList {
+		choices[i] = mapToChoice(choiceData)
+	}
+
+	return choices, nil
+}
+
+// ReplaceAll は選択肢の差分を replace_question_choices 関数で1トランザクションとして適用
+// 関数は置き換え後の選択肢一覧を表示順で返す
+func (r *ChoiceRepositoryImpl) ReplaceAll(ctx context.Context, questionID int64, diff entities.ChoiceSetDiff, userToken string) ([]entities.Choice, error) {
+	inserts := make([]map[string]interface{}, 0, len(diff.Inserts))
+	for _, choice := range diff.Inserts {
+		inserts = append(inserts, map[string]interface{}{
+			"text":       choice.Text,
+			"is_correct": choice.IsCorrect,
+			"position":   choice.Position,
+		})
+	}
+
+	updates := make([]map[string]interface{}, 0, len(diff.Updates))
+	for _, choice := range diff.Updates {
+		updates = append(updates, map[string]interface{}{
+			"id":         choice.ID,
+			"text":       choice.Text,
+			"is_correct": choice.IsCorrect,
+			"position":   choice.Position,
+		})
+	}
+
+	deletes := diff.Deletes
+	if deletes == nil {
+		deletes = []int64{}
+	}
+
+	params := map[string]interface{}{
+		"p_question_id": questionID,
+		"p_inserts":     inserts,
+		"p_updates":     updates,
+		"p_deletes":     deletes,
+	}
+
+	jsonData, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal choice data: %w", err)
+	}
+
+	url := r.supabase.URL + "/rest/v1/rpc/replace_question_choices"
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("apikey", r.supabase.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+userToken)
+
+	client := httpclient.New(r.supabase.RequestTimeout)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, shared.NewInfrastructureError("replace choices", resp.StatusCode, string(body))
+	}
+
+	var choiceList []map[string]interface{}
+	if err := json.Unmarshal(body, &choiceList); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	choices := make([]entities.Choice, len(choiceList))
+	for i, choiceData := range choiceList {
+		choices[i] = mapToChoice(choiceData)
+	}
+
+	return choices, nil
+}
+
 // mapToChoice は map[string]interface{} を Choice エンティティに変換
 func mapToChoice(m map[string]interface{}) entities.Choice {
 	return entities.Choice{
@@ -268,6 +444,7 @@ func mapToChoice(m map[string]interface{}) entities.Choice {
 		QuestionID: getInt64(m, "question_id"),
 		Text:       getString(m, "text"),
 		IsCorrect:  getBool(m, "is_correct"),
+		Position:   int(getInt64(m, "position")),
 	}
 }
 
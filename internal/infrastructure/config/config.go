@@ -1,47 +1,343 @@
 package config
 
 // config.goはアプリケーションの設定を保持
+// 設定は 既定値 → YAMLファイル → 環境変数 の順に上書きして読み込み、起動時にまとめて検証する
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
 // Config はアプリケーションの設定を保持
+// 各項目の env タグは上書きに使う環境変数の名前
 type Config struct {
-	SupabaseURL        string
-	SupabaseServiceKey string
-	Port               string
+	Server      ServerConfig      `yaml:"server"`
+	Supabase    SupabaseConfig    `yaml:"supabase"`
+	Log         LogConfig         `yaml:"log"`
+	Tracing     TracingConfig     `yaml:"tracing"`
+	Health      HealthConfig      `yaml:"health"`
+	CORS        CORSConfig        `yaml:"cors"`
+	Security    SecurityConfig    `yaml:"security"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit"`
+	Auth        AuthConfig        `yaml:"auth"`
+	Idempotency IdempotencyConfig `yaml:"idempotency"`
+	Cache       CacheConfig       `yaml:"cache"`
+	Storage     StorageConfig     `yaml:"storage"`
+	Features    FeatureFlags      `yaml:"features"`
+}
+
+// ServerConfig はHTTPサーバーの設定
+type ServerConfig struct {
+	Port              string        `yaml:"port" env:"PORT"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"SERVER_READ_TIMEOUT"`               // リクエスト全体（ボディを含む）の読み込み
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT"` // リクエストヘッダーの読み込み
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`             // レスポンスの書き込み（ヘッダーの読み込み完了から）
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`               // keep-alive の接続を次のリクエストまで保持する時間
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT"`       // 終了時に処理中のリクエストとバックグラウンド処理の完了を待つ時間
+	ClientIPHeader    string        `yaml:"client_ip_header" env:"SERVER_CLIENT_IP_HEADER"`       // プロキシがクライアントのIPアドレスを設定するヘッダー（空なら接続元のアドレス）
+	MaxBodyBytes      int           `yaml:"max_body_bytes" env:"SERVER_MAX_BODY_BYTES"`           // リクエストボディの最大サイズ（バイト）
+}
+
+// SupabaseConfig はSupabaseへの接続設定
+type SupabaseConfig struct {
+	URL            string        `yaml:"url" env:"SUPABASE_URL"`
+	AnonKey        string        `yaml:"anon_key" env:"SUPABASE_ANON_KEY"`
+	ServiceRoleKey string        `yaml:"service_role_key" env:"SUPABASE_SERVICE_ROLE_KEY"`
+	JWTSecret      string        `yaml:"jwt_secret" env:"SUPABASE_JWT_SECRET"`           // アクセストークンの署名の検証に使う（任意、未設定ならレート制限などをユーザーごとに区別しない）
+	RequestTimeout time.Duration `yaml:"request_timeout" env:"SUPABASE_REQUEST_TIMEOUT"` // Supabaseへの1リクエストのタイムアウト
+}
+
+// LogConfig はログの設定
+type LogConfig struct {
+	Level string `yaml:"level" env:"LOG_LEVEL"` // debug / info / warn / error
+}
+
+// TracingConfig はトレースの設定
+type TracingConfig struct {
+	Exporter    string `yaml:"exporter" env:"OTEL_TRACES_EXPORTER"` // none / otlp / console
+	ServiceName string `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+}
+
+// HealthConfig はヘルスチェックの設定
+type HealthConfig struct {
+	ReadinessTimeout  time.Duration `yaml:"readiness_timeout" env:"HEALTH_READINESS_TIMEOUT"`     // 依存サービスごとの疎通確認のタイムアウト
+	ReadinessCacheTTL time.Duration `yaml:"readiness_cache_ttl" env:"HEALTH_READINESS_CACHE_TTL"` // 疎通確認の結果を使い回す時間
+}
+
+// CORSConfig はCORSの設定
+type CORSConfig struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS"`     // 環境変数ではカンマ区切り（https://*.vercel.app のようにサブドメインを * にできる）
+	AllowCredentials bool          `yaml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS"` // Cookieや Authorization ヘッダー付きのリクエストを許可する
+	MaxAge           time.Duration `yaml:"max_age" env:"CORS_MAX_AGE"`                     // プリフライトの結果をブラウザがキャッシュする時間
+}
+
+// SecurityConfig はセキュリティ関連のヘッダーの設定
+type SecurityConfig struct {
+	HSTSMaxAge time.Duration `yaml:"hsts_max_age" env:"SECURITY_HSTS_MAX_AGE"` // HTTPSのレスポンスに付与する HSTS の有効期間（0 なら付与しない）
+}
+
+// RateLimitConfig はレート制限の設定
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+
+	// 書き込み（GET 以外）の制限（ユーザーごと、未ログインならIPアドレスごと）
+	RequestsPerMinute int `yaml:"requests_per_minute" env:"RATE_LIMIT_REQUESTS_PER_MINUTE"`
+	Burst             int `yaml:"burst" env:"RATE_LIMIT_BURST"`
+
+	// ログイン・ユーザー登録・トークン更新の制限（IPアドレスごと）
+	AuthRequestsPerMinute int `yaml:"auth_requests_per_minute" env:"RATE_LIMIT_AUTH_REQUESTS_PER_MINUTE"`
+	AuthBurst             int `yaml:"auth_burst" env:"RATE_LIMIT_AUTH_BURST"`
+
+	// Routes はルートごとの制限（キーは "POST /api/auth/login" のようなバージョンなしのパターン、YAMLでのみ指定できる）
+	// 指定したルートには上の2つの代わりにこの制限を適用する（GET のルートにも指定できる）
+	Routes map[string]RateLimitRouteConfig `yaml:"routes"`
+}
+
+// RateLimitRouteConfig はルートごとのレート制限の設定
+type RateLimitRouteConfig struct {
+	RequestsPerMinute int `yaml:"requests_per_minute"`
+	Burst             int `yaml:"burst"`
+
+	// Key は制限を数える単位（ip ならIPアドレスごと、user なら署名を検証したユーザーごとで未ログインならIPアドレスごと）
+	Key string `yaml:"key"`
+}
+
+// AuthConfig はログインの失敗に対する保護の設定
+// 失敗が FreeAttempts 回を超えると、次のログインまで BackoffBase から倍々に延びる時間（最大 BackoffMax）待たせ、
+// LockoutThreshold 回に達すると LockoutDuration の間ログインできなくする
+// 失敗の回数はメールアドレスごととIPアドレスごとに数え、最後の失敗から LockoutDuration が過ぎると忘れる
+type AuthConfig struct {
+	FreeAttempts       int           `yaml:"free_attempts" env:"AUTH_FREE_ATTEMPTS"`
+	BackoffBase        time.Duration `yaml:"backoff_base" env:"AUTH_BACKOFF_BASE"`
+	BackoffMax         time.Duration `yaml:"backoff_max" env:"AUTH_BACKOFF_MAX"`
+	LockoutThreshold   int           `yaml:"lockout_threshold" env:"AUTH_LOCKOUT_THRESHOLD"`       // メールアドレスごとの回数
+	IPLockoutThreshold int           `yaml:"ip_lockout_threshold" env:"AUTH_IP_LOCKOUT_THRESHOLD"` // IPアドレスごとの回数（同じIPアドレスの利用者がいるため多めにする）
+	LockoutDuration    time.Duration `yaml:"lockout_duration" env:"AUTH_LOCKOUT_DURATION"`
+}
+
+// IdempotencyConfig は Idempotency-Key ヘッダーによる再送の重複防止の設定
+type IdempotencyConfig struct {
+	TTL time.Duration `yaml:"ttl" env:"IDEMPOTENCY_TTL"` // 最初のレスポンスを保存しておく時間
+}
+
+// CacheConfig はキャッシュの設定
+type CacheConfig struct {
+	PublicMaxAge time.Duration `yaml:"public_max_age" env:"CACHE_PUBLIC_MAX_AGE"` // 公開の読み取りをブラウザやCDNが再検証せずに使える時間（0 なら毎回 ETag で再検証させる）
+	TTL          time.Duration `yaml:"ttl" env:"CACHE_TTL"`                       // サーバーでジャンルと問題を保持する時間（0 ならキャッシュしない）
+	MaxEntries   int           `yaml:"max_entries" env:"CACHE_MAX_ENTRIES"`       // サーバーで保持する値の数の上限（種類ごと）
+}
+
+// StorageConfig はレート制限などの状態を保存する先の設定
+// supabase の場合、Idempotency-Key の記録は idempotency_keys テーブルに保存する（レート制限とログインの保護は未対応でメモリに保存する）
+type StorageConfig struct {
+	Backend string `yaml:"backend" env:"STORAGE_BACKEND"` // memory / supabase
+}
+
+// FeatureFlags は機能の有効・無効
+type FeatureFlags struct {
+	Metrics bool `yaml:"metrics" env:"FEATURE_METRICS"` // /metrics でメトリクスを公開する
+	Docs    bool `yaml:"docs" env:"FEATURE_DOCS"`       // /docs と /api/openapi.json でAPIドキュメントを公開する
+}
+
+// defaultConfigFile は CONFIG_FILE が未設定の場合に読み込む設定ファイル
+const defaultConfigFile = "config.yaml"
+
+// Default は既定値の設定を返す
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:              "8088",
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       120 * time.Second,
+			ShutdownTimeout:   20 * time.Second,
+			ClientIPHeader:    "Fly-Client-IP",
+			MaxBodyBytes:      1 << 20,
+		},
+		Supabase: SupabaseConfig{
+			RequestTimeout: 10 * time.Second,
+		},
+		Log:     LogConfig{Level: "info"},
+		Tracing: TracingConfig{Exporter: "none", ServiceName: "shittaka-back"},
+		Health: HealthConfig{
+			ReadinessTimeout:  2 * time.Second,
+			ReadinessCacheTTL: 5 * time.Second,
+		},
+		CORS: CORSConfig{
+			// ローカルの開発用とデプロイしたフロントエンドのオリジン
+			AllowedOrigins: []string{
+				"http://localhost:3000",
+				"http://127.0.0.1:3000",
+				"https://shittakakeijibanfront-web.vercel.app",
+			},
+			MaxAge: 10 * time.Minute,
+		},
+		Security: SecurityConfig{HSTSMaxAge: 365 * 24 * time.Hour},
+		RateLimit: RateLimitConfig{
+			Enabled:               true,
+			RequestsPerMinute:     120,
+			Burst:                 30,
+			AuthRequestsPerMinute: 10,
+			AuthBurst:             5,
+		},
+		Auth: AuthConfig{
+			FreeAttempts:       3,
+			BackoffBase:        time.Second,
+			BackoffMax:         time.Minute,
+			LockoutThreshold:   10,
+			IPLockoutThreshold: 50,
+			LockoutDuration:    15 * time.Minute,
+		},
+		Idempotency: IdempotencyConfig{TTL: 24 * time.Hour},
+		Cache:       CacheConfig{PublicMaxAge: 0, TTL: 30 * time.Second, MaxEntries: 1000},
+		Storage:     StorageConfig{Backend: "memory"},
+		Features:    FeatureFlags{Metrics: true, Docs: true},
+	}
 }
 
 // LoadConfig は設定を読み込む
+// 設定に問題があれば全ての問題をログに出力して終了する
 func LoadConfig() *Config {
 	// 環境変数を読み込み
 	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using system env")
+		slog.Info("no .env file found, using system env")
+	}
+
+	// CONFIG_FILE が未設定なら config.yaml を（あれば）読み込む
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		if _, err := os.Stat(defaultConfigFile); err == nil {
+			path = defaultConfigFile
+		}
 	}
 
-	// 必要な環境変数をチェック
-	supabaseURL := os.Getenv("SUPABASE_URL")
-	if supabaseURL == "" {
-		log.Fatal("SUPABASE_URL is required")
+	cfg, err := Load(path, os.LookupEnv)
+	if err != nil {
+		for _, problem := range problems(err) {
+			slog.Error("invalid configuration", "problem", problem)
+		}
+		os.Exit(1)
 	}
+	return cfg
+}
+
+// Load は既定値にYAMLファイル（path が空なら読み込まない）と環境変数を重ねて設定を作り、検証する
+// lookupEnv には os.LookupEnv を渡す（空の値が設定された環境変数と未設定の環境変数を区別するため）
+// 問題は全て errors.Join でまとめて返す
+func Load(path string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
 
-	supabaseServiceKey := os.Getenv("SUPABASE_SERVICE_ROLE_KEY")
-	if supabaseServiceKey == "" {
-		log.Fatal("SUPABASE_SERVICE_ROLE_KEY is required")
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		defer file.Close()
+
+		decoder := yaml.NewDecoder(file)
+		decoder.KnownFields(true)                                              // 綴りの誤りに気づけるよう、未知の項目はエラーにする
+		if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) { // 空のファイルは io.EOF になる
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
 	}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8088"
+	errs := applyEnv(reflect.ValueOf(cfg).Elem(), lookupEnv)
+	errs = append(errs, cfg.validate()...)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
 	}
+	return cfg, nil
+}
 
-	return &Config{
-		SupabaseURL:        supabaseURL,
-		SupabaseServiceKey: supabaseServiceKey,
-		Port:               port,
+// applyEnv は env タグの付いた項目を環境変数で上書きする
+// 空の値が設定された環境変数は項目を空（数値・時間なら0、真偽値なら false）にする
+// （CORS_ALLOWED_ORIGINS= のように、既定値やYAMLファイルの値を環境変数で消せるようにする）
+func applyEnv(v reflect.Value, lookupEnv func(string) (string, bool)) []error {
+	var errs []error
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			errs = append(errs, applyEnv(field, lookupEnv)...)
+			continue
+		}
+
+		key := t.Field(i).Tag.Get("env")
+		if key == "" {
+			continue
+		}
+		value, ok := lookupEnv(key)
+		if !ok {
+			continue
+		}
+		if value == "" {
+			field.Set(reflect.Zero(field.Type()))
+			continue
+		}
+		if err := setField(field, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	return errs
+}
+
+// setField は文字列の値を項目の型に変換して設定する
+func setField(field reflect.Value, value string) error {
+	switch field.Interface().(type) {
+	case string:
+		field.SetString(value)
+	case time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q (use a form like 10s or 1m30s)", value)
+		}
+		field.SetInt(int64(d))
+	case int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		field.SetInt(int64(n))
+	case bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		field.SetBool(b)
+	case []string:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported config type %s", field.Type())
+	}
+	return nil
+}
+
+// problems は Load が返したエラーを問題ごとに分ける
+func problems(err error) []string {
+	var joined interface{ Unwrap() []error }
+	if !errors.As(err, &joined) {
+		return []string{err.Error()}
+	}
+	var list []string
+	for _, e := range joined.Unwrap() {
+		list = append(list, e.Error())
 	}
+	return list
 }
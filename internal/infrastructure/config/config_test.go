@@ -0,0 +1,177 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// env は map を環境変数の代わりに使う
+func env(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+}
+
+// writeFile はテスト用の設定ファイルを作成する
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// requiredEnv は必須項目だけを設定した環境変数
+var requiredEnv = map[string]string{
+	"SUPABASE_URL":              "https://example.supabase.co",
+	"SUPABASE_ANON_KEY":         "anon-key",
+	"SUPABASE_SERVICE_ROLE_KEY": "service-role-key",
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("必須項目だけなら既定値で読み込む", func(t *testing.T) {
+		cfg, err := Load("", env(requiredEnv))
+		require.NoError(t, err)
+
+		assert.Equal(t, "8088", cfg.Server.Port)
+		assert.Equal(t, 10*time.Second, cfg.Supabase.RequestTimeout)
+		// 全てのオリジンではなく、開発用とデプロイしたフロントエンドのオリジンだけを許可する
+		assert.Equal(t, []string{"http://localhost:3000", "http://127.0.0.1:3000", "https://shittakakeijibanfront-web.vercel.app"}, cfg.CORS.AllowedOrigins)
+		assert.Equal(t, "memory", cfg.Storage.Backend)
+		assert.True(t, cfg.Features.Docs)
+	})
+
+	t.Run("環境変数はYAMLファイルより優先する", func(t *testing.T) {
+		path := writeFile(t, `
+server:
+  port: "9000"
+  read_timeout: 3s
+supabase:
+  url: https://file.supabase.co
+  anon_key: file-anon-key
+  service_role_key: file-service-role-key
+log:
+  level: debug
+rate_limit:
+  requests_per_minute: 60
+features:
+  docs: false
+`)
+		cfg, err := Load(path, env(map[string]string{
+			"PORT":                 "9100",
+			"CORS_ALLOWED_ORIGINS": "https://shittaka.app, https://admin.shittaka.app",
+			"FEATURE_METRICS":      "false",
+		}))
+		require.NoError(t, err)
+
+		assert.Equal(t, "9100", cfg.Server.Port)
+		assert.Equal(t, 3*time.Second, cfg.Server.ReadTimeout)
+		assert.Equal(t, "https://file.supabase.co", cfg.Supabase.URL)
+		assert.Equal(t, "debug", cfg.Log.Level)
+		assert.Equal(t, 60, cfg.RateLimit.RequestsPerMinute)
+		assert.Equal(t, 30, cfg.RateLimit.Burst)
+		assert.Equal(t, []string{"https://shittaka.app", "https://admin.shittaka.app"}, cfg.CORS.AllowedOrigins)
+		assert.False(t, cfg.Features.Docs)
+		assert.False(t, cfg.Features.Metrics)
+	})
+
+	t.Run("空の値を設定した環境変数は項目を空にする", func(t *testing.T) {
+		path := writeFile(t, `
+supabase:
+  jwt_secret: file-jwt-secret
+`)
+		values := map[string]string{"SUPABASE_JWT_SECRET": "", "CACHE_TTL": ""}
+		for k, v := range requiredEnv {
+			values[k] = v
+		}
+		cfg, err := Load(path, env(values))
+		require.NoError(t, err)
+
+		assert.Empty(t, cfg.Supabase.JWTSecret)
+		assert.Zero(t, cfg.Cache.TTL)
+
+		// 許可するオリジンを空にするとクロスオリジンのリクエストを許可しない（全て許可にはならない）
+		values["CORS_ALLOWED_ORIGINS"] = ""
+		cfg, err = Load("", env(values))
+		require.NoError(t, err)
+		assert.Empty(t, cfg.CORS.AllowedOrigins)
+
+		// 空にした結果が正しくない設定なら検証で拒否する
+		values["SUPABASE_URL"] = ""
+		_, err = Load("", env(values))
+		assert.Equal(t, []string{"SUPABASE_URL: is required"}, problems(err))
+	})
+
+	t.Run("未知の項目はエラー", func(t *testing.T) {
+		path := writeFile(t, "server:\n  prot: \"9000\"\n")
+		_, err := Load(path, env(requiredEnv))
+		assert.ErrorContains(t, err, "prot")
+	})
+
+	t.Run("サブドメインのワイルドカードを許可し、* と資格情報の組み合わせは拒否する", func(t *testing.T) {
+		values := map[string]string{"CORS_ALLOWED_ORIGINS": "https://shittaka.app,https://*.vercel.app", "CORS_ALLOW_CREDENTIALS": "true"}
+		for k, v := range requiredEnv {
+			values[k] = v
+		}
+		cfg, err := Load("", env(values))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://shittaka.app", "https://*.vercel.app"}, cfg.CORS.AllowedOrigins)
+
+		values["CORS_ALLOWED_ORIGINS"] = "*,https://shittaka.*.app"
+		_, err = Load("", env(values))
+		assert.Len(t, problems(err), 2)
+	})
+
+	t.Run("ルートごとのレート制限はYAMLで指定する", func(t *testing.T) {
+		path := writeFile(t, `
+rate_limit:
+  routes:
+    "POST /api/auth/login":
+      requests_per_minute: 5
+      burst: 1
+      key: ip
+`)
+		cfg, err := Load(path, env(requiredEnv))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]RateLimitRouteConfig{
+			"POST /api/auth/login": {RequestsPerMinute: 5, Burst: 1, Key: "ip"},
+		}, cfg.RateLimit.Routes)
+
+		path = writeFile(t, `
+rate_limit:
+  routes:
+    /api/auth/login:
+      requests_per_minute: 0
+      burst: 1
+      key: session
+`)
+		_, err = Load(path, env(requiredEnv))
+		assert.Equal(t, []string{
+			`rate_limit.routes[/api/auth/login]: must be a route pattern such as "POST /api/auth/login"`,
+			"rate_limit.routes[/api/auth/login].requests_per_minute: must be greater than 0",
+			`rate_limit.routes[/api/auth/login].key: must be one of [ip user] (got "session")`,
+		}, problems(err))
+	})
+
+	t.Run("全ての問題をまとめて返す", func(t *testing.T) {
+		_, err := Load("", env(map[string]string{
+			"SUPABASE_URL":         "example.supabase.co",
+			"SERVER_READ_TIMEOUT":  "15",
+			"LOG_LEVEL":            "verbose",
+			"CORS_ALLOWED_ORIGINS": "shittaka.app",
+			"STORAGE_BACKEND":      "redis",
+		}))
+		require.Error(t, err)
+
+		list := problems(err)
+		assert.Len(t, list, 7)
+		assert.Contains(t, list, `SERVER_READ_TIMEOUT: invalid duration "15" (use a form like 10s or 1m30s)`)
+		assert.Contains(t, list, "SUPABASE_ANON_KEY: is required")
+		assert.Contains(t, list, "SUPABASE_SERVICE_ROLE_KEY: is required")
+	})
+}
@@ -0,0 +1,175 @@
+package config
+
+// validate.goは設定の検証を定義
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 選択肢のある設定値
+var (
+	logLevels       = []string{"debug", "info", "warn", "error"}
+	traceExporters  = []string{"none", "otlp", "console", "stdout"}
+	storageBackends = []string{"memory", "supabase"}
+	rateLimitKeys   = []string{"ip", "user"}
+)
+
+// problemList は検証で見つかった問題を集める
+type problemList []error
+
+func (p *problemList) add(key, format string, args ...interface{}) {
+	*p = append(*p, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+}
+
+func (p *problemList) required(key, value string) {
+	if value == "" {
+		p.add(key, "is required")
+	}
+}
+
+func (p *problemList) positive(key string, d time.Duration) {
+	if d <= 0 {
+		p.add(key, "must be greater than 0")
+	}
+}
+
+func (p *problemList) oneOf(key, value string, choices []string) {
+	if !slices.Contains(choices, value) {
+		p.add(key, "must be one of %v (got %q)", choices, value)
+	}
+}
+
+// validate は設定を検証し、全ての問題を返す
+// 問題の項目名は上書きに使う環境変数の名前で示す
+func (c *Config) validate() []error {
+	var p problemList
+
+	// サーバー
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		p.add("PORT", "must be a port number between 1 and 65535 (got %q)", c.Server.Port)
+	}
+	p.positive("SERVER_READ_TIMEOUT", c.Server.ReadTimeout)
+	p.positive("SERVER_READ_HEADER_TIMEOUT", c.Server.ReadHeaderTimeout)
+	p.positive("SERVER_WRITE_TIMEOUT", c.Server.WriteTimeout)
+	p.positive("SERVER_IDLE_TIMEOUT", c.Server.IdleTimeout)
+	p.positive("SERVER_SHUTDOWN_TIMEOUT", c.Server.ShutdownTimeout)
+	if c.Server.MaxBodyBytes <= 0 {
+		p.add("SERVER_MAX_BODY_BYTES", "must be greater than 0")
+	}
+
+	// Supabase
+	p.required("SUPABASE_URL", c.Supabase.URL)
+	if c.Supabase.URL != "" {
+		if u, err := url.Parse(c.Supabase.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			p.add("SUPABASE_URL", "must be an http(s) URL (got %q)", c.Supabase.URL)
+		}
+	}
+	p.required("SUPABASE_ANON_KEY", c.Supabase.AnonKey)
+	p.required("SUPABASE_SERVICE_ROLE_KEY", c.Supabase.ServiceRoleKey)
+	p.positive("SUPABASE_REQUEST_TIMEOUT", c.Supabase.RequestTimeout)
+
+	// ログ・トレース
+	p.oneOf("LOG_LEVEL", c.Log.Level, logLevels)
+	p.oneOf("OTEL_TRACES_EXPORTER", c.Tracing.Exporter, traceExporters)
+	p.required("OTEL_SERVICE_NAME", c.Tracing.ServiceName)
+
+	// ヘルスチェック
+	p.positive("HEALTH_READINESS_TIMEOUT", c.Health.ReadinessTimeout)
+	if c.Health.ReadinessCacheTTL < 0 {
+		p.add("HEALTH_READINESS_CACHE_TTL", "must not be negative")
+	}
+
+	// CORS（空ならクロスオリジンのリクエストを許可しない）
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			if c.CORS.AllowCredentials {
+				p.add("CORS_ALLOWED_ORIGINS", "* cannot be used with CORS_ALLOW_CREDENTIALS (list the origins instead)")
+			}
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			p.add("CORS_ALLOWED_ORIGINS", "%q must be an origin like https://example.com or https://*.example.com", origin)
+			continue
+		}
+		// ワイルドカードはホストの先頭のラベルにだけ使える
+		if strings.Contains(u.Host, "*") && (!strings.HasPrefix(u.Host, "*.") || strings.Count(u.Host, "*") > 1) {
+			p.add("CORS_ALLOWED_ORIGINS", "%q may only use * as the first label of the host (like https://*.example.com)", origin)
+		}
+	}
+	if c.CORS.MaxAge < 0 {
+		p.add("CORS_MAX_AGE", "must not be negative")
+	}
+	if c.Security.HSTSMaxAge < 0 {
+		p.add("SECURITY_HSTS_MAX_AGE", "must not be negative")
+	}
+
+	// レート制限
+	if c.RateLimit.Enabled {
+		if c.RateLimit.RequestsPerMinute <= 0 {
+			p.add("RATE_LIMIT_REQUESTS_PER_MINUTE", "must be greater than 0")
+		}
+		if c.RateLimit.Burst <= 0 {
+			p.add("RATE_LIMIT_BURST", "must be greater than 0")
+		}
+		if c.RateLimit.AuthRequestsPerMinute <= 0 {
+			p.add("RATE_LIMIT_AUTH_REQUESTS_PER_MINUTE", "must be greater than 0")
+		}
+		if c.RateLimit.AuthBurst <= 0 {
+			p.add("RATE_LIMIT_AUTH_BURST", "must be greater than 0")
+		}
+		for _, pattern := range slices.Sorted(maps.Keys(c.RateLimit.Routes)) {
+			route := c.RateLimit.Routes[pattern]
+			name := "rate_limit.routes[" + pattern + "]"
+			if method, path, ok := strings.Cut(pattern, " "); !ok || method == "" || !strings.HasPrefix(path, "/") {
+				p.add(name, `must be a route pattern such as "POST /api/auth/login"`)
+			}
+			if route.RequestsPerMinute <= 0 {
+				p.add(name+".requests_per_minute", "must be greater than 0")
+			}
+			if route.Burst <= 0 {
+				p.add(name+".burst", "must be greater than 0")
+			}
+			p.oneOf(name+".key", route.Key, rateLimitKeys)
+		}
+	}
+
+	// ログインの保護
+	if c.Auth.FreeAttempts < 0 {
+		p.add("AUTH_FREE_ATTEMPTS", "must not be negative")
+	}
+	p.positive("AUTH_BACKOFF_BASE", c.Auth.BackoffBase)
+	if c.Auth.BackoffMax < c.Auth.BackoffBase {
+		p.add("AUTH_BACKOFF_MAX", "must not be less than AUTH_BACKOFF_BASE")
+	}
+	if c.Auth.LockoutThreshold <= c.Auth.FreeAttempts {
+		p.add("AUTH_LOCKOUT_THRESHOLD", "must be greater than AUTH_FREE_ATTEMPTS")
+	}
+	if c.Auth.IPLockoutThreshold <= c.Auth.FreeAttempts {
+		p.add("AUTH_IP_LOCKOUT_THRESHOLD", "must be greater than AUTH_FREE_ATTEMPTS")
+	}
+	p.positive("AUTH_LOCKOUT_DURATION", c.Auth.LockoutDuration)
+
+	// キャッシュ
+	if c.Cache.PublicMaxAge < 0 {
+		p.add("CACHE_PUBLIC_MAX_AGE", "must not be negative")
+	}
+	if c.Cache.TTL < 0 {
+		p.add("CACHE_TTL", "must not be negative")
+	}
+	if c.Cache.TTL > 0 && c.Cache.MaxEntries <= 0 {
+		p.add("CACHE_MAX_ENTRIES", "must be greater than 0")
+	}
+
+	// 保存先
+	p.positive("IDEMPOTENCY_TTL", c.Idempotency.TTL)
+	p.oneOf("STORAGE_BACKEND", c.Storage.Backend, storageBackends)
+
+	return p
+}
@@ -8,39 +8,60 @@ import (
 	"Shittaka_back/internal/application/auth/usecases"
 	profileUsecases "Shittaka_back/internal/application/profile/usecases"
 	"Shittaka_back/internal/domain/auth/services"
+	genreRepositories "Shittaka_back/internal/domain/genre/repositories"
+	questionRepositories "Shittaka_back/internal/domain/question/repositories"
+	authMemory "Shittaka_back/internal/infrastructure/auth/memory"
 	"Shittaka_back/internal/infrastructure/auth/supabase"
 	"Shittaka_back/internal/infrastructure/config"
+	"Shittaka_back/internal/infrastructure/metrics"
 	profileSupabase "Shittaka_back/internal/infrastructure/profile/supabase"
+	"Shittaka_back/internal/infrastructure/tracing"
 	"Shittaka_back/internal/presentation/http/handlers"
 )
 
 // Container は依存関係のコンテナ
 type Container struct {
 	Config         *config.Config
+	Metrics        *metrics.Prometheus
 	AuthHandler    *handlers.AuthHandler
 	ProfileHandler *handlers.ProfileHandler
+
+	// 複数の機能で共有するリポジトリ（同じプロセスの書き込みでキャッシュを捨てるため）
+	Genres    genreRepositories.GenreRepository
+	Questions questionRepositories.QuestionRepository
 }
 
-// NewContainer は新しいコンテナを作成
-func NewContainer() *Container {
-	// 設定を読み込み
-	cfg := config.LoadConfig()
+// NewContainer は読み込んだ設定から新しいコンテナを作成
+func NewContainer(cfg *config.Config) *Container {
+	// メトリクス（全ての機能で共有する）
+	recorder := metrics.NewPrometheus()
 
 	// 依存関係を構築（外側から内側へ）
 	// Auth関連
-	userRepo := supabase.NewUserRepository()
-	authService := services.NewAuthService(userRepo)
-	authUsecase := usecases.NewAuthUsecase(authService)
+	userRepo := tracing.NewUserRepository(metrics.NewUserRepository(supabase.NewUserRepository(cfg.Supabase), recorder))
+	loginThrottle := services.NewLoginThrottle(authMemory.NewLoginAttemptRepository(), services.LockoutPolicy{
+		FreeAttempts:       cfg.Auth.FreeAttempts,
+		BackoffBase:        cfg.Auth.BackoffBase,
+		BackoffMax:         cfg.Auth.BackoffMax,
+		LockoutThreshold:   cfg.Auth.LockoutThreshold,
+		IPLockoutThreshold: cfg.Auth.IPLockoutThreshold,
+		LockoutDuration:    cfg.Auth.LockoutDuration,
+	})
+	authService := services.NewAuthService(userRepo, loginThrottle)
+	authUsecase := usecases.NewAuthUsecase(authService, recorder)
 	authHandler := handlers.NewAuthHandler(authUsecase)
 
 	// Profile関連
-	profileRepo := profileSupabase.NewProfileRepository()
+	profileRepo := tracing.NewProfileRepository(metrics.NewProfileRepository(profileSupabase.NewProfileRepository(cfg.Supabase), recorder))
 	profileUsecase := profileUsecases.NewProfileUsecase(profileRepo)
 	profileHandler := handlers.NewProfileHandler(profileUsecase)
 
 	return &Container{
 		Config:         cfg,
+		Metrics:        recorder,
 		AuthHandler:    authHandler,
 		ProfileHandler: profileHandler,
+		Genres:         newGenreRepository(cfg, recorder),
+		Questions:      newQuestionRepository(cfg, recorder),
 	}
 }
@@ -2,16 +2,26 @@ package di
 
 import (
 	"Shittaka_back/internal/application/answer/usecases"
+	appMetrics "Shittaka_back/internal/application/metrics"
+	"Shittaka_back/internal/domain/answer/services"
+	questionRepositories "Shittaka_back/internal/domain/question/repositories"
 	"Shittaka_back/internal/infrastructure/answer/supabase"
+	choiceSupabase "Shittaka_back/internal/infrastructure/choice/supabase"
+	"Shittaka_back/internal/infrastructure/config"
+	"Shittaka_back/internal/infrastructure/metrics"
+	"Shittaka_back/internal/infrastructure/tracing"
 	"Shittaka_back/internal/presentation/http/handlers"
 )
 
 // NewAnswerHandler は新しいAnswerHandlerを作成
-func NewAnswerHandler() *handlers.AnswerHandler {
+// questionRepo はコンテナで共有するリポジトリ（Container.Questions）
+func NewAnswerHandler(cfg *config.Config, recorder appMetrics.Recorder, questionRepo questionRepositories.QuestionRepository) *handlers.AnswerHandler {
 	// 依存関係を構築（外側から内側へ）
-	answerRepo := supabase.NewAnswerRepository()
-	answerUsecase := usecases.NewAnswerUsecase(answerRepo)
+	answerRepo := tracing.NewAnswerRepository(metrics.NewAnswerRepository(supabase.NewAnswerRepository(cfg.Supabase), recorder))
+	choiceRepo := tracing.NewChoiceRepository(metrics.NewChoiceRepository(choiceSupabase.NewChoiceRepository(cfg.Supabase), recorder))
+	gradingService := services.NewGradingService()
+	answerUsecase := usecases.NewAnswerUsecase(answerRepo, questionRepo, choiceRepo, gradingService, recorder)
 	answerHandler := handlers.NewAnswerHandler(answerUsecase)
 
 	return answerHandler
-}
\ No newline at end of file
+}
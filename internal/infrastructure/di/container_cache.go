@@ -0,0 +1,39 @@
+package di
+
+// container_cache.goは複数の機能で共有するリポジトリ（読み込みをキャッシュする）の依存関係配線を定義
+
+import (
+	appMetrics "Shittaka_back/internal/application/metrics"
+	genreRepositories "Shittaka_back/internal/domain/genre/repositories"
+	questionRepositories "Shittaka_back/internal/domain/question/repositories"
+	"Shittaka_back/internal/infrastructure/cache"
+	"Shittaka_back/internal/infrastructure/config"
+	genreSupabase "Shittaka_back/internal/infrastructure/genre/supabase"
+	"Shittaka_back/internal/infrastructure/metrics"
+	questionSupabase "Shittaka_back/internal/infrastructure/question/supabase"
+	"Shittaka_back/internal/infrastructure/tracing"
+)
+
+// newGenreRepository はジャンルのリポジトリを構築する
+// CACHE_TTL が0より大きければ、読み込みをキャッシュする（キャッシュから返した呼び出しはメトリクスとトレースに記録しない）
+func newGenreRepository(cfg *config.Config, recorder appMetrics.Recorder) genreRepositories.GenreRepository {
+	repo := tracing.NewGenreRepository(metrics.NewGenreRepository(genreSupabase.NewGenreRepository(cfg.Supabase), recorder))
+	if cfg.Cache.TTL <= 0 {
+		return repo
+	}
+	return cache.NewGenreRepository(repo, cacheOptions(cfg), recorder)
+}
+
+// newQuestionRepository は問題のリポジトリを構築する
+// 書き込みで同じプロセスのキャッシュを捨てられるよう、問題・回答・選択肢の機能で共有する
+func newQuestionRepository(cfg *config.Config, recorder appMetrics.Recorder) questionRepositories.QuestionRepository {
+	repo := tracing.NewQuestionRepository(metrics.NewQuestionRepository(questionSupabase.NewQuestionRepository(cfg.Supabase), recorder))
+	if cfg.Cache.TTL <= 0 {
+		return repo
+	}
+	return cache.NewQuestionRepository(repo, cacheOptions(cfg), recorder)
+}
+
+func cacheOptions(cfg *config.Config) cache.Options {
+	return cache.Options{TTL: cfg.Cache.TTL, MaxEntries: cfg.Cache.MaxEntries}
+}
@@ -3,18 +3,24 @@ package di
 // container_choices.goは選択肢機能の依存関係配線を定義
 
 import (
+	appMetrics "Shittaka_back/internal/application/metrics"
 	"Shittaka_back/internal/domain/choices/services"
+	questionRepositories "Shittaka_back/internal/domain/question/repositories"
 	choiceSupabase "Shittaka_back/internal/infrastructure/choice/supabase"
+	"Shittaka_back/internal/infrastructure/config"
+	"Shittaka_back/internal/infrastructure/metrics"
+	"Shittaka_back/internal/infrastructure/tracing"
 	"Shittaka_back/internal/presentation/http/handlers"
 )
 
 // NewChoiceHandler は選択肢機能の依存関係を構築し、ハンドラーを返す
-func NewChoiceHandler() *handlers.ChoiceHandler {
-	// リポジトリ（Supabase HTTP実装）
-	choiceRepo := choiceSupabase.NewChoiceRepository()
+// questionRepo はコンテナで共有するリポジトリ（Container.Questions）
+func NewChoiceHandler(cfg *config.Config, recorder appMetrics.Recorder, questionRepo questionRepositories.QuestionRepository) *handlers.ChoiceHandler {
+	// リポジトリ（Supabase HTTP実装、呼び出しをメトリクスとトレースに記録する）
+	choiceRepo := tracing.NewChoiceRepository(metrics.NewChoiceRepository(choiceSupabase.NewChoiceRepository(cfg.Supabase), recorder))
 
 	// サービス
-	choiceService := services.NewChoiceService(choiceRepo)
+	choiceService := services.NewChoiceService(choiceRepo, questionRepo)
 
 	// ハンドラー
 	return handlers.NewChoiceHandler(choiceService)
@@ -0,0 +1,25 @@
+package di
+
+// container_health.goはヘルスチェック機能の依存関係配線を定義
+
+import (
+	healthUsecases "Shittaka_back/internal/application/health/usecases"
+	"Shittaka_back/internal/infrastructure/config"
+	healthSupabase "Shittaka_back/internal/infrastructure/health/supabase"
+	"Shittaka_back/internal/presentation/http/handlers"
+)
+
+// NewHealthHandler はヘルスチェック機能の依存関係を構築し、ハンドラーを返す
+func NewHealthHandler(cfg *config.Config) *handlers.HealthHandler {
+	// 疎通を確認する依存サービス
+	checkers := []healthUsecases.Checker{
+		healthSupabase.NewPostgRESTChecker(cfg.Supabase),
+		healthSupabase.NewGoTrueChecker(cfg.Supabase),
+	}
+
+	// ユースケース
+	usecase := healthUsecases.NewHealthUsecase(checkers, cfg.Health.ReadinessTimeout, cfg.Health.ReadinessCacheTTL)
+
+	// ハンドラー
+	return handlers.NewHealthHandler(usecase)
+}
@@ -0,0 +1,21 @@
+package di
+
+// container_idempotency.goは再送の重複防止の依存関係配線を定義
+
+import (
+	"Shittaka_back/internal/infrastructure/config"
+	idempotencyStore "Shittaka_back/internal/infrastructure/idempotency"
+	"Shittaka_back/internal/presentation/http/router"
+)
+
+// NewIdempotency は再送の重複防止の設定を構築する
+// 保存先が supabase なら全てのマシンで共有できる idempotency_keys テーブルに、それ以外はメモリに保存する
+func NewIdempotency(cfg *config.Config) router.Idempotency {
+	idempotency := router.Idempotency{TTL: cfg.Idempotency.TTL}
+	if cfg.Storage.Backend == "supabase" {
+		idempotency.Store = idempotencyStore.NewSupabaseStore(cfg.Supabase)
+	} else {
+		idempotency.Store = idempotencyStore.NewMemoryStore()
+	}
+	return idempotency
+}
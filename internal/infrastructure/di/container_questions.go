@@ -1,19 +1,25 @@
 package di
 
 import (
+	appMetrics "Shittaka_back/internal/application/metrics"
 	questionUsecases "Shittaka_back/internal/application/question/usecases"
-	questionSupabase "Shittaka_back/internal/infrastructure/question/supabase"
+	questionRepositories "Shittaka_back/internal/domain/question/repositories"
+	"Shittaka_back/internal/infrastructure/config"
+	"Shittaka_back/internal/infrastructure/metrics"
+	tagSupabase "Shittaka_back/internal/infrastructure/tag/supabase"
+	"Shittaka_back/internal/infrastructure/tracing"
 	"Shittaka_back/internal/presentation/http/handlers"
 )
 
 // NewQuestionHandler は問題機能の依存関係を構築し、ハンドラーを返す
-func NewQuestionHandler() *handlers.QuestionHandler {
-	// リポジトリ（Supabase 実装）
-	questionRepo := questionSupabase.NewQuestionRepository()
+// questionRepo はコンテナで共有するリポジトリ（Container.Questions）
+func NewQuestionHandler(cfg *config.Config, recorder appMetrics.Recorder, questionRepo questionRepositories.QuestionRepository) *handlers.QuestionHandler {
+	// リポジトリ（Supabase 実装、呼び出しをメトリクスとトレースに記録する）
+	tagRepo := tracing.NewTagRepository(metrics.NewTagRepository(tagSupabase.NewTagRepository(cfg.Supabase), recorder))
 
 	// ユースケース
-	usecase := questionUsecases.NewQuestionUsecase(questionRepo)
+	usecase := questionUsecases.NewQuestionUsecase(questionRepo, tagRepo, recorder)
 
 	// ハンドラー
 	return handlers.NewQuestionHandler(usecase)
-}
\ No newline at end of file
+}
@@ -0,0 +1,57 @@
+package di
+
+// container_ratelimit.goはレート制限の依存関係配線を定義
+
+import (
+	"log/slog"
+
+	"Shittaka_back/internal/application/ratelimit"
+	"Shittaka_back/internal/infrastructure/config"
+	ratelimitStore "Shittaka_back/internal/infrastructure/ratelimit"
+	"Shittaka_back/internal/presentation/http/middleware"
+	"Shittaka_back/internal/presentation/http/router"
+)
+
+// NewRateLimit はレート制限の設定を構築する（無効ならストアを設定しない）
+func NewRateLimit(cfg *config.Config) router.RateLimit {
+	if !cfg.RateLimit.Enabled {
+		return router.RateLimit{}
+	}
+
+	// バケットの共有ストアはまだないため、保存先の設定によらずメモリに保存する
+	if cfg.Storage.Backend != "memory" {
+		slog.Warn("shared rate limit store is not available, using memory", "backend", cfg.Storage.Backend)
+	}
+
+	// ルートごとの方針はルートごとに別のバケットで数える
+	routes := make(map[string]router.RoutePolicy, len(cfg.RateLimit.Routes))
+	for pattern, route := range cfg.RateLimit.Routes {
+		key := middleware.ClientIP
+		if route.Key == "user" {
+			key = middleware.UserOrClientIP
+		}
+		routes[pattern] = router.RoutePolicy{
+			Policy: ratelimit.Policy{
+				Name:              pattern,
+				RequestsPerMinute: route.RequestsPerMinute,
+				Burst:             route.Burst,
+			},
+			Key: key,
+		}
+	}
+
+	return router.RateLimit{
+		Store: ratelimitStore.NewMemoryStore(),
+		Auth: ratelimit.Policy{
+			Name:              "auth",
+			RequestsPerMinute: cfg.RateLimit.AuthRequestsPerMinute,
+			Burst:             cfg.RateLimit.AuthBurst,
+		},
+		Write: ratelimit.Policy{
+			Name:              "write",
+			RequestsPerMinute: cfg.RateLimit.RequestsPerMinute,
+			Burst:             cfg.RateLimit.Burst,
+		},
+		Routes: routes,
+	}
+}
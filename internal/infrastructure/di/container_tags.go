@@ -0,0 +1,25 @@
+package di
+
+// container_tags.goはタグ機能の依存関係配線を定義
+
+import (
+	appMetrics "Shittaka_back/internal/application/metrics"
+	tagUsecases "Shittaka_back/internal/application/tag/usecases"
+	"Shittaka_back/internal/infrastructure/config"
+	"Shittaka_back/internal/infrastructure/metrics"
+	tagSupabase "Shittaka_back/internal/infrastructure/tag/supabase"
+	"Shittaka_back/internal/infrastructure/tracing"
+	"Shittaka_back/internal/presentation/http/handlers"
+)
+
+// NewTagHandler はタグ機能の依存関係を構築し、ハンドラーを返す
+func NewTagHandler(cfg *config.Config, recorder appMetrics.Recorder) *handlers.TagHandler {
+	// リポジトリ（Supabase 実装、呼び出しをメトリクスとトレースに記録する）
+	tagRepo := tracing.NewTagRepository(metrics.NewTagRepository(tagSupabase.NewTagRepository(cfg.Supabase), recorder))
+
+	// ユースケース
+	usecase := tagUsecases.NewTagUsecase(tagRepo)
+
+	// ハンドラー
+	return handlers.NewTagHandler(usecase)
+}
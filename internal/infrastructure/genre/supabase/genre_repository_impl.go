@@ -10,20 +10,23 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"os"
 	"strconv"
 
 	"Shittaka_back/internal/domain/genre/entities"
 	"Shittaka_back/internal/domain/genre/repositories"
 	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/infrastructure/config"
+	"Shittaka_back/internal/infrastructure/httpclient"
 )
 
 // GenreRepositoryImpl はSupabaseを使用したGenreRepositoryの実装
-type GenreRepositoryImpl struct{}
+type GenreRepositoryImpl struct {
+	supabase config.SupabaseConfig
+}
 
 // NewGenreRepository は新しいGenreRepositoryImplを作成
-func NewGenreRepository() repositories.GenreRepository {
-	return &GenreRepositoryImpl{}
+func NewGenreRepository(supabase config.SupabaseConfig) repositories.GenreRepository {
+	return &GenreRepositoryImpl{supabase: supabase}
 }
 
 // Create は新しいジャンルを作成（RLS適用のためユーザートークンを使用）
@@ -37,18 +40,18 @@ func (r *GenreRepositoryImpl) Create(ctx context.Context, genre *entities.Genre,
 		return nil, fmt.Errorf("failed to marshal genre data: %w", err)
 	}
 
-	url := os.Getenv("SUPABASE_URL") + "/rest/v1/genres"
+	url := r.supabase.URL + "/rest/v1/genres"
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
 	req.Header.Set("Authorization", "Bearer "+userToken)
 	req.Header.Set("Prefer", "return=representation")
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -61,7 +64,7 @@ func (r *GenreRepositoryImpl) Create(ctx context.Context, genre *entities.Genre,
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("create genre failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, shared.NewInfrastructureError("create genre", resp.StatusCode, string(body))
 	}
 
 	var genreList []map[string]interface{}
@@ -82,16 +85,16 @@ func (r *GenreRepositoryImpl) Create(ctx context.Context, genre *entities.Genre,
 
 // FindByID はIDでジャンルを検索
 func (r *GenreRepositoryImpl) FindByID(ctx context.Context, id int64) (*entities.Genre, error) {
-	url := fmt.Sprintf("%s/rest/v1/genres?id=eq.%d", os.Getenv("SUPABASE_URL"), id)
+	url := fmt.Sprintf("%s/rest/v1/genres?id=eq.%d", r.supabase.URL, id)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
-	req.Header.Set("Authorization", "Bearer "+os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+r.supabase.AnonKey)
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -104,7 +107,7 @@ func (r *GenreRepositoryImpl) FindByID(ctx context.Context, id int64) (*entities
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("find genre failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, shared.NewInfrastructureError("find genre", resp.StatusCode, string(body))
 	}
 
 	var genreList []map[string]interface{}
@@ -113,7 +116,7 @@ func (r *GenreRepositoryImpl) FindByID(ctx context.Context, id int64) (*entities
 	}
 
 	if len(genreList) == 0 {
-		return nil, shared.NewDomainError("NOT_FOUND", "ジャンルが見つかりません")
+		return nil, shared.NewDomainError("NOT_FOUND").With("resource", "genre")
 	}
 
 	genreData := genreList[0]
@@ -125,16 +128,16 @@ func (r *GenreRepositoryImpl) FindByID(ctx context.Context, id int64) (*entities
 
 // FindAll は全てのジャンルを取得
 func (r *GenreRepositoryImpl) FindAll(ctx context.Context) ([]*entities.Genre, error) {
-	url := os.Getenv("SUPABASE_URL") + "/rest/v1/genres"
+	url := r.supabase.URL + "/rest/v1/genres"
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
-	req.Header.Set("Authorization", "Bearer "+os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+r.supabase.AnonKey)
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -147,7 +150,7 @@ func (r *GenreRepositoryImpl) FindAll(ctx context.Context) ([]*entities.Genre, e
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("find all genres failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, shared.NewInfrastructureError("find all genres", resp.StatusCode, string(body))
 	}
 
 	var genreList []map[string]interface{}
@@ -170,16 +173,16 @@ func (r *GenreRepositoryImpl) FindAll(ctx context.Context) ([]*entities.Genre, e
 func (r *GenreRepositoryImpl) FindByName(ctx context.Context, name string, userToken string) (*entities.Genre, error) {
 	// URLエンコーディングを適用
 	encodedName := url.QueryEscape(name)
-	apiURL := fmt.Sprintf("%s/rest/v1/genres?name=eq.%s", os.Getenv("SUPABASE_URL"), encodedName)
+	apiURL := fmt.Sprintf("%s/rest/v1/genres?name=eq.%s", r.supabase.URL, encodedName)
 	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
 	req.Header.Set("Authorization", "Bearer "+userToken)
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -192,7 +195,7 @@ func (r *GenreRepositoryImpl) FindByName(ctx context.Context, name string, userT
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("find genre by name failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, shared.NewInfrastructureError("find genre by name", resp.StatusCode, string(body))
 	}
 
 	var genreList []map[string]interface{}
@@ -201,7 +204,7 @@ func (r *GenreRepositoryImpl) FindByName(ctx context.Context, name string, userT
 	}
 
 	if len(genreList) == 0 {
-		return nil, shared.NewDomainError("NOT_FOUND", "ジャンルが見つかりません")
+		return nil, shared.NewDomainError("NOT_FOUND").With("resource", "genre")
 	}
 
 	genreData := genreList[0]
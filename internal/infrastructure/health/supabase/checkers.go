@@ -0,0 +1,69 @@
+package supabase
+
+// checkers.goはSupabaseの各サービスへの疎通確認を定義
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"Shittaka_back/internal/application/health/usecases"
+	"Shittaka_back/internal/infrastructure/config"
+	"Shittaka_back/internal/infrastructure/httpclient"
+)
+
+// endpointChecker はエンドポイントが 2xx を返すかどうかで疎通を確認する
+type endpointChecker struct {
+	name    string
+	url     string
+	apiKey  string
+	timeout time.Duration
+}
+
+// NewPostgRESTChecker はPostgREST（データベースAPI）への疎通を確認するCheckerを作成
+func NewPostgRESTChecker(supabase config.SupabaseConfig) usecases.Checker {
+	return &endpointChecker{
+		name:    "postgrest",
+		url:     supabase.URL + "/rest/v1/",
+		apiKey:  supabase.ServiceRoleKey,
+		timeout: supabase.RequestTimeout,
+	}
+}
+
+// NewGoTrueChecker はGoTrue（認証API）への疎通を確認するCheckerを作成
+func NewGoTrueChecker(supabase config.SupabaseConfig) usecases.Checker {
+	return &endpointChecker{
+		name:    "gotrue",
+		url:     supabase.URL + "/auth/v1/health",
+		apiKey:  supabase.AnonKey,
+		timeout: supabase.RequestTimeout,
+	}
+}
+
+func (c *endpointChecker) Name() string {
+	return c.name
+}
+
+func (c *endpointChecker) Check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("apikey", c.apiKey)
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := httpclient.New(c.timeout).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", c.name, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body) // 接続を再利用するため読み捨てる
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned status %d", c.name, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package httpclient
+
+// httpclient.goはSupabaseへのリクエストに使うHTTPクライアントを定義
+
+import (
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// transport はトレースを伝播するトランスポート（全てのクライアントで共有し、接続を再利用する）
+var transport = otelhttp.NewTransport(http.DefaultTransport)
+
+// New はSupabaseへのリクエストに使うHTTPクライアントを作成
+// timeout は1リクエスト（レスポンスボディの読み込みを含む）のタイムアウト（0なら無制限）
+// トレースが有効な場合はリクエストごとにクライアントスパンを作り、traceparent ヘッダーを付与する
+func New(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
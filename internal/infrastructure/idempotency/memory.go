@@ -0,0 +1,90 @@
+package idempotency
+
+// memory.goは Idempotency-Key の記録をプロセス内のメモリに保存するストアを定義
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"Shittaka_back/internal/application/idempotency"
+)
+
+// sweepInterval は期限切れの記録を削除する間隔
+const sweepInterval = time.Minute
+
+// entry は保存している記録と、その期限
+type entry struct {
+	record    idempotency.Record
+	expiresAt time.Time
+}
+
+// MemoryStore は記録をメモリに保存する idempotency.Store
+// 記録はプロセスごとになるため、複数のマシンで動かす場合は別のマシンに届いた再送を重複として扱えない
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*entry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore は新しいMemoryStoreを作成
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]*entry),
+		now:     time.Now,
+	}
+}
+
+// Reserve はキーを処理中として確保する（既に記録があればそれを返す）
+func (s *MemoryStore) Reserve(ctx context.Context, key, requestHash string, ttl time.Duration) (*idempotency.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	if e, ok := s.entries[key]; ok && now.Before(e.expiresAt) {
+		record := e.record
+		return &record, nil
+	}
+
+	s.entries[key] = &entry{
+		record:    idempotency.Record{RequestHash: requestHash},
+		expiresAt: now.Add(ttl),
+	}
+	return nil, nil
+}
+
+// Complete は処理を終えたレスポンスを保存する
+func (s *MemoryStore) Complete(ctx context.Context, key string, record idempotency.Record, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record.Completed = true
+	s.entries[key] = &entry{record: record, expiresAt: s.now().Add(ttl)}
+	return nil
+}
+
+// Release は確保を解除する
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// sweep は期限切れの記録を一定間隔で削除する
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
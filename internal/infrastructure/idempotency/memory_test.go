@@ -0,0 +1,60 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"Shittaka_back/internal/application/idempotency"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	t.Run("最初の確保はnilを返し、以降は処理中の記録を返す", func(t *testing.T) {
+		record, err := store.Reserve(ctx, "user:1:key", "hash", time.Minute)
+		require.NoError(t, err)
+		assert.Nil(t, record)
+
+		record, err = store.Reserve(ctx, "user:1:key", "hash", time.Minute)
+		require.NoError(t, err)
+		require.NotNil(t, record)
+		assert.Equal(t, "hash", record.RequestHash)
+		assert.False(t, record.Completed)
+	})
+
+	t.Run("完了したレスポンスをTTLの間返す", func(t *testing.T) {
+		require.NoError(t, store.Complete(ctx, "user:1:key", idempotency.Record{
+			RequestHash: "hash",
+			StatusCode:  201,
+			Body:        []byte(`{"id":1}`),
+		}, time.Hour))
+
+		now = now.Add(30 * time.Minute)
+		record, err := store.Reserve(ctx, "user:1:key", "hash", time.Minute)
+		require.NoError(t, err)
+		require.NotNil(t, record)
+		assert.True(t, record.Completed)
+		assert.Equal(t, 201, record.StatusCode)
+
+		now = now.Add(time.Hour)
+		record, err = store.Reserve(ctx, "user:1:key", "hash", time.Minute)
+		require.NoError(t, err)
+		assert.Nil(t, record)
+	})
+
+	t.Run("解除したキーは再び確保できる", func(t *testing.T) {
+		_, _ = store.Reserve(ctx, "user:2:key", "hash", time.Minute)
+		require.NoError(t, store.Release(ctx, "user:2:key"))
+
+		record, err := store.Reserve(ctx, "user:2:key", "hash", time.Minute)
+		require.NoError(t, err)
+		assert.Nil(t, record)
+	})
+}
@@ -0,0 +1,138 @@
+package idempotency
+
+// supabase.goは Idempotency-Key の記録をSupabaseの idempotency_keys テーブルに保存するストアを定義
+// 複数のマシンで動かす場合も、どのマシンに届いた再送にも最初のレスポンスを返せる
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"Shittaka_back/internal/application/idempotency"
+	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/infrastructure/config"
+	"Shittaka_back/internal/infrastructure/httpclient"
+)
+
+// SupabaseStore は記録をSupabaseのテーブルに保存する idempotency.Store
+// テーブルはRLSで保護し、サービスロールのキーで読み書きする
+type SupabaseStore struct {
+	supabase config.SupabaseConfig
+}
+
+// NewSupabaseStore は新しいSupabaseStoreを作成
+func NewSupabaseStore(supabase config.SupabaseConfig) *SupabaseStore {
+	return &SupabaseStore{supabase: supabase}
+}
+
+// reservation は reserve_idempotency_key 関数が返す行
+type reservation struct {
+	Reserved    bool              `json:"reserved"`
+	RequestHash string            `json:"request_hash"`
+	Completed   bool              `json:"completed"`
+	StatusCode  *int              `json:"status_code"`
+	Headers     map[string]string `json:"headers"`
+	Body        *string           `json:"body"`
+}
+
+// Reserve はキーを処理中として確保する（reserve_idempotency_key関数で1トランザクションとして実行）
+func (s *SupabaseStore) Reserve(ctx context.Context, key, requestHash string, ttl time.Duration) (*idempotency.Record, error) {
+	params := map[string]interface{}{
+		"p_key":          key,
+		"p_request_hash": requestHash,
+		"p_ttl_seconds":  int(ttl.Seconds()),
+	}
+
+	body, err := s.send(ctx, http.MethodPost, "/rest/v1/rpc/reserve_idempotency_key", params, "reserve idempotency key")
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []reservation
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("reserve idempotency key returned no rows")
+	}
+
+	row := rows[0]
+	if row.Reserved {
+		return nil, nil
+	}
+
+	record := &idempotency.Record{
+		RequestHash: row.RequestHash,
+		Completed:   row.Completed,
+		Header:      row.Headers,
+	}
+	if row.StatusCode != nil {
+		record.StatusCode = *row.StatusCode
+	}
+	if row.Body != nil {
+		record.Body = []byte(*row.Body)
+	}
+	return record, nil
+}
+
+// Complete は処理を終えたレスポンスを保存する
+func (s *SupabaseStore) Complete(ctx context.Context, key string, record idempotency.Record, ttl time.Duration) error {
+	values := map[string]interface{}{
+		"completed":   true,
+		"status_code": record.StatusCode,
+		"headers":     record.Header,
+		"body":        string(record.Body),
+		"expires_at":  time.Now().Add(ttl).UTC().Format(time.RFC3339),
+	}
+
+	_, err := s.send(ctx, http.MethodPatch, "/rest/v1/idempotency_keys?key=eq."+url.QueryEscape(key), values, "complete idempotency key")
+	return err
+}
+
+// Release は確保を解除する
+func (s *SupabaseStore) Release(ctx context.Context, key string) error {
+	_, err := s.send(ctx, http.MethodDelete, "/rest/v1/idempotency_keys?key=eq."+url.QueryEscape(key), nil, "release idempotency key")
+	return err
+}
+
+// send はサービスロールのキーでPostgRESTにリクエストを送信し、レスポンスボディを返す
+func (s *SupabaseStore) send(ctx context.Context, method, path string, payload interface{}, op string) ([]byte, error) {
+	var reader io.Reader
+	if payload != nil {
+		jsonData, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewBuffer(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.supabase.URL+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("apikey", s.supabase.ServiceRoleKey)
+	req.Header.Set("Authorization", "Bearer "+s.supabase.ServiceRoleKey)
+
+	resp, err := httpclient.New(s.supabase.RequestTimeout).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, shared.NewInfrastructureError(op, resp.StatusCode, string(body))
+	}
+	return body, nil
+}
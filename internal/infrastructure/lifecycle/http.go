@@ -0,0 +1,24 @@
+package lifecycle
+
+// http.goはHTTPサーバーをComponentとして扱う
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// HTTPServer はHTTPサーバーのComponentを返す
+// 停止時は新しい接続の受け付けをやめ、処理中のリクエストが終わるのを待つ
+func HTTPServer(server *http.Server) Component {
+	return Component{
+		Name: "http server",
+		Start: func(ctx context.Context) error {
+			if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		},
+		Stop: server.Shutdown,
+	}
+}
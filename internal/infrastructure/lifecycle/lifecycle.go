@@ -0,0 +1,124 @@
+package lifecycle
+
+// lifecycle.goはHTTPサーバーやバックグラウンド処理の起動と停止の順序を管理する
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Component は起動と停止を管理する部品
+type Component struct {
+	// Name はログとエラーに使う名前
+	Name string
+
+	// Start は部品を起動し、停止するまでブロックする（nil なら起動処理はなく、停止時に Stop だけを呼ぶ）
+	// 渡されるコンテキストは停止時にキャンセルされる
+	Start func(ctx context.Context) error
+
+	// Stop は部品を停止する（nil なら Start に渡したコンテキストのキャンセルだけで停止する）
+	// 渡されるコンテキストは停止の猶予時間が過ぎるとキャンセルされる
+	Stop func(ctx context.Context) error
+}
+
+// Manager は登録された部品をまとめて起動し、終了時に登録と逆の順で停止する
+// 例: トレースの送信 → バックグラウンド処理 → HTTPサーバーの順に登録すると、
+// HTTPサーバーが処理中のリクエストを返し終えてから、バックグラウンド処理とトレースの送信を止める
+type Manager struct {
+	shutdownTimeout time.Duration
+	components      []Component
+}
+
+// NewManager は新しいManagerを作成
+// shutdownTimeout は全ての部品の停止を待つ時間
+func NewManager(shutdownTimeout time.Duration) *Manager {
+	return &Manager{shutdownTimeout: shutdownTimeout}
+}
+
+// Add は部品を登録する（Run の前に呼ぶ）
+func (m *Manager) Add(component Component) {
+	m.components = append(m.components, component)
+}
+
+// running は起動中の部品
+type running struct {
+	Component
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Run は全ての部品を起動し、ctx がキャンセルされる（シグナルを受け取るなど）か、
+// いずれかの部品が失敗するまで待ってから、全ての部品を停止する
+// 部品の失敗と停止の失敗をまとめて返す（正常に停止した場合は nil）
+func (m *Manager) Run(ctx context.Context) error {
+	failures := make(chan error, len(m.components))
+	started := make([]*running, 0, len(m.components))
+
+	for _, component := range m.components {
+		startCtx, cancel := context.WithCancel(context.Background())
+		r := &running{Component: component, cancel: cancel, done: make(chan struct{})}
+		started = append(started, r)
+
+		if component.Start == nil {
+			close(r.done)
+			continue
+		}
+		go func() {
+			defer close(r.done)
+			if err := r.Start(startCtx); err != nil && !errors.Is(err, context.Canceled) {
+				failures <- fmt.Errorf("%s: %w", r.Name, err)
+			}
+		}()
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		slog.Info("shutting down", "reason", context.Cause(ctx))
+	case runErr = <-failures:
+		slog.Error("component failed, shutting down", "error", runErr)
+	}
+
+	stopErr := m.stop(started)
+
+	// 停止中に失敗した部品のエラーもあわせて返す
+	errs := []error{runErr, stopErr}
+	for {
+		select {
+		case err := <-failures:
+			errs = append(errs, err)
+		default:
+			return errors.Join(errs...)
+		}
+	}
+}
+
+// stop は起動中の部品を登録と逆の順で停止する
+func (m *Manager) stop(started []*running) error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(started) - 1; i >= 0; i-- {
+		r := started[i]
+		begin := time.Now()
+
+		if r.Stop != nil {
+			if err := r.Stop(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("stop %s: %w", r.Name, err))
+			}
+		}
+		r.cancel()
+
+		select {
+		case <-r.done:
+			slog.Info("component stopped", "component", r.Name, "duration_ms", time.Since(begin).Milliseconds())
+		case <-ctx.Done():
+			errs = append(errs, fmt.Errorf("stop %s: %w", r.Name, ctx.Err()))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,96 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stopLog は部品が停止した順序を記録する
+type stopLog struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (l *stopLog) add(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.names = append(l.names, name)
+}
+
+// worker はコンテキストがキャンセルされるまで動き続けるバックグラウンド処理
+func worker(name string, log *stopLog) Component {
+	return Component{
+		Name: name,
+		Start: func(ctx context.Context) error {
+			<-ctx.Done()
+			log.add(name)
+			return ctx.Err()
+		},
+	}
+}
+
+// hook は停止時の処理だけを行う部品
+func hook(name string, log *stopLog) Component {
+	return Component{
+		Name: name,
+		Stop: func(ctx context.Context) error {
+			log.add(name)
+			return nil
+		},
+	}
+}
+
+func TestManager(t *testing.T) {
+	t.Run("登録と逆の順で停止する", func(t *testing.T) {
+		log := &stopLog{}
+		m := NewManager(time.Second)
+		m.Add(hook("flusher", log))
+		m.Add(worker("scheduler", log))
+		m.Add(hook("server", log))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.NoError(t, m.Run(ctx))
+		assert.Equal(t, []string{"server", "scheduler", "flusher"}, log.names)
+	})
+
+	t.Run("部品が失敗したら全て停止して失敗を返す", func(t *testing.T) {
+		log := &stopLog{}
+		m := NewManager(time.Second)
+		m.Add(worker("scheduler", log))
+		m.Add(Component{
+			Name:  "server",
+			Start: func(ctx context.Context) error { return errors.New("address already in use") },
+		})
+
+		err := m.Run(context.Background())
+		assert.ErrorContains(t, err, "server: address already in use")
+		assert.Equal(t, []string{"scheduler"}, log.names)
+	})
+
+	t.Run("猶予時間内に停止しない部品はエラーにする", func(t *testing.T) {
+		m := NewManager(10 * time.Millisecond)
+		release := make(chan struct{})
+		defer close(release)
+		m.Add(Component{
+			Name: "stuck",
+			Start: func(ctx context.Context) error {
+				<-release
+				return nil
+			},
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := m.Run(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, "stop stuck")
+	})
+}
@@ -0,0 +1,64 @@
+package logging
+
+// logging.goはアプリケーション全体で使う構造化ロガー（log/slog のJSON出力）を組み立てる
+// 出力する全ての属性は redact.go の規則で秘匿情報を伏せてから書き込む
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// ContextAttr はコンテキストから取り出してログに付与する属性（リクエストIDなど）
+type ContextAttr struct {
+	Key   string
+	Value func(ctx context.Context) string
+}
+
+// New はJSON形式で出力するロガーを作成する
+// contextAttrs に指定した値は、ログ出力時のコンテキストにあれば全てのログに付与される
+func New(w io.Writer, level slog.Level, contextAttrs ...ContextAttr) *slog.Logger {
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redactAttr,
+	})
+	return slog.New(&contextHandler{Handler: handler, attrs: contextAttrs})
+}
+
+// ParseLevel はログレベルの文字列（debug / info / warn / error）を変換する（不明な場合は info）
+func ParseLevel(s string) slog.Level {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// contextHandler はコンテキストの値を属性として付与する slog.Handler
+type contextHandler struct {
+	slog.Handler
+	attrs []ContextAttr
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	for _, attr := range h.attrs {
+		if value := attr.Value(ctx); value != "" {
+			record.AddAttrs(slog.String(attr.Key, value))
+		}
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs), attrs: h.attrs}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name), attrs: h.attrs}
+}
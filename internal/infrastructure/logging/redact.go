@@ -0,0 +1,69 @@
+package logging
+
+// redact.goはトークン・パスワード・メールアドレスをログに書き込まないための伏せ字処理を定義
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// Redacted は伏せた値の代わりに出力する文字列
+const Redacted = "[REDACTED]"
+
+// sensitiveKeyParts は属性名にこれらを含む場合、値をまるごと伏せる
+var sensitiveKeyParts = []string{"token", "password", "secret", "authorization", "apikey", "api_key", "cookie", "email"}
+
+// sensitivePatterns は文字列中に現れた場合に伏せる値のパターン
+var sensitivePatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	// JSON中の秘匿項目（"password":"..." など）
+	{regexp.MustCompile(`(?i)("[a-z_]*(?:token|password|secret|email)[a-z_]*"\s*:\s*)"[^"]*"`), `${1}"` + Redacted + `"`},
+	// Authorization ヘッダーの値
+	{regexp.MustCompile(`(?i)(bearer\s+)\S+`), "${1}" + Redacted},
+	// JWT（ヘッダー・ペイロード・署名の3つをドットでつないだもの）
+	{regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), Redacted},
+	// メールアドレス
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), Redacted},
+}
+
+// Redact は文字列中のトークン・パスワード・メールアドレスを伏せ字に置き換える
+func Redact(s string) string {
+	for _, p := range sensitivePatterns {
+		s = p.pattern.ReplaceAllString(s, p.replacement)
+	}
+	return s
+}
+
+// IsSensitiveKey は属性名が秘匿情報を表すかどうかを判定する
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactAttr は slog の ReplaceAttr として全ての属性の秘匿情報を伏せる
+func redactAttr(groups []string, a slog.Attr) slog.Attr {
+	if IsSensitiveKey(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, Redact(a.Value.String()))
+	case slog.KindAny:
+		// error などは文字列にしてから伏せる
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, Redact(err.Error()))
+		}
+		return slog.String(a.Key, Redact(fmt.Sprint(a.Value.Any())))
+	}
+	return a
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"JWT", "token eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.sig-part", "token [REDACTED]"},
+		{"Authorizationヘッダー", "Authorization: Bearer abc.def", "Authorization: Bearer [REDACTED]"},
+		{"メールアドレス", "user user@example.com not found", "user [REDACTED] not found"},
+		{"JSONの秘匿項目", `{"email":"a@b.co","password":"p@ss","name":"太郎"}`, `{"email":"[REDACTED]","password":"[REDACTED]","name":"太郎"}`},
+		{"秘匿情報なし", "question 12 not found", "question 12 not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Redact(tt.input))
+		})
+	}
+}
+
+func TestNew_RedactsAndAddsContextAttrs(t *testing.T) {
+	type key struct{}
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo, ContextAttr{
+		Key:   "request_id",
+		Value: func(ctx context.Context) string { s, _ := ctx.Value(key{}).(string); return s },
+	})
+
+	ctx := context.WithValue(context.Background(), key{}, "req-1")
+	logger.InfoContext(ctx, "login failed",
+		"email", "user@example.com",
+		"refresh_token", "abc",
+		"error", errors.New(`supabase: {"password":"secret"}`),
+		"user_id", "user-1",
+	)
+
+	out := buf.String()
+	assert.Contains(t, out, `"request_id":"req-1"`)
+	assert.Contains(t, out, `"user_id":"user-1"`)
+	assert.NotContains(t, out, "user@example.com")
+	assert.NotContains(t, out, `"abc"`)
+	assert.NotContains(t, out, "secret")
+}
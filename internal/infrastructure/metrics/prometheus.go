@@ -0,0 +1,150 @@
+package metrics
+
+// prometheus.goはPrometheus形式でメトリクスを記録・公開するRecorderを定義
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	appMetrics "Shittaka_back/internal/application/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace はメトリクス名の接頭辞
+const namespace = "shittaka"
+
+// Prometheus はPrometheusのレジストリにメトリクスを記録する Recorder
+// 記録したメトリクスは ServeHTTP で /metrics として公開する
+type Prometheus struct {
+	registry *prometheus.Registry
+	handler  http.Handler
+
+	httpRequests        *prometheus.CounterVec
+	httpRequestDuration *prometheus.HistogramVec
+	repositoryDuration  *prometheus.HistogramVec
+	repositoryErrors    *prometheus.CounterVec
+	answersSubmitted    *prometheus.CounterVec
+	signups             prometheus.Counter
+	questionsCreated    prometheus.Counter
+	cacheLookups        *prometheus.CounterVec
+}
+
+var _ appMetrics.Recorder = (*Prometheus)(nil)
+
+// NewPrometheus は新しいPrometheusを作成
+// レジストリはプロセス全体のデフォルトではなく専用のものを使う（テストで複数作成できるようにするため）
+func NewPrometheus() *Prometheus {
+	p := &Prometheus{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		repositoryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "supabase_request_duration_seconds",
+			Help:      "Supabase call latency by repository and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"repository", "method"}),
+		repositoryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "supabase_request_errors_total",
+			Help:      "Number of failed Supabase calls by repository and method.",
+		}, []string{"repository", "method"}),
+		answersSubmitted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "answers_submitted_total",
+			Help:      "Number of submitted answers by result (correct or incorrect).",
+		}, []string{"result"}),
+		signups: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "signups_total",
+			Help:      "Number of user signups.",
+		}),
+		questionsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "questions_created_total",
+			Help:      "Number of created questions.",
+		}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_lookups_total",
+			Help:      "Number of read cache lookups by cache and result (hit, miss or shared).",
+		}, []string{"cache", "result"}),
+	}
+
+	p.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		p.httpRequests,
+		p.httpRequestDuration,
+		p.repositoryDuration,
+		p.repositoryErrors,
+		p.answersSubmitted,
+		p.signups,
+		p.questionsCreated,
+		p.cacheLookups,
+	)
+
+	// 正誤のラベルは回答がなくても0として出力する
+	p.answersSubmitted.WithLabelValues("correct")
+	p.answersSubmitted.WithLabelValues("incorrect")
+
+	p.handler = promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{})
+	return p
+}
+
+// ServeHTTP はメトリクスをPrometheusのテキスト形式で返す
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.handler.ServeHTTP(w, r)
+}
+
+// ObserveHTTPRequest はHTTPリクエストの処理結果と処理時間を記録する
+func (p *Prometheus) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	statusLabel := strconv.Itoa(status)
+	p.httpRequests.WithLabelValues(method, route, statusLabel).Inc()
+	p.httpRequestDuration.WithLabelValues(method, route, statusLabel).Observe(duration.Seconds())
+}
+
+// ObserveRepositoryCall はリポジトリ（Supabase）呼び出しの処理時間と失敗を記録する
+func (p *Prometheus) ObserveRepositoryCall(repository, method string, duration time.Duration, err error) {
+	p.repositoryDuration.WithLabelValues(repository, method).Observe(duration.Seconds())
+	if err != nil {
+		p.repositoryErrors.WithLabelValues(repository, method).Inc()
+	}
+}
+
+// AnswerSubmitted は回答の提出を正誤とあわせて記録する
+func (p *Prometheus) AnswerSubmitted(correct bool) {
+	result := "incorrect"
+	if correct {
+		result = "correct"
+	}
+	p.answersSubmitted.WithLabelValues(result).Inc()
+}
+
+// UserSignedUp はユーザー登録を記録する
+func (p *Prometheus) UserSignedUp() {
+	p.signups.Inc()
+}
+
+// QuestionCreated は問題の作成を記録する
+func (p *Prometheus) QuestionCreated() {
+	p.questionsCreated.Inc()
+}
+
+// ObserveCacheLookup はキャッシュの参照結果を記録する
+func (p *Prometheus) ObserveCacheLookup(cache, result string) {
+	p.cacheLookups.WithLabelValues(cache, result).Inc()
+}
@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	genreEntities "Shittaka_back/internal/domain/genre/entities"
+	"Shittaka_back/internal/domain/shared"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scrape は /metrics の出力を取得する
+func scrape(t *testing.T, p *Prometheus) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestPrometheus(t *testing.T) {
+	p := NewPrometheus()
+
+	p.ObserveHTTPRequest(http.MethodGet, "GET /api/questions/{id}", http.StatusOK, 30*time.Millisecond)
+	p.AnswerSubmitted(true)
+	p.AnswerSubmitted(false)
+	p.AnswerSubmitted(false)
+	p.UserSignedUp()
+	p.QuestionCreated()
+	p.ObserveCacheLookup("GenreRepository.FindAll", "hit")
+
+	body := scrape(t, p)
+	assert.Contains(t, body, `shittaka_http_requests_total{method="GET",route="GET /api/questions/{id}",status="200"} 1`)
+	assert.Contains(t, body, `shittaka_http_request_duration_seconds_count{method="GET",route="GET /api/questions/{id}",status="200"} 1`)
+	assert.Contains(t, body, `shittaka_answers_submitted_total{result="correct"} 1`)
+	assert.Contains(t, body, `shittaka_answers_submitted_total{result="incorrect"} 2`)
+	assert.Contains(t, body, `shittaka_signups_total 1`)
+	assert.Contains(t, body, `shittaka_questions_created_total 1`)
+	assert.Contains(t, body, `shittaka_cache_lookups_total{cache="GenreRepository.FindAll",result="hit"} 1`)
+}
+
+// fakeGenreRepository は指定したエラーを返す GenreRepository
+type fakeGenreRepository struct {
+	err error
+}
+
+func (r *fakeGenreRepository) Create(ctx context.Context, genre *genreEntities.Genre, userToken string) (*genreEntities.Genre, error) {
+	return genre, r.err
+}
+
+func (r *fakeGenreRepository) FindByID(ctx context.Context, id int64) (*genreEntities.Genre, error) {
+	return nil, r.err
+}
+
+func (r *fakeGenreRepository) FindAll(ctx context.Context) ([]*genreEntities.Genre, error) {
+	return nil, r.err
+}
+
+func (r *fakeGenreRepository) FindByName(ctx context.Context, name string, userToken string) (*genreEntities.Genre, error) {
+	return nil, r.err
+}
+
+func TestInstrumentedRepository(t *testing.T) {
+	p := NewPrometheus()
+
+	failing := NewGenreRepository(&fakeGenreRepository{err: errors.New("connection refused")}, p)
+	_, _ = failing.FindAll(context.Background())
+
+	// NOT_FOUND はSupabaseの呼び出し自体は成功しているため失敗に数えない
+	notFound := NewGenreRepository(&fakeGenreRepository{err: shared.NewDomainError("NOT_FOUND")}, p)
+	_, err := notFound.FindByID(context.Background(), 1)
+	assert.Error(t, err, "エラーはそのまま呼び出し元に返す")
+
+	body := scrape(t, p)
+	assert.Contains(t, body, `shittaka_supabase_request_duration_seconds_count{method="FindAll",repository="GenreRepository"} 1`)
+	assert.Contains(t, body, `shittaka_supabase_request_errors_total{method="FindAll",repository="GenreRepository"} 1`)
+	assert.Contains(t, body, `shittaka_supabase_request_duration_seconds_count{method="FindByID",repository="GenreRepository"} 1`)
+	assert.NotContains(t, body, `shittaka_supabase_request_errors_total{method="FindByID"`)
+}
@@ -0,0 +1,378 @@
+package metrics
+
+// repositories.goはリポジトリの呼び出しごとに処理時間と失敗を記録するデコレーターを定義
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	appMetrics "Shittaka_back/internal/application/metrics"
+	answerEntities "Shittaka_back/internal/domain/answer/entities"
+	answerRepositories "Shittaka_back/internal/domain/answer/repositories"
+	authEntities "Shittaka_back/internal/domain/auth/entities"
+	authRepositories "Shittaka_back/internal/domain/auth/repositories"
+	choiceEntities "Shittaka_back/internal/domain/choices/entities"
+	choiceRepositories "Shittaka_back/internal/domain/choices/repositories"
+	genreEntities "Shittaka_back/internal/domain/genre/entities"
+	genreRepositories "Shittaka_back/internal/domain/genre/repositories"
+	profileEntities "Shittaka_back/internal/domain/profile/entities"
+	profileRepositories "Shittaka_back/internal/domain/profile/repositories"
+	questionEntities "Shittaka_back/internal/domain/question/entities"
+	questionRepositories "Shittaka_back/internal/domain/question/repositories"
+	"Shittaka_back/internal/domain/shared"
+	tagEntities "Shittaka_back/internal/domain/tag/entities"
+	tagRepositories "Shittaka_back/internal/domain/tag/repositories"
+)
+
+// repositoryObserver はリポジトリの呼び出しを Recorder に記録する
+type repositoryObserver struct {
+	recorder   appMetrics.Recorder
+	repository string
+}
+
+// observe は呼び出しの処理時間を記録する
+// NOT_FOUND などのドメインエラーはSupabaseへの呼び出し自体は成功しているため失敗に数えない
+func (o repositoryObserver) observe(method string, start time.Time, err error) {
+	var domainErr shared.DomainError
+	if errors.As(err, &domainErr) {
+		err = nil
+	}
+	o.recorder.ObserveRepositoryCall(o.repository, method, time.Since(start), err)
+}
+
+// instrumentedAnswerRepository は AnswerRepository の呼び出しを記録する
+type instrumentedAnswerRepository struct {
+	next answerRepositories.AnswerRepository
+	repositoryObserver
+}
+
+// NewAnswerRepository は AnswerRepository の呼び出しを記録するデコレーターを作成
+func NewAnswerRepository(next answerRepositories.AnswerRepository, recorder appMetrics.Recorder) answerRepositories.AnswerRepository {
+	return &instrumentedAnswerRepository{next: next, repositoryObserver: repositoryObserver{recorder: recorder, repository: "AnswerRepository"}}
+}
+
+func (r *instrumentedAnswerRepository) Create(ctx context.Context, answer *answerEntities.Answer, userToken string) (*answerEntities.Answer, error) {
+	start := time.Now()
+	result, err := r.next.Create(ctx, answer, userToken)
+	r.observe("Create", start, err)
+	return result, err
+}
+
+func (r *instrumentedAnswerRepository) GetByUserID(ctx context.Context, userID string) ([]*answerEntities.Answer, error) {
+	start := time.Now()
+	result, err := r.next.GetByUserID(ctx, userID)
+	r.observe("GetByUserID", start, err)
+	return result, err
+}
+
+func (r *instrumentedAnswerRepository) GetByQuestionID(ctx context.Context, questionID int64) ([]*answerEntities.Answer, error) {
+	start := time.Now()
+	result, err := r.next.GetByQuestionID(ctx, questionID)
+	r.observe("GetByQuestionID", start, err)
+	return result, err
+}
+
+// instrumentedUserRepository は UserRepository の呼び出しを記録する
+type instrumentedUserRepository struct {
+	next authRepositories.UserRepository
+	repositoryObserver
+}
+
+// NewUserRepository は UserRepository の呼び出しを記録するデコレーターを作成
+func NewUserRepository(next authRepositories.UserRepository, recorder appMetrics.Recorder) authRepositories.UserRepository {
+	return &instrumentedUserRepository{next: next, repositoryObserver: repositoryObserver{recorder: recorder, repository: "UserRepository"}}
+}
+
+func (r *instrumentedUserRepository) Create(ctx context.Context, email, password string, metadata map[string]interface{}) (*authEntities.User, error) {
+	start := time.Now()
+	result, err := r.next.Create(ctx, email, password, metadata)
+	r.observe("Create", start, err)
+	return result, err
+}
+
+func (r *instrumentedUserRepository) Authenticate(ctx context.Context, email, password string) (*authRepositories.AuthResult, error) {
+	start := time.Now()
+	result, err := r.next.Authenticate(ctx, email, password)
+	r.observe("Authenticate", start, err)
+	return result, err
+}
+
+func (r *instrumentedUserRepository) FindByID(ctx context.Context, id string) (*authEntities.User, error) {
+	start := time.Now()
+	result, err := r.next.FindByID(ctx, id)
+	r.observe("FindByID", start, err)
+	return result, err
+}
+
+func (r *instrumentedUserRepository) FindByEmail(ctx context.Context, email string) (*authEntities.User, error) {
+	start := time.Now()
+	result, err := r.next.FindByEmail(ctx, email)
+	r.observe("FindByEmail", start, err)
+	return result, err
+}
+
+func (r *instrumentedUserRepository) Update(ctx context.Context, user *authEntities.User) error {
+	start := time.Now()
+	err := r.next.Update(ctx, user)
+	r.observe("Update", start, err)
+	return err
+}
+
+func (r *instrumentedUserRepository) Delete(ctx context.Context, id string) error {
+	start := time.Now()
+	err := r.next.Delete(ctx, id)
+	r.observe("Delete", start, err)
+	return err
+}
+
+func (r *instrumentedUserRepository) Logout(ctx context.Context, token string) error {
+	start := time.Now()
+	err := r.next.Logout(ctx, token)
+	r.observe("Logout", start, err)
+	return err
+}
+
+func (r *instrumentedUserRepository) GetCurrentUser(ctx context.Context, token string) (*authEntities.User, error) {
+	start := time.Now()
+	result, err := r.next.GetCurrentUser(ctx, token)
+	r.observe("GetCurrentUser", start, err)
+	return result, err
+}
+
+func (r *instrumentedUserRepository) Refresh(ctx context.Context, refreshToken string) (*authRepositories.AuthResult, error) {
+	start := time.Now()
+	result, err := r.next.Refresh(ctx, refreshToken)
+	r.observe("Refresh", start, err)
+	return result, err
+}
+
+// instrumentedChoiceRepository は ChoiceRepository の呼び出しを記録する
+type instrumentedChoiceRepository struct {
+	next choiceRepositories.ChoiceRepository
+	repositoryObserver
+}
+
+// NewChoiceRepository は ChoiceRepository の呼び出しを記録するデコレーターを作成
+func NewChoiceRepository(next choiceRepositories.ChoiceRepository, recorder appMetrics.Recorder) choiceRepositories.ChoiceRepository {
+	return &instrumentedChoiceRepository{next: next, repositoryObserver: repositoryObserver{recorder: recorder, repository: "ChoiceRepository"}}
+}
+
+func (r *instrumentedChoiceRepository) GetByQuestionID(ctx context.Context, questionID int64) ([]choiceEntities.Choice, error) {
+	start := time.Now()
+	result, err := r.next.GetByQuestionID(ctx, questionID)
+	r.observe("GetByQuestionID", start, err)
+	return result, err
+}
+
+func (r *instrumentedChoiceRepository) GetByID(ctx context.Context, id int64) (*choiceEntities.Choice, error) {
+	start := time.Now()
+	result, err := r.next.GetByID(ctx, id)
+	r.observe("GetByID", start, err)
+	return result, err
+}
+
+func (r *instrumentedChoiceRepository) Create(ctx context.Context, choice choiceEntities.Choice) (*choiceEntities.Choice, error) {
+	start := time.Now()
+	result, err := r.next.Create(ctx, choice)
+	r.observe("Create", start, err)
+	return result, err
+}
+
+func (r *instrumentedChoiceRepository) CreateWithAuth(ctx context.Context, choice choiceEntities.Choice, userToken string) (*choiceEntities.Choice, error) {
+	start := time.Now()
+	result, err := r.next.CreateWithAuth(ctx, choice, userToken)
+	r.observe("CreateWithAuth", start, err)
+	return result, err
+}
+
+func (r *instrumentedChoiceRepository) Update(ctx context.Context, choice choiceEntities.Choice) (*choiceEntities.Choice, error) {
+	start := time.Now()
+	result, err := r.next.Update(ctx, choice)
+	r.observe("Update", start, err)
+	return result, err
+}
+
+func (r *instrumentedChoiceRepository) Delete(ctx context.Context, questionID, id int64) error {
+	start := time.Now()
+	err := r.next.Delete(ctx, questionID, id)
+	r.observe("Delete", start, err)
+	return err
+}
+
+func (r *instrumentedChoiceRepository) Reorder(ctx context.Context, questionID int64, choiceIDs []int64, userToken string) ([]choiceEntities.Choice, error) {
+	start := time.Now()
+	result, err := r.next.Reorder(ctx, questionID, choiceIDs, userToken)
+	r.observe("Reorder", start, err)
+	return result, err
+}
+
+func (r *instrumentedChoiceRepository) ReplaceAll(ctx context.Context, questionID int64, diff choiceEntities.ChoiceSetDiff, userToken string) ([]choiceEntities.Choice, error) {
+	start := time.Now()
+	result, err := r.next.ReplaceAll(ctx, questionID, diff, userToken)
+	r.observe("ReplaceAll", start, err)
+	return result, err
+}
+
+// instrumentedGenreRepository は GenreRepository の呼び出しを記録する
+type instrumentedGenreRepository struct {
+	next genreRepositories.GenreRepository
+	repositoryObserver
+}
+
+// NewGenreRepository は GenreRepository の呼び出しを記録するデコレーターを作成
+func NewGenreRepository(next genreRepositories.GenreRepository, recorder appMetrics.Recorder) genreRepositories.GenreRepository {
+	return &instrumentedGenreRepository{next: next, repositoryObserver: repositoryObserver{recorder: recorder, repository: "GenreRepository"}}
+}
+
+func (r *instrumentedGenreRepository) Create(ctx context.Context, genre *genreEntities.Genre, userToken string) (*genreEntities.Genre, error) {
+	start := time.Now()
+	result, err := r.next.Create(ctx, genre, userToken)
+	r.observe("Create", start, err)
+	return result, err
+}
+
+func (r *instrumentedGenreRepository) FindByID(ctx context.Context, id int64) (*genreEntities.Genre, error) {
+	start := time.Now()
+	result, err := r.next.FindByID(ctx, id)
+	r.observe("FindByID", start, err)
+	return result, err
+}
+
+func (r *instrumentedGenreRepository) FindAll(ctx context.Context) ([]*genreEntities.Genre, error) {
+	start := time.Now()
+	result, err := r.next.FindAll(ctx)
+	r.observe("FindAll", start, err)
+	return result, err
+}
+
+func (r *instrumentedGenreRepository) FindByName(ctx context.Context, name string, userToken string) (*genreEntities.Genre, error) {
+	start := time.Now()
+	result, err := r.next.FindByName(ctx, name, userToken)
+	r.observe("FindByName", start, err)
+	return result, err
+}
+
+// instrumentedProfileRepository は ProfileRepository の呼び出しを記録する
+type instrumentedProfileRepository struct {
+	next profileRepositories.ProfileRepository
+	repositoryObserver
+}
+
+// NewProfileRepository は ProfileRepository の呼び出しを記録するデコレーターを作成
+func NewProfileRepository(next profileRepositories.ProfileRepository, recorder appMetrics.Recorder) profileRepositories.ProfileRepository {
+	return &instrumentedProfileRepository{next: next, repositoryObserver: repositoryObserver{recorder: recorder, repository: "ProfileRepository"}}
+}
+
+func (r *instrumentedProfileRepository) GetByID(ctx context.Context, id string) (*profileEntities.Profile, error) {
+	start := time.Now()
+	result, err := r.next.GetByID(ctx, id)
+	r.observe("GetByID", start, err)
+	return result, err
+}
+
+func (r *instrumentedProfileRepository) Create(ctx context.Context, profile *profileEntities.Profile) (*profileEntities.Profile, error) {
+	start := time.Now()
+	result, err := r.next.Create(ctx, profile)
+	r.observe("Create", start, err)
+	return result, err
+}
+
+func (r *instrumentedProfileRepository) Update(ctx context.Context, profile *profileEntities.Profile) error {
+	start := time.Now()
+	err := r.next.Update(ctx, profile)
+	r.observe("Update", start, err)
+	return err
+}
+
+// instrumentedQuestionRepository は QuestionRepository の呼び出しを記録する
+type instrumentedQuestionRepository struct {
+	next questionRepositories.QuestionRepository
+	repositoryObserver
+}
+
+// NewQuestionRepository は QuestionRepository の呼び出しを記録するデコレーターを作成
+func NewQuestionRepository(next questionRepositories.QuestionRepository, recorder appMetrics.Recorder) questionRepositories.QuestionRepository {
+	return &instrumentedQuestionRepository{next: next, repositoryObserver: repositoryObserver{recorder: recorder, repository: "QuestionRepository"}}
+}
+
+func (r *instrumentedQuestionRepository) Create(ctx context.Context, question *questionEntities.Question, tags []string, userToken string) (*questionEntities.Question, error) {
+	start := time.Now()
+	result, err := r.next.Create(ctx, question, tags, userToken)
+	r.observe("Create", start, err)
+	return result, err
+}
+
+func (r *instrumentedQuestionRepository) GetByID(ctx context.Context, id int64) (*questionEntities.Question, error) {
+	start := time.Now()
+	result, err := r.next.GetByID(ctx, id)
+	r.observe("GetByID", start, err)
+	return result, err
+}
+
+func (r *instrumentedQuestionRepository) GetByUserID(ctx context.Context, userID string, userToken string) ([]*questionEntities.Question, error) {
+	start := time.Now()
+	result, err := r.next.GetByUserID(ctx, userID, userToken)
+	r.observe("GetByUserID", start, err)
+	return result, err
+}
+
+func (r *instrumentedQuestionRepository) Update(ctx context.Context, question *questionEntities.Question, tags []string, userToken string) error {
+	start := time.Now()
+	err := r.next.Update(ctx, question, tags, userToken)
+	r.observe("Update", start, err)
+	return err
+}
+
+func (r *instrumentedQuestionRepository) Delete(ctx context.Context, id int64, userToken string) error {
+	start := time.Now()
+	err := r.next.Delete(ctx, id, userToken)
+	r.observe("Delete", start, err)
+	return err
+}
+
+func (r *instrumentedQuestionRepository) GetAll(ctx context.Context) ([]*questionEntities.Question, error) {
+	start := time.Now()
+	result, err := r.next.GetAll(ctx)
+	r.observe("GetAll", start, err)
+	return result, err
+}
+
+func (r *instrumentedQuestionRepository) GetByIDs(ctx context.Context, ids []int64) ([]*questionEntities.Question, error) {
+	start := time.Now()
+	result, err := r.next.GetByIDs(ctx, ids)
+	r.observe("GetByIDs", start, err)
+	return result, err
+}
+
+// instrumentedTagRepository は TagRepository の呼び出しを記録する
+type instrumentedTagRepository struct {
+	next tagRepositories.TagRepository
+	repositoryObserver
+}
+
+// NewTagRepository は TagRepository の呼び出しを記録するデコレーターを作成
+func NewTagRepository(next tagRepositories.TagRepository, recorder appMetrics.Recorder) tagRepositories.TagRepository {
+	return &instrumentedTagRepository{next: next, repositoryObserver: repositoryObserver{recorder: recorder, repository: "TagRepository"}}
+}
+
+func (r *instrumentedTagRepository) Search(ctx context.Context, prefix string, limit int) ([]*tagEntities.Tag, error) {
+	start := time.Now()
+	result, err := r.next.Search(ctx, prefix, limit)
+	r.observe("Search", start, err)
+	return result, err
+}
+
+func (r *instrumentedTagRepository) FindByQuestionIDs(ctx context.Context, questionIDs []int64) (map[int64][]string, error) {
+	start := time.Now()
+	result, err := r.next.FindByQuestionIDs(ctx, questionIDs)
+	r.observe("FindByQuestionIDs", start, err)
+	return result, err
+}
+
+func (r *instrumentedTagRepository) FindQuestionIDsByName(ctx context.Context, name string) ([]int64, error) {
+	start := time.Now()
+	result, err := r.next.FindQuestionIDsByName(ctx, name)
+	r.observe("FindQuestionIDsByName", start, err)
+	return result, err
+}
@@ -7,33 +7,36 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 
 	"Shittaka_back/internal/domain/profile/entities"
 	"Shittaka_back/internal/domain/profile/repositories"
 	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/infrastructure/config"
+	"Shittaka_back/internal/infrastructure/httpclient"
 )
 
 // ProfileRepositoryImpl はSupabaseを使用したProfileRepositoryの実装
-type ProfileRepositoryImpl struct{}
+type ProfileRepositoryImpl struct {
+	supabase config.SupabaseConfig
+}
 
 // NewProfileRepository は新しいProfileRepositoryImplを作成
-func NewProfileRepository() repositories.ProfileRepository {
-	return &ProfileRepositoryImpl{}
+func NewProfileRepository(supabase config.SupabaseConfig) repositories.ProfileRepository {
+	return &ProfileRepositoryImpl{supabase: supabase}
 }
 
 // GetByID はIDでプロフィールを取得
 func (r *ProfileRepositoryImpl) GetByID(ctx context.Context, id string) (*entities.Profile, error) {
-	url := fmt.Sprintf("%s/rest/v1/profiles?id=eq.%s", os.Getenv("SUPABASE_URL"), id)
+	url := fmt.Sprintf("%s/rest/v1/profiles?id=eq.%s", r.supabase.URL, id)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
-	req.Header.Set("Authorization", "Bearer "+os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+r.supabase.AnonKey)
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -46,7 +49,7 @@ func (r *ProfileRepositoryImpl) GetByID(ctx context.Context, id string) (*entiti
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("find profile failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, shared.NewInfrastructureError("find profile", resp.StatusCode, string(body))
 	}
 
 	var profileList []map[string]interface{}
@@ -55,7 +58,7 @@ func (r *ProfileRepositoryImpl) GetByID(ctx context.Context, id string) (*entiti
 	}
 
 	if len(profileList) == 0 {
-		return nil, shared.NewDomainError("NOT_FOUND", "profile not found")
+		return nil, shared.NewDomainError("NOT_FOUND").With("resource", "profile")
 	}
 
 	return mapToProfile(profileList[0]), nil
@@ -73,18 +76,18 @@ func (r *ProfileRepositoryImpl) Create(ctx context.Context, profile *entities.Pr
 		return nil, fmt.Errorf("failed to marshal profile data: %w", err)
 	}
 
-	url := os.Getenv("SUPABASE_URL") + "/rest/v1/profiles"
+	url := r.supabase.URL + "/rest/v1/profiles"
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
-	req.Header.Set("Authorization", "Bearer "+os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+r.supabase.AnonKey)
 	req.Header.Set("Prefer", "return=representation")
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -97,7 +100,7 @@ func (r *ProfileRepositoryImpl) Create(ctx context.Context, profile *entities.Pr
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("create profile failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, shared.NewInfrastructureError("create profile", resp.StatusCode, string(body))
 	}
 
 	var profileList []map[string]interface{}
@@ -123,17 +126,17 @@ func (r *ProfileRepositoryImpl) Update(ctx context.Context, profile *entities.Pr
 		return fmt.Errorf("failed to marshal profile data: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/rest/v1/profiles?id=eq.%s", os.Getenv("SUPABASE_URL"), profile.ID)
+	url := fmt.Sprintf("%s/rest/v1/profiles?id=eq.%s", r.supabase.URL, profile.ID)
 	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
-	req.Header.Set("Authorization", "Bearer "+os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+r.supabase.AnonKey)
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
@@ -146,7 +149,7 @@ func (r *ProfileRepositoryImpl) Update(ctx context.Context, profile *entities.Pr
 	}
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("update profile failed with status %d: %s", resp.StatusCode, string(body))
+		return shared.NewInfrastructureError("update profile", resp.StatusCode, string(body))
 	}
 
 	return nil
@@ -7,50 +7,65 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"Shittaka_back/internal/domain/question/entities"
 	"Shittaka_back/internal/domain/question/repositories"
 	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/infrastructure/config"
+	"Shittaka_back/internal/infrastructure/httpclient"
 )
 
 // QuestionRepositoryImpl はSupabaseを使用したQuestionRepositoryの実装
-type QuestionRepositoryImpl struct{}
+type QuestionRepositoryImpl struct {
+	supabase config.SupabaseConfig
+}
 
 // NewQuestionRepository は新しいQuestionRepositoryImplを作成
-func NewQuestionRepository() repositories.QuestionRepository {
-	return &QuestionRepositoryImpl{}
+func NewQuestionRepository(supabase config.SupabaseConfig) repositories.QuestionRepository {
+	return &QuestionRepositoryImpl{supabase: supabase}
 }
 
-// Create は新しい問題を作成（RLS適用のためユーザートークンを使用）
-func (r *QuestionRepositoryImpl) Create(ctx context.Context, question *entities.Question, userToken string) (*entities.Question, error) {
+// Create は新しい問題とタグを create_question 関数で1トランザクションとして作成（RLS適用のためユーザートークンを使用）
+func (r *QuestionRepositoryImpl) Create(ctx context.Context, question *entities.Question, tags []string, userToken string) (*entities.Question, error) {
 	questionData := map[string]interface{}{
-		"genre_id":    question.GenreID,
-		"user_id":     question.UserID,
-		"title":       question.Title,
-		"body":        question.Body,
-		"explanation": question.Explanation,
-	}
-
-	jsonData, err := json.Marshal(questionData)
+		"genre_id":          question.GenreID,
+		"user_id":           question.UserID,
+		"title":             question.Title,
+		"body":              question.Body,
+		"explanation":       question.Explanation,
+		"type":              question.Type,
+		"partial_credit":    question.PartialCredit,
+		"accepted_answers":  nonNilStrings(question.AcceptedAnswers),
+		"correct_boolean":   question.CorrectBoolean,
+		"numeric_answer":    question.NumericAnswer,
+		"numeric_tolerance": question.NumericTolerance,
+		"shuffle_choices":   question.ShuffleChoices,
+	}
+
+	params := map[string]interface{}{
+		"p_question": questionData,
+		"p_tags":     nonNilStrings(tags),
+	}
+
+	jsonData, err := json.Marshal(params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal question data: %w", err)
 	}
 
-	url := os.Getenv("SUPABASE_URL") + "/rest/v1/questions"
+	url := r.supabase.URL + "/rest/v1/rpc/create_question"
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
 	req.Header.Set("Authorization", "Bearer "+userToken)
-	req.Header.Set("Prefer", "return=representation")
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -62,8 +77,8 @@ func (r *QuestionRepositoryImpl) Create(ctx context.Context, question *entities.
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("create question failed with status %d: %s", resp.StatusCode, string(body))
+	if resp.StatusCode != http.StatusOK {
+		return nil, shared.NewInfrastructureError("create question", resp.StatusCode, string(body))
 	}
 
 	var questionList []map[string]interface{}
@@ -81,16 +96,16 @@ func (r *QuestionRepositoryImpl) Create(ctx context.Context, question *entities.
 
 // GetByID はIDで問題を検索
 func (r *QuestionRepositoryImpl) GetByID(ctx context.Context, id int64) (*entities.Question, error) {
-	url := fmt.Sprintf("%s/rest/v1/questions?id=eq.%d", os.Getenv("SUPABASE_URL"), id)
+	url := fmt.Sprintf("%s/rest/v1/questions?id=eq.%d", r.supabase.URL, id)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
-	req.Header.Set("Authorization", "Bearer "+os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+r.supabase.AnonKey)
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -103,7 +118,7 @@ func (r *QuestionRepositoryImpl) GetByID(ctx context.Context, id int64) (*entiti
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("find question failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, shared.NewInfrastructureError("find question", resp.StatusCode, string(body))
 	}
 
 	var questionList []map[string]interface{}
@@ -112,7 +127,7 @@ func (r *QuestionRepositoryImpl) GetByID(ctx context.Context, id int64) (*entiti
 	}
 
 	if len(questionList) == 0 {
-		return nil, shared.NewDomainError("NOT_FOUND", "問題が見つかりません")
+		return nil, shared.NewDomainError("NOT_FOUND").With("resource", "question")
 	}
 
 	return mapToQuestion(questionList[0]), nil
@@ -120,16 +135,16 @@ func (r *QuestionRepositoryImpl) GetByID(ctx context.Context, id int64) (*entiti
 
 // GetByUserID はユーザーIDで問題一覧を取得
 func (r *QuestionRepositoryImpl) GetByUserID(ctx context.Context, userID string, userToken string) ([]*entities.Question, error) {
-	url := fmt.Sprintf("%s/rest/v1/questions?user_id=eq.%s", os.Getenv("SUPABASE_URL"), userID)
+	url := fmt.Sprintf("%s/rest/v1/questions?user_id=eq.%s", r.supabase.URL, userID)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
 	req.Header.Set("Authorization", "Bearer "+userToken)
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -142,7 +157,7 @@ func (r *QuestionRepositoryImpl) GetByUserID(ctx context.Context, userID string,
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("find questions by user failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, shared.NewInfrastructureError("find questions by user", resp.StatusCode, string(body))
 	}
 
 	var questionList []map[string]interface{}
@@ -158,30 +173,45 @@ func (r *QuestionRepositoryImpl) GetByUserID(ctx context.Context, userID string,
 	return questions, nil
 }
 
-// Update は問題を更新（RLS適用のためユーザートークンを使用）
-func (r *QuestionRepositoryImpl) Update(ctx context.Context, question *entities.Question, userToken string) error {
+// Update は問題とタグを update_question 関数で1トランザクションとして更新（RLS適用のためユーザートークンを使用）
+// 保存されている版が question.Version と一致する行だけを更新し、版を1つ進める
+func (r *QuestionRepositoryImpl) Update(ctx context.Context, question *entities.Question, tags []string, userToken string) error {
 	questionData := map[string]interface{}{
-		"title":       question.Title,
-		"body":        question.Body,
-		"explanation": question.Explanation,
-	}
-
-	jsonData, err := json.Marshal(questionData)
+		"title":             question.Title,
+		"body":              question.Body,
+		"explanation":       question.Explanation,
+		"partial_credit":    question.PartialCredit,
+		"accepted_answers":  nonNilStrings(question.AcceptedAnswers),
+		"correct_boolean":   question.CorrectBoolean,
+		"numeric_answer":    question.NumericAnswer,
+		"numeric_tolerance": question.NumericTolerance,
+		"shuffle_choices":   question.ShuffleChoices,
+	}
+
+	// tags が nil の場合は null を送り、タグを変更しない
+	params := map[string]interface{}{
+		"p_id":       question.ID,
+		"p_version":  question.Version,
+		"p_question": questionData,
+		"p_tags":     tags,
+	}
+
+	jsonData, err := json.Marshal(params)
 	if err != nil {
 		return fmt.Errorf("failed to marshal question data: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/rest/v1/questions?id=eq.%d", os.Getenv("SUPABASE_URL"), question.ID)
-	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewBuffer(jsonData))
+	url := r.supabase.URL + "/rest/v1/rpc/update_question"
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
 	req.Header.Set("Authorization", "Bearer "+userToken)
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
@@ -193,25 +223,38 @@ func (r *QuestionRepositoryImpl) Update(ctx context.Context, question *entities.
 		return fmt.Errorf("failed to read response: %w", err)
 	}
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("update question failed with status %d: %s", resp.StatusCode, string(body))
+	if resp.StatusCode != http.StatusOK {
+		return shared.NewInfrastructureError("update question", resp.StatusCode, string(body))
 	}
 
+	var questionList []map[string]interface{}
+	if err := json.Unmarshal(body, &questionList); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	// 更新された行がなければ、読み込んだ後に他の更新で版が進んでいる
+	if len(questionList) == 0 {
+		return shared.NewDomainError("PRECONDITION_FAILED")
+	}
+
+	updated := mapToQuestion(questionList[0])
+	question.Version = updated.Version
+	question.UpdatedAt = updated.UpdatedAt
 	return nil
 }
 
 // Delete は問題を削除（RLS適用のためユーザートークンを使用）
 func (r *QuestionRepositoryImpl) Delete(ctx context.Context, id int64, userToken string) error {
-	url := fmt.Sprintf("%s/rest/v1/questions?id=eq.%d", os.Getenv("SUPABASE_URL"), id)
+	url := fmt.Sprintf("%s/rest/v1/questions?id=eq.%d", r.supabase.URL, id)
 	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
 	req.Header.Set("Authorization", "Bearer "+userToken)
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
@@ -224,7 +267,7 @@ func (r *QuestionRepositoryImpl) Delete(ctx context.Context, id int64, userToken
 	}
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("delete question failed with status %d: %s", resp.StatusCode, string(body))
+		return shared.NewInfrastructureError("delete question", resp.StatusCode, string(body))
 	}
 
 	return nil
@@ -232,16 +275,16 @@ func (r *QuestionRepositoryImpl) Delete(ctx context.Context, id int64, userToken
 
 // GetAll は全ての問題を取得
 func (r *QuestionRepositoryImpl) GetAll(ctx context.Context) ([]*entities.Question, error) {
-	url := os.Getenv("SUPABASE_URL") + "/rest/v1/questions"
+	url := r.supabase.URL + "/rest/v1/questions"
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("apikey", os.Getenv("SUPABASE_ANON_KEY"))
-	req.Header.Set("Authorization", "Bearer "+os.Getenv("SUPABASE_ANON_KEY"))
+	req.Header.Set("apikey", r.supabase.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+r.supabase.AnonKey)
 
-	client := &http.Client{}
+	client := httpclient.New(r.supabase.RequestTimeout)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -254,7 +297,56 @@ func (r *QuestionRepositoryImpl) GetAll(ctx context.Context) ([]*entities.Questi
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("find all questions failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, shared.NewInfrastructureError("find all questions", resp.StatusCode, string(body))
+	}
+
+	var questionList []map[string]interface{}
+	if err := json.Unmarshal(body, &questionList); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	questions := make([]*entities.Question, len(questionList))
+	for i, questionData := range questionList {
+		questions[i] = mapToQuestion(questionData)
+	}
+
+	return questions, nil
+}
+
+// GetByIDs は複数のIDで問題一覧を取得
+func (r *QuestionRepositoryImpl) GetByIDs(ctx context.Context, ids []int64) ([]*entities.Question, error) {
+	if len(ids) == 0 {
+		return []*entities.Question{}, nil
+	}
+
+	idStrs := make([]string, len(ids))
+	for i, id := range ids {
+		idStrs[i] = strconv.FormatInt(id, 10)
+	}
+
+	url := fmt.Sprintf("%s/rest/v1/questions?id=in.(%s)", r.supabase.URL, strings.Join(idStrs, ","))
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("apikey", r.supabase.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+r.supabase.AnonKey)
+
+	client := httpclient.New(r.supabase.RequestTimeout)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, shared.NewInfrastructureError("find questions by ids", resp.StatusCode, string(body))
 	}
 
 	var questionList []map[string]interface{}
@@ -272,7 +364,7 @@ func (r *QuestionRepositoryImpl) GetAll(ctx context.Context) ([]*entities.Questi
 
 // mapToQuestion は map[string]interface{} を Question エンティティに変換
 func mapToQuestion(m map[string]interface{}) *entities.Question {
-	return &entities.Question{
+	question := &entities.Question{
 		ID:             getInt64(m, "id"),
 		GenreID:        getInt64(m, "genre_id"),
 		UserID:         getString(m, "user_id"),
@@ -283,11 +375,34 @@ func mapToQuestion(m map[string]interface{}) *entities.Question {
 		Views:          getInt(m, "views"),
 		CorrectCount:   getInt(m, "correct_count"),
 		IncorrectCount: getInt(m, "incorrect_count"),
+		Type:           entities.QuestionType(getString(m, "type")),
+
+		PartialCredit:    getBool(m, "partial_credit"),
+		AcceptedAnswers:  getStringSlice(m, "accepted_answers"),
+		CorrectBoolean:   getBoolPtr(m, "correct_boolean"),
+		NumericAnswer:    getFloat64Ptr(m, "numeric_answer"),
+		NumericTolerance: getFloat64(m, "numeric_tolerance"),
+		ShuffleChoices:   getBool(m, "shuffle_choices"),
+
+		Version:   getInt(m, "version"),
+		UpdatedAt: getTime(m, "updated_at"),
 	}
+	if question.Type == "" {
+		question.Type = entities.QuestionTypeSingleChoice
+	}
+	return question
 }
 
 // ヘルパー関数
 
+// nonNilStrings は nil スライスを空スライスに変換（not null の配列カラムに null を送らないため）
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
 // getString は map から文字列を安全に取得
 func getString(m map[string]interface{}, key string) string {
 	if val, ok := m[key]; ok {
@@ -336,6 +451,64 @@ func getInt(m map[string]interface{}, key string) int {
 	return 0
 }
 
+// getBool は map から bool を安全に取得
+func getBool(m map[string]interface{}, key string) bool {
+	if val, ok := m[key]; ok {
+		if b, ok := val.(bool); ok {
+			return b
+		}
+	}
+	return false
+}
+
+// getBoolPtr は map から *bool を安全に取得（null の場合は nil）
+func getBoolPtr(m map[string]interface{}, key string) *bool {
+	if val, ok := m[key]; ok {
+		if b, ok := val.(bool); ok {
+			return &b
+		}
+	}
+	return nil
+}
+
+// getFloat64 は map から float64 を安全に取得
+func getFloat64(m map[string]interface{}, key string) float64 {
+	if f := getFloat64Ptr(m, key); f != nil {
+		return *f
+	}
+	return 0
+}
+
+// getFloat64Ptr は map から *float64 を安全に取得（null の場合は nil）
+func getFloat64Ptr(m map[string]interface{}, key string) *float64 {
+	if val, ok := m[key]; ok {
+		switch v := val.(type) {
+		case float64:
+			return &v
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return &f
+			}
+		}
+	}
+	return nil
+}
+
+// getStringSlice は map から []string を安全に取得
+func getStringSlice(m map[string]interface{}, key string) []string {
+	result := []string{}
+	if val, ok := m[key]; ok {
+		if items, ok := val.([]interface{}); ok {
+			for _, item := range items {
+				if str, ok := item.(string); ok {
+					result = append(result, str)
+				}
+			}
+		}
+	}
+	return result
+}
+
 // getTime は map から time.Time を安全に取得
 func getTime(m map[string]interface{}, key string) time.Time {
 	if val, ok := m[key]; ok {
@@ -346,4 +519,4 @@ func getTime(m map[string]interface{}, key string) time.Time {
 		}
 	}
 	return time.Time{}
-}
\ No newline at end of file
+}
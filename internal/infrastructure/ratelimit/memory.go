@@ -0,0 +1,68 @@
+package ratelimit
+
+// memory.goはレート制限のバケットをプロセス内のメモリに保存するストアを定義
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"Shittaka_back/internal/application/ratelimit"
+)
+
+// sweepInterval は満杯に戻ったバケットを削除する間隔
+const sweepInterval = time.Minute
+
+// entry は保存しているバケットと、その方針
+type entry struct {
+	bucket ratelimit.Bucket
+	policy ratelimit.Policy
+}
+
+// MemoryStore はバケットをメモリに保存する ratelimit.Store
+// 制限はプロセスごとになるため、複数のマシンで動かす場合はマシンの数だけ緩くなる
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*entry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore は新しいMemoryStoreを作成
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*entry),
+		now:     time.Now,
+	}
+}
+
+// Take はキーのバケットからトークンを1つ取り出す
+func (s *MemoryStore) Take(ctx context.Context, key string, policy ratelimit.Policy) (ratelimit.Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	e, ok := s.buckets[key]
+	if !ok {
+		e = &entry{bucket: ratelimit.NewBucket(policy, now), policy: policy}
+		s.buckets[key] = e
+	}
+	return e.bucket.Take(policy, now), nil
+}
+
+// sweep は満杯に戻ったバケットを削除する（満杯のバケットは新しく作った場合と同じため）
+// アクセスしたIPアドレスの数だけメモリを使い続けないよう、一定間隔で行う
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, e := range s.buckets {
+		if e.bucket.Full(e.policy, now) {
+			delete(s.buckets, key)
+		}
+	}
+}
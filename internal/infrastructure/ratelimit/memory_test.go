@@ -0,0 +1,39 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"Shittaka_back/internal/application/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	policy := ratelimit.Policy{Name: "write", RequestsPerMinute: 60, Burst: 1}
+	store := NewMemoryStore()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	t.Run("キーごとにバケットを分ける", func(t *testing.T) {
+		decision, err := store.Take(ctx, "write:ip:192.0.2.1", policy)
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+
+		decision, _ = store.Take(ctx, "write:ip:192.0.2.1", policy)
+		assert.False(t, decision.Allowed)
+
+		decision, _ = store.Take(ctx, "write:ip:192.0.2.2", policy)
+		assert.True(t, decision.Allowed)
+	})
+
+	t.Run("満杯に戻ったバケットは削除する", func(t *testing.T) {
+		now = now.Add(2 * time.Minute)
+		_, _ = store.Take(ctx, "write:ip:192.0.2.3", policy)
+
+		assert.Len(t, store.buckets, 1)
+	})
+}
@@ -0,0 +1,197 @@
+package supabase
+
+// tag_repository_impl.goはSupabaseを使用したTagRepositoryの実装
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"Shittaka_back/internal/domain/tag/entities"
+	"Shittaka_back/internal/domain/tag/repositories"
+	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/infrastructure/config"
+	"Shittaka_back/internal/infrastructure/httpclient"
+)
+
+// TagRepositoryImpl はSupabaseを使用したTagRepositoryの実装
+type TagRepositoryImpl struct {
+	supabase config.SupabaseConfig
+}
+
+// NewTagRepository は新しいTagRepositoryImplを作成
+func NewTagRepository(supabase config.SupabaseConfig) repositories.TagRepository {
+	return &TagRepositoryImpl{supabase: supabase}
+}
+
+// Search は名前の前方一致でタグを検索（tag_usageビューから使用数付きで取得）
+func (r *TagRepositoryImpl) Search(ctx context.Context, prefix string, limit int) ([]*entities.Tag, error) {
+	query := url.Values{}
+	query.Set("select", "id,name,usage_count")
+	query.Set("order", "usage_count.desc,name.asc")
+	query.Set("limit", strconv.Itoa(limit))
+	if prefix != "" {
+		// PostgRESTのlikeでは * がワイルドカード
+		escaped := strings.NewReplacer("*", "", "%", "", "_", "").Replace(prefix)
+		query.Set("name", "like."+escaped+"*")
+	}
+
+	apiURL := fmt.Sprintf("%s/rest/v1/tag_usage?%s", r.supabase.URL, query.Encode())
+	body, err := r.get(ctx, apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("search tags failed: %w", err)
+	}
+
+	var tagList []map[string]interface{}
+	if err := json.Unmarshal(body, &tagList); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	tags := make([]*entities.Tag, len(tagList))
+	for i, tagData := range tagList {
+		tags[i] = &entities.Tag{
+			ID:         getInt64(tagData, "id"),
+			Name:       getString(tagData, "name"),
+			UsageCount: int(getInt64(tagData, "usage_count")),
+		}
+	}
+
+	return tags, nil
+}
+
+// FindByQuestionIDs は問題IDごとのタグ名一覧を取得
+func (r *TagRepositoryImpl) FindByQuestionIDs(ctx context.Context, questionIDs []int64) (map[int64][]string, error) {
+	result := make(map[int64][]string, len(questionIDs))
+	if len(questionIDs) == 0 {
+		return result, nil
+	}
+
+	apiURL := fmt.Sprintf("%s/rest/v1/question_tags?select=question_id,tags(name)&question_id=in.(%s)&order=tag_id.asc",
+		r.supabase.URL, joinIDs(questionIDs))
+	body, err := r.get(ctx, apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("find tags by questions failed: %w", err)
+	}
+
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	for _, row := range rows {
+		questionID := getInt64(row, "question_id")
+		name := getString(getMap(row, "tags"), "name")
+		if name == "" {
+			continue
+		}
+		result[questionID] = append(result[questionID], name)
+	}
+
+	return result, nil
+}
+
+// FindQuestionIDsByName は指定したタグが付いた問題のIDを取得
+func (r *TagRepositoryImpl) FindQuestionIDsByName(ctx context.Context, name string) ([]int64, error) {
+	apiURL := fmt.Sprintf("%s/rest/v1/question_tags?select=question_id,tags!inner(name)&tags.name=eq.%s",
+		r.supabase.URL, url.QueryEscape(name))
+	body, err := r.get(ctx, apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("find questions by tag failed: %w", err)
+	}
+
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	ids := make([]int64, len(rows))
+	for i, row := range rows {
+		ids[i] = getInt64(row, "question_id")
+	}
+
+	return ids, nil
+}
+
+// get は匿名キーでGETリクエストを送信し、レスポンスボディを返す
+func (r *TagRepositoryImpl) get(ctx context.Context, apiURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("apikey", r.supabase.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+r.supabase.AnonKey)
+
+	client := httpclient.New(r.supabase.RequestTimeout)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, shared.NewInfrastructureError("get", resp.StatusCode, string(body))
+	}
+
+	return body, nil
+}
+
+// ヘルパー関数
+
+// joinIDs はIDの一覧をPostgRESTのin句用にカンマ区切りにする
+func joinIDs(ids []int64) string {
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(strs, ",")
+}
+
+// getString は map から文字列を安全に取得
+func getString(m map[string]interface{}, key string) string {
+	if val, ok := m[key]; ok {
+		if str, ok := val.(string); ok {
+			return str
+		}
+	}
+	return ""
+}
+
+// getInt64 は map から int64 を安全に取得
+func getInt64(m map[string]interface{}, key string) int64 {
+	if val, ok := m[key]; ok {
+		switch v := val.(type) {
+		case float64:
+			return int64(v)
+		case int64:
+			return v
+		case int:
+			return int64(v)
+		case string:
+			if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return i
+			}
+		}
+	}
+	return 0
+}
+
+// getMap は map から map を安全に取得
+func getMap(m map[string]interface{}, key string) map[string]interface{} {
+	if val, ok := m[key]; ok {
+		if mapVal, ok := val.(map[string]interface{}); ok {
+			return mapVal
+		}
+	}
+	return make(map[string]interface{})
+}
@@ -0,0 +1,393 @@
+package tracing
+
+// repositories.goはリポジトリの呼び出しごとにスパンを作るデコレーターを定義
+// Supabaseへのリクエスト自体のスパンと traceparent の付与は httpclient のトランスポートが行う
+
+import (
+	"context"
+	"errors"
+
+	answerEntities "Shittaka_back/internal/domain/answer/entities"
+	answerRepositories "Shittaka_back/internal/domain/answer/repositories"
+	authEntities "Shittaka_back/internal/domain/auth/entities"
+	authRepositories "Shittaka_back/internal/domain/auth/repositories"
+	choiceEntities "Shittaka_back/internal/domain/choices/entities"
+	choiceRepositories "Shittaka_back/internal/domain/choices/repositories"
+	genreEntities "Shittaka_back/internal/domain/genre/entities"
+	genreRepositories "Shittaka_back/internal/domain/genre/repositories"
+	profileEntities "Shittaka_back/internal/domain/profile/entities"
+	profileRepositories "Shittaka_back/internal/domain/profile/repositories"
+	questionEntities "Shittaka_back/internal/domain/question/entities"
+	questionRepositories "Shittaka_back/internal/domain/question/repositories"
+	"Shittaka_back/internal/domain/shared"
+	tagEntities "Shittaka_back/internal/domain/tag/entities"
+	tagRepositories "Shittaka_back/internal/domain/tag/repositories"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer はこのパッケージが作るスパンのトレーサー
+var tracer = otel.Tracer("Shittaka_back/internal/infrastructure/tracing")
+
+// repositorySpan はリポジトリの呼び出しのスパンを作る
+type repositorySpan struct {
+	repository string
+}
+
+// start は "QuestionRepository.GetByID" の形の名前でスパンを開始する
+func (s repositorySpan) start(ctx context.Context, method string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, s.repository+"."+method, trace.WithAttributes(
+		attribute.String("repository", s.repository),
+		attribute.String("repository.method", method),
+	))
+}
+
+// end はスパンを終了する
+// NOT_FOUND などのドメインエラーはSupabaseへの呼び出し自体は成功しているため失敗として記録しない
+func end(span trace.Span, err error) {
+	var domainErr shared.DomainError
+	if err != nil && !errors.As(err, &domainErr) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracedAnswerRepository は AnswerRepository の呼び出しごとにスパンを作る
+type tracedAnswerRepository struct {
+	next answerRepositories.AnswerRepository
+	repositorySpan
+}
+
+// NewAnswerRepository は AnswerRepository の呼び出しごとにスパンを作るデコレーターを作成
+func NewAnswerRepository(next answerRepositories.AnswerRepository) answerRepositories.AnswerRepository {
+	return &tracedAnswerRepository{next: next, repositorySpan: repositorySpan{repository: "AnswerRepository"}}
+}
+
+func (r *tracedAnswerRepository) Create(ctx context.Context, answer *answerEntities.Answer, userToken string) (*answerEntities.Answer, error) {
+	ctx, span := r.start(ctx, "Create")
+	result, err := r.next.Create(ctx, answer, userToken)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedAnswerRepository) GetByUserID(ctx context.Context, userID string) ([]*answerEntities.Answer, error) {
+	ctx, span := r.start(ctx, "GetByUserID")
+	result, err := r.next.GetByUserID(ctx, userID)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedAnswerRepository) GetByQuestionID(ctx context.Context, questionID int64) ([]*answerEntities.Answer, error) {
+	ctx, span := r.start(ctx, "GetByQuestionID")
+	result, err := r.next.GetByQuestionID(ctx, questionID)
+	end(span, err)
+	return result, err
+}
+
+// tracedUserRepository は UserRepository の呼び出しごとにスパンを作る
+type tracedUserRepository struct {
+	next authRepositories.UserRepository
+	repositorySpan
+}
+
+// NewUserRepository は UserRepository の呼び出しごとにスパンを作るデコレーターを作成
+func NewUserRepository(next authRepositories.UserRepository) authRepositories.UserRepository {
+	return &tracedUserRepository{next: next, repositorySpan: repositorySpan{repository: "UserRepository"}}
+}
+
+func (r *tracedUserRepository) Create(ctx context.Context, email, password string, metadata map[string]interface{}) (*authEntities.User, error) {
+	ctx, span := r.start(ctx, "Create")
+	result, err := r.next.Create(ctx, email, password, metadata)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedUserRepository) Authenticate(ctx context.Context, email, password string) (*authRepositories.AuthResult, error) {
+	ctx, span := r.start(ctx, "Authenticate")
+	result, err := r.next.Authenticate(ctx, email, password)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedUserRepository) FindByID(ctx context.Context, id string) (*authEntities.User, error) {
+	ctx, span := r.start(ctx, "FindByID")
+	result, err := r.next.FindByID(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedUserRepository) FindByEmail(ctx context.Context, email string) (*authEntities.User, error) {
+	ctx, span := r.start(ctx, "FindByEmail")
+	result, err := r.next.FindByEmail(ctx, email)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedUserRepository) Update(ctx context.Context, user *authEntities.User) error {
+	ctx, span := r.start(ctx, "Update")
+	err := r.next.Update(ctx, user)
+	end(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) Delete(ctx context.Context, id string) error {
+	ctx, span := r.start(ctx, "Delete")
+	err := r.next.Delete(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) Logout(ctx context.Context, token string) error {
+	ctx, span := r.start(ctx, "Logout")
+	err := r.next.Logout(ctx, token)
+	end(span, err)
+	return err
+}
+
+func (r *tracedUserRepository) GetCurrentUser(ctx context.Context, token string) (*authEntities.User, error) {
+	ctx, span := r.start(ctx, "GetCurrentUser")
+	result, err := r.next.GetCurrentUser(ctx, token)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedUserRepository) Refresh(ctx context.Context, refreshToken string) (*authRepositories.AuthResult, error) {
+	ctx, span := r.start(ctx, "Refresh")
+	result, err := r.next.Refresh(ctx, refreshToken)
+	end(span, err)
+	return result, err
+}
+
+// tracedChoiceRepository は ChoiceRepository の呼び出しごとにスパンを作る
+type tracedChoiceRepository struct {
+	next choiceRepositories.ChoiceRepository
+	repositorySpan
+}
+
+// NewChoiceRepository は ChoiceRepository の呼び出しごとにスパンを作るデコレーターを作成
+func NewChoiceRepository(next choiceRepositories.ChoiceRepository) choiceRepositories.ChoiceRepository {
+	return &tracedChoiceRepository{next: next, repositorySpan: repositorySpan{repository: "ChoiceRepository"}}
+}
+
+func (r *tracedChoiceRepository) GetByQuestionID(ctx context.Context, questionID int64) ([]choiceEntities.Choice, error) {
+	ctx, span := r.start(ctx, "GetByQuestionID")
+	result, err := r.next.GetByQuestionID(ctx, questionID)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedChoiceRepository) GetByID(ctx context.Context, id int64) (*choiceEntities.Choice, error) {
+	ctx, span := r.start(ctx, "GetByID")
+	result, err := r.next.GetByID(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedChoiceRepository) Create(ctx context.Context, choice choiceEntities.Choice) (*choiceEntities.Choice, error) {
+	ctx, span := r.start(ctx, "Create")
+	result, err := r.next.Create(ctx, choice)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedChoiceRepository) CreateWithAuth(ctx context.Context, choice choiceEntities.Choice, userToken string) (*choiceEntities.Choice, error) {
+	ctx, span := r.start(ctx, "CreateWithAuth")
+	result, err := r.next.CreateWithAuth(ctx, choice, userToken)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedChoiceRepository) Update(ctx context.Context, choice choiceEntities.Choice) (*choiceEntities.Choice, error) {
+	ctx, span := r.start(ctx, "Update")
+	result, err := r.next.Update(ctx, choice)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedChoiceRepository) Delete(ctx context.Context, questionID, id int64) error {
+	ctx, span := r.start(ctx, "Delete")
+	err := r.next.Delete(ctx, questionID, id)
+	end(span, err)
+	return err
+}
+
+func (r *tracedChoiceRepository) Reorder(ctx context.Context, questionID int64, choiceIDs []int64, userToken string) ([]choiceEntities.Choice, error) {
+	ctx, span := r.start(ctx, "Reorder")
+	result, err := r.next.Reorder(ctx, questionID, choiceIDs, userToken)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedChoiceRepository) ReplaceAll(ctx context.Context, questionID int64, diff choiceEntities.ChoiceSetDiff, userToken string) ([]choiceEntities.Choice, error) {
+	ctx, span := r.start(ctx, "ReplaceAll")
+	result, err := r.next.ReplaceAll(ctx, questionID, diff, userToken)
+	end(span, err)
+	return result, err
+}
+
+// tracedGenreRepository は GenreRepository の呼び出しごとにスパンを作る
+type tracedGenreRepository struct {
+	next genreRepositories.GenreRepository
+	repositorySpan
+}
+
+// NewGenreRepository は GenreRepository の呼び出しごとにスパンを作るデコレーターを作成
+func NewGenreRepository(next genreRepositories.GenreRepository) genreRepositories.GenreRepository {
+	return &tracedGenreRepository{next: next, repositorySpan: repositorySpan{repository: "GenreRepository"}}
+}
+
+func (r *tracedGenreRepository) Create(ctx context.Context, genre *genreEntities.Genre, userToken string) (*genreEntities.Genre, error) {
+	ctx, span := r.start(ctx, "Create")
+	result, err := r.next.Create(ctx, genre, userToken)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedGenreRepository) FindByID(ctx context.Context, id int64) (*genreEntities.Genre, error) {
+	ctx, span := r.start(ctx, "FindByID")
+	result, err := r.next.FindByID(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedGenreRepository) FindAll(ctx context.Context) ([]*genreEntities.Genre, error) {
+	ctx, span := r.start(ctx, "FindAll")
+	result, err := r.next.FindAll(ctx)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedGenreRepository) FindByName(ctx context.Context, name string, userToken string) (*genreEntities.Genre, error) {
+	ctx, span := r.start(ctx, "FindByName")
+	result, err := r.next.FindByName(ctx, name, userToken)
+	end(span, err)
+	return result, err
+}
+
+// tracedProfileRepository は ProfileRepository の呼び出しごとにスパンを作る
+type tracedProfileRepository struct {
+	next profileRepositories.ProfileRepository
+	repositorySpan
+}
+
+// NewProfileRepository は ProfileRepository の呼び出しごとにスパンを作るデコレーターを作成
+func NewProfileRepository(next profileRepositories.ProfileRepository) profileRepositories.ProfileRepository {
+	return &tracedProfileRepository{next: next, repositorySpan: repositorySpan{repository: "ProfileRepository"}}
+}
+
+func (r *tracedProfileRepository) GetByID(ctx context.Context, id string) (*profileEntities.Profile, error) {
+	ctx, span := r.start(ctx, "GetByID")
+	result, err := r.next.GetByID(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedProfileRepository) Create(ctx context.Context, profile *profileEntities.Profile) (*profileEntities.Profile, error) {
+	ctx, span := r.start(ctx, "Create")
+	result, err := r.next.Create(ctx, profile)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedProfileRepository) Update(ctx context.Context, profile *profileEntities.Profile) error {
+	ctx, span := r.start(ctx, "Update")
+	err := r.next.Update(ctx, profile)
+	end(span, err)
+	return err
+}
+
+// tracedQuestionRepository は QuestionRepository の呼び出しごとにスパンを作る
+type tracedQuestionRepository struct {
+	next questionRepositories.QuestionRepository
+	repositorySpan
+}
+
+// NewQuestionRepository は QuestionRepository の呼び出しごとにスパンを作るデコレーターを作成
+func NewQuestionRepository(next questionRepositories.QuestionRepository) questionRepositories.QuestionRepository {
+	return &tracedQuestionRepository{next: next, repositorySpan: repositorySpan{repository: "QuestionRepository"}}
+}
+
+func (r *tracedQuestionRepository) Create(ctx context.Context, question *questionEntities.Question, tags []string, userToken string) (*questionEntities.Question, error) {
+	ctx, span := r.start(ctx, "Create")
+	result, err := r.next.Create(ctx, question, tags, userToken)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedQuestionRepository) GetByID(ctx context.Context, id int64) (*questionEntities.Question, error) {
+	ctx, span := r.start(ctx, "GetByID")
+	result, err := r.next.GetByID(ctx, id)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedQuestionRepository) GetByUserID(ctx context.Context, userID string, userToken string) ([]*questionEntities.Question, error) {
+	ctx, span := r.start(ctx, "GetByUserID")
+	result, err := r.next.GetByUserID(ctx, userID, userToken)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedQuestionRepository) Update(ctx context.Context, question *questionEntities.Question, tags []string, userToken string) error {
+	ctx, span := r.start(ctx, "Update")
+	err := r.next.Update(ctx, question, tags, userToken)
+	end(span, err)
+	return err
+}
+
+func (r *tracedQuestionRepository) Delete(ctx context.Context, id int64, userToken string) error {
+	ctx, span := r.start(ctx, "Delete")
+	err := r.next.Delete(ctx, id, userToken)
+	end(span, err)
+	return err
+}
+
+func (r *tracedQuestionRepository) GetAll(ctx context.Context) ([]*questionEntities.Question, error) {
+	ctx, span := r.start(ctx, "GetAll")
+	result, err := r.next.GetAll(ctx)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedQuestionRepository) GetByIDs(ctx context.Context, ids []int64) ([]*questionEntities.Question, error) {
+	ctx, span := r.start(ctx, "GetByIDs")
+	result, err := r.next.GetByIDs(ctx, ids)
+	end(span, err)
+	return result, err
+}
+
+// tracedTagRepository は TagRepository の呼び出しごとにスパンを作る
+type tracedTagRepository struct {
+	next tagRepositories.TagRepository
+	repositorySpan
+}
+
+// NewTagRepository は TagRepository の呼び出しごとにスパンを作るデコレーターを作成
+func NewTagRepository(next tagRepositories.TagRepository) tagRepositories.TagRepository {
+	return &tracedTagRepository{next: next, repositorySpan: repositorySpan{repository: "TagRepository"}}
+}
+
+func (r *tracedTagRepository) Search(ctx context.Context, prefix string, limit int) ([]*tagEntities.Tag, error) {
+	ctx, span := r.start(ctx, "Search")
+	result, err := r.next.Search(ctx, prefix, limit)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedTagRepository) FindByQuestionIDs(ctx context.Context, questionIDs []int64) (map[int64][]string, error) {
+	ctx, span := r.start(ctx, "FindByQuestionIDs")
+	result, err := r.next.FindByQuestionIDs(ctx, questionIDs)
+	end(span, err)
+	return result, err
+}
+
+func (r *tracedTagRepository) FindQuestionIDsByName(ctx context.Context, name string) ([]int64, error) {
+	ctx, span := r.start(ctx, "FindQuestionIDsByName")
+	result, err := r.next.FindQuestionIDsByName(ctx, name)
+	end(span, err)
+	return result, err
+}
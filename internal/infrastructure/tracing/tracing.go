@@ -0,0 +1,72 @@
+package tracing
+
+// tracing.goはOpenTelemetryのトレース出力を設定する
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// トレースの出力先（OTEL_TRACES_EXPORTER の値）
+const (
+	ExporterNone    = "none"    // 出力しない（既定）
+	ExporterOTLP    = "otlp"    // OTLP/HTTP で送信する（送信先は OTEL_EXPORTER_OTLP_ENDPOINT など標準の環境変数で指定）
+	ExporterConsole = "console" // 標準出力にJSONで出力する
+	ExporterStdout  = "stdout"  // console の別名
+)
+
+// Setup は出力先に応じてトレースを設定し、終了時に呼ぶ関数を返す
+// 出力先が none の場合は何も設定しない（スパンは作られるが記録も送信もされない）
+// serviceName はトレースに付与するサービス名
+func Setup(ctx context.Context, exporterName, serviceName string) (shutdown func(context.Context) error, err error) {
+	noop := func(context.Context) error { return nil }
+
+	var exporter sdktrace.SpanExporter
+	switch strings.ToLower(strings.TrimSpace(exporterName)) {
+	case "", ExporterNone:
+		return noop, nil
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	case ExporterConsole, ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return noop, fmt.Errorf("unknown trace exporter %q (expected none, otlp or console)", exporterName)
+	}
+	if err != nil {
+		return noop, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return noop, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
+
+// TraceIDFromContext はコンテキストのスパンのトレースIDを返す（スパンが記録されていなければ空文字）
+func TraceIDFromContext(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
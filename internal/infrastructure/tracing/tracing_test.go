@@ -0,0 +1,121 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	genreEntities "Shittaka_back/internal/domain/genre/entities"
+	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/infrastructure/httpclient"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetup(t *testing.T) {
+	t.Run("既定では無効", func(t *testing.T) {
+		shutdown, err := Setup(context.Background(), "", "shittaka-back")
+		require.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("未知の出力先はエラー", func(t *testing.T) {
+		_, err := Setup(context.Background(), "jaeger", "shittaka-back")
+		assert.Error(t, err)
+	})
+}
+
+// fakeGenreRepository は Supabase の代わりにテスト用サーバーへリクエストを送る GenreRepository
+type fakeGenreRepository struct {
+	url string
+	err error
+}
+
+func (r *fakeGenreRepository) Create(ctx context.Context, genre *genreEntities.Genre, userToken string) (*genreEntities.Genre, error) {
+	return genre, r.err
+}
+
+func (r *fakeGenreRepository) FindByID(ctx context.Context, id int64) (*genreEntities.Genre, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpclient.New(0).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return nil, r.err
+}
+
+func (r *fakeGenreRepository) FindAll(ctx context.Context) ([]*genreEntities.Genre, error) {
+	return nil, r.err
+}
+
+func (r *fakeGenreRepository) FindByName(ctx context.Context, name string, userToken string) (*genreEntities.Genre, error) {
+	return nil, r.err
+}
+
+func TestRepositorySpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defaultProvider, defaultPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(defaultProvider)
+		otel.SetTextMapPropagator(defaultPropagator)
+	})
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	t.Run("Supabaseへのリクエストに traceparent を付与する", func(t *testing.T) {
+		exporter.Reset()
+		repo := NewGenreRepository(&fakeGenreRepository{url: server.URL})
+		_, err := repo.FindByID(context.Background(), 1)
+		require.NoError(t, err)
+
+		// HTTPクライアントのスパンがリポジトリのスパンの子になる
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 2)
+		client, repository := spans[0], spans[1]
+		assert.Equal(t, "GenreRepository.FindByID", repository.Name)
+		assert.Equal(t, repository.SpanContext.SpanID(), client.Parent.SpanID())
+
+		assert.Contains(t, traceparent, repository.SpanContext.TraceID().String())
+		assert.Contains(t, traceparent, client.SpanContext.SpanID().String())
+	})
+
+	t.Run("失敗はスパンにエラーとして記録する", func(t *testing.T) {
+		exporter.Reset()
+		repo := NewGenreRepository(&fakeGenreRepository{err: errors.New("connection refused")})
+		_, _ = repo.FindAll(context.Background())
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status.Code)
+	})
+
+	t.Run("NOT_FOUND は失敗として記録しない", func(t *testing.T) {
+		exporter.Reset()
+		repo := NewGenreRepository(&fakeGenreRepository{err: shared.NewDomainError("NOT_FOUND")})
+		_, _ = repo.FindAll(context.Background())
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, codes.Unset, spans[0].Status.Code)
+	})
+}
@@ -3,16 +3,28 @@ package dto
 import "time"
 
 // CreateAnswerRequest は回答作成リクエストDTO
+// single_choice は choice_id、multi_select は choice_ids、true_false は boolean_answer、
+// free_text は text_answer、numeric は numeric_answer を指定する
 type CreateAnswerRequest struct {
-	QuestionID int64 `json:"question_id"`
-	ChoiceID   int64 `json:"choice_id"`
+	QuestionID    int64    `json:"question_id"`
+	ChoiceID      int64    `json:"choice_id,omitempty"`
+	ChoiceIDs     []int64  `json:"choice_ids,omitempty"`
+	BooleanAnswer *bool    `json:"boolean_answer,omitempty"`
+	TextAnswer    string   `json:"text_answer,omitempty"`
+	NumericAnswer *float64 `json:"numeric_answer,omitempty"`
 }
 
 // AnswerResponse は回答レスポンスDTO
 type AnswerResponse struct {
-	ID         int64     `json:"id"`
-	UserID     string    `json:"user_id"`
-	QuestionID int64     `json:"question_id"`
-	ChoiceID   int64     `json:"choice_id"`
-	AnsweredAt time.Time `json:"answered_at"`
-}
\ No newline at end of file
+	ID            int64     `json:"id"`
+	UserID        string    `json:"user_id"`
+	QuestionID    int64     `json:"question_id"`
+	ChoiceID      int64     `json:"choice_id,omitempty"`
+	ChoiceIDs     []int64   `json:"choice_ids,omitempty"`
+	BooleanAnswer *bool     `json:"boolean_answer,omitempty"`
+	TextAnswer    string    `json:"text_answer,omitempty"`
+	NumericAnswer *float64  `json:"numeric_answer,omitempty"`
+	IsCorrect     bool      `json:"is_correct"`
+	Score         float64   `json:"score"`
+	AnsweredAt    time.Time `json:"answered_at"`
+}
@@ -11,6 +11,11 @@ type AuthRequest struct {
 	Username string `json:"username,omitempty"`
 }
 
+// RefreshRequest はトークン更新リクエストのHTTP DTO
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
 // AuthResponse は認証レスポンスのHTTP DTO
 type AuthResponse struct {
 	Token        string  `json:"token"`
@@ -25,9 +30,3 @@ type UserDTO struct {
 	Email    string `json:"email"`
 	Username string `json:"username"`
 }
-
-// ErrorResponse はエラーレスポンスのHTTP DTO
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-}
@@ -7,6 +7,7 @@ type CreateChoiceRequest struct {
 	QuestionID int64  `json:"question_id"`
 	Text       string `json:"text"`
 	IsCorrect  bool   `json:"is_correct"`
+	Position   int    `json:"position,omitempty"`
 }
 
 // UpdateChoiceRequest は選択肢更新リクエストのHTTP DTO
@@ -15,6 +16,7 @@ type UpdateChoiceRequest struct {
 	QuestionID int64  `json:"question_id"`
 	Text       string `json:"text"`
 	IsCorrect  bool   `json:"is_correct"`
+	Position   int    `json:"position,omitempty"`
 }
 
 // ChoiceResponse は選択肢レスポンスのHTTP DTO
@@ -23,6 +25,26 @@ type ChoiceResponse struct {
 	QuestionID int64  `json:"question_id"`
 	Text       string `json:"text"`
 	IsCorrect  bool   `json:"is_correct"`
+	Position   int    `json:"position"`
+}
+
+// ReorderChoicesRequest は選択肢並べ替えリクエストのHTTP DTO
+// choice_ids には問題の全ての選択肢IDを表示したい順に指定する
+type ReorderChoicesRequest struct {
+	ChoiceIDs []int64 `json:"choice_ids"`
+}
+
+// ReplaceChoicesRequest は選択肢一式の置き換えリクエストのHTTP DTO
+// choices の並び順が表示順になる。id を省略した要素は新規追加、含まれない既存の選択肢は削除される
+type ReplaceChoicesRequest struct {
+	Choices []ChoiceInput `json:"choices"`
+}
+
+// ChoiceInput は選択肢一式の置き換えで指定する1つの選択肢
+type ChoiceInput struct {
+	ID        int64  `json:"id,omitempty"`
+	Text      string `json:"text"`
+	IsCorrect bool   `json:"is_correct"`
 }
 
 // ChoicesResponse は複数選択肢のレスポンスのHTTP DTO
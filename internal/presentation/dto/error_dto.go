@@ -0,0 +1,28 @@
+package dto
+
+// error_dto.goはエラーレスポンスのHTTP DTOを定義
+
+// ErrorResponse はエラーレスポンスのHTTP DTO
+// 全てのエラーは {"error": {"code", "message", "field", "errors", "request_id"}} の形で返す
+type ErrorResponse struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail はエラーの詳細
+type ErrorDetail struct {
+	Code           string       `json:"code"`                      // 機械判定用のエラーコード（NOT_FOUND, VALIDATION_ERROR など）
+	Message        string       `json:"message"`                   // 表示用のメッセージ
+	Field          string       `json:"field,omitempty"`           // バリデーションエラーの対象項目（複数ある場合は最初の項目）
+	Errors         []FieldError `json:"errors,omitempty"`          // バリデーションエラーの全項目
+	RetryAfter     int          `json:"retry_after,omitempty"`     // 再試行できるまでの秒数（RATE_LIMITED / ACCOUNT_LOCKED）
+	UnlockAt       string       `json:"unlock_at,omitempty"`       // ロックが解除される日時（ACCOUNT_LOCKED、RFC 3339）
+	CurrentVersion int          `json:"current_version,omitempty"` // 保存されている現在の版（PRECONDITION_FAILED）
+	RequestID      string       `json:"request_id,omitempty"`      // 問い合わせ用のリクエストID
+}
+
+// FieldError は項目ごとのバリデーションエラー
+type FieldError struct {
+	Field   string `json:"field"`   // 対象項目
+	Code    string `json:"code"`    // バリデーションエラーのコード（required, max_length など）
+	Message string `json:"message"` // 表示用のメッセージ
+}
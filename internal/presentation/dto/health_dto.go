@@ -0,0 +1,31 @@
+package dto
+
+// health_dto.goはヘルスチェック関連のHTTP DTOを定義
+
+// HealthResponse は生存確認のレスポンス
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+// ReadinessResponse はリクエストを受け付けられるかどうかの確認結果
+type ReadinessResponse struct {
+	Status    string             `json:"status"`     // ok / degraded
+	Checks    []DependencyStatus `json:"checks"`     // 依存サービスごとの結果
+	CheckedAt string             `json:"checked_at"` // 確認した日時（結果は短時間キャッシュされる）
+}
+
+// DependencyStatus は依存サービスごとの確認結果
+type DependencyStatus struct {
+	Name      string  `json:"name"`       // postgrest / gotrue
+	Status    string  `json:"status"`     // ok / down
+	LatencyMs float64 `json:"latency_ms"` // 応答までの時間（ミリ秒）
+}
+
+// VersionResponse はビルド情報
+type VersionResponse struct {
+	Version    string `json:"version"`
+	Commit     string `json:"commit,omitempty"`
+	CommitTime string `json:"commit_time,omitempty"`
+	Modified   bool   `json:"modified"`
+	GoVersion  string `json:"go_version"`
+}
@@ -4,17 +4,38 @@ import "time"
 
 // CreateQuestionRequest は問題作成リクエストのHTTP DTO
 type CreateQuestionRequest struct {
-	GenreID     int64  `json:"genre_id"`
-	Title       string `json:"title"`
-	Body        string `json:"body"`
-	Explanation string `json:"explanation"`
+	GenreID     int64    `json:"genre_id"`
+	Title       string   `json:"title"`
+	Body        string   `json:"body"`
+	Explanation string   `json:"explanation"`
+	Tags        []string `json:"tags,omitempty"`
+
+	// 問題形式: single_choice（既定）/ multi_select / true_false / free_text / numeric
+	Type             string   `json:"type,omitempty"`
+	PartialCredit    bool     `json:"partial_credit,omitempty"`    // multi_select の部分点
+	AcceptedAnswers  []string `json:"accepted_answers,omitempty"`  // free_text の許容解答
+	CorrectBoolean   *bool    `json:"correct_boolean,omitempty"`   // true_false の正解
+	NumericAnswer    *float64 `json:"numeric_answer,omitempty"`    // numeric の正解
+	NumericTolerance float64  `json:"numeric_tolerance,omitempty"` // numeric の許容誤差
+
+	ShuffleChoices bool `json:"shuffle_choices,omitempty"` // 回答者ごとに選択肢をシャッフルする
 }
 
 // UpdateQuestionRequest は問題更新リクエストのHTTP DTO
+// tags を省略した場合はタグを変更しない
 type UpdateQuestionRequest struct {
-	Title       string `json:"title"`
-	Body        string `json:"body"`
-	Explanation string `json:"explanation"`
+	Title       string   `json:"title"`
+	Body        string   `json:"body"`
+	Explanation string   `json:"explanation"`
+	Tags        []string `json:"tags,omitempty"`
+
+	PartialCredit    *bool    `json:"partial_credit,omitempty"`
+	AcceptedAnswers  []string `json:"accepted_answers,omitempty"`
+	CorrectBoolean   *bool    `json:"correct_boolean,omitempty"`
+	NumericAnswer    *float64 `json:"numeric_answer,omitempty"`
+	NumericTolerance *float64 `json:"numeric_tolerance,omitempty"`
+
+	ShuffleChoices *bool `json:"shuffle_choices,omitempty"`
 }
 
 // QuestionResponse は問題レスポンスのHTTP DTO
@@ -29,4 +50,29 @@ type QuestionResponse struct {
 	Views          int       `json:"views"`
 	CorrectCount   int       `json:"correct_count"`
 	IncorrectCount int       `json:"incorrect_count"`
-}
\ No newline at end of file
+	Tags           []string  `json:"tags"`
+
+	Type             string  `json:"type"`
+	PartialCredit    bool    `json:"partial_credit"`
+	NumericTolerance float64 `json:"numeric_tolerance,omitempty"`
+
+	ShuffleChoices bool `json:"shuffle_choices"`
+
+	Version   int       `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OwnerQuestionResponse は作成者に返す問題レスポンスのHTTP DTO（正解を含む）
+type OwnerQuestionResponse struct {
+	QuestionResponse
+
+	AcceptedAnswers []string `json:"accepted_answers,omitempty"`
+	CorrectBoolean  *bool    `json:"correct_boolean,omitempty"`
+	NumericAnswer   *float64 `json:"numeric_answer,omitempty"`
+}
+
+// UpdateQuestionResponse は問題更新レスポンスのHTTP DTO
+type UpdateQuestionResponse struct {
+	Message string `json:"message"`
+	Version int    `json:"version"`
+}
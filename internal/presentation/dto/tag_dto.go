@@ -0,0 +1,10 @@
+package dto
+
+// tag_dto.goはタグ関連のHTTP DTOを定義
+
+// TagResponse はタグレスポンスのHTTP DTO
+type TagResponse struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	UsageCount int    `json:"usage_count"`
+}
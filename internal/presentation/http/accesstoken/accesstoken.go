@@ -0,0 +1,119 @@
+package accesstoken
+
+// accesstoken.goはアクセストークン（SupabaseのJWT）の署名を検証し、ユーザーIDをコンテキストで受け渡す
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken はトークンの形式・署名・有効期限のいずれかが正しくないことを表す
+var ErrInvalidToken = errors.New("invalid access token")
+
+// Verifier はHS256で署名されたアクセストークンを検証する
+type Verifier struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewVerifier はSupabaseのJWTシークレットで検証するVerifierを作成（シークレットが空なら nil）
+func NewVerifier(secret string) *Verifier {
+	if secret == "" {
+		return nil
+	}
+	return &Verifier{secret: []byte(secret), now: time.Now}
+}
+
+// UserID はトークンを検証し、ユーザーID（sub）を返す
+func (v *Verifier) UserID(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", ErrInvalidToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return "", ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return "", ErrInvalidToken
+	}
+
+	var claims struct {
+		Sub string `json:"sub"`
+		Exp int64  `json:"exp"`
+	}
+	if err := decodeSegment(parts[1], &claims); err != nil || claims.Sub == "" {
+		return "", ErrInvalidToken
+	}
+	if !v.now().Before(time.Unix(claims.Exp, 0)) {
+		return "", ErrInvalidToken
+	}
+	return claims.Sub, nil
+}
+
+// decodeSegment はJWTの部分（base64url の JSON）を読み込む
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+type contextKey struct{}
+
+// WithUserID はコンテキストに検証済みのユーザーIDを設定する
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, userID)
+}
+
+// FromContext はコンテキストから検証済みのユーザーIDを取得する（未設定なら空文字）
+func FromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(contextKey{}).(string)
+	return userID
+}
+
+// Bearer は Authorization ヘッダーのトークンを返す（なければ空文字）
+func Bearer(r *http.Request) string {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// Middleware は Authorization ヘッダーのトークンを検証し、ユーザーIDをコンテキストに設定するミドルウェアを返す
+// 検証できないトークンのリクエストもそのまま通す（認証が必要かどうかはハンドラーが判断する）
+// verifier が nil（JWTシークレットが未設定）の場合は何も設定しない
+func Middleware(verifier *Verifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if verifier == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token := Bearer(r); token != "" {
+				if userID, err := verifier.UserID(token); err == nil {
+					r = r.WithContext(WithUserID(r.Context(), userID))
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
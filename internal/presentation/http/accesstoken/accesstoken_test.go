@@ -0,0 +1,69 @@
+package accesstoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sign はテスト用にHS256で署名したトークンを作成する
+func sign(secret, header, payload string) string {
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString([]byte(payload))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifier_UserID(t *testing.T) {
+	verifier := NewVerifier("secret")
+	verifier.now = func() time.Time { return time.Unix(1000, 0) }
+	header := `{"alg":"HS256","typ":"JWT"}`
+
+	userID, err := verifier.UserID(sign("secret", header, `{"sub":"user-1","exp":2000}`))
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", userID)
+
+	tests := map[string]string{
+		"別のシークレットで署名": sign("other", header, `{"sub":"user-1","exp":2000}`),
+		"有効期限切れ":      sign("secret", header, `{"sub":"user-1","exp":1000}`),
+		"有効期限なし":      sign("secret", header, `{"sub":"user-1"}`),
+		"ユーザーIDなし":    sign("secret", header, `{"exp":2000}`),
+		"HS256 以外の方式": sign("secret", `{"alg":"none"}`, `{"sub":"user-1","exp":2000}`),
+		"署名なし":        sign("secret", header, `{"sub":"user-1","exp":2000}`)[:10],
+		"JWT ではない文字列": "not-a-token",
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := verifier.UserID(token)
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var got string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = FromContext(r.Context())
+	})
+	serve := func(handler http.Handler, token string) string {
+		got = ""
+		req := httptest.NewRequest(http.MethodPost, "/api/answers", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return got
+	}
+	valid := sign("secret", `{"alg":"HS256"}`, `{"sub":"user-1","exp":4102444800}`)
+
+	assert.Equal(t, "user-1", serve(Middleware(NewVerifier("secret"))(next), valid))
+	assert.Empty(t, serve(Middleware(NewVerifier("secret"))(next), sign("forged", `{"alg":"HS256"}`, `{"sub":"user-2","exp":4102444800}`)))
+
+	// シークレットが未設定なら検証しない（ユーザーIDを設定しない）
+	assert.Empty(t, serve(Middleware(NewVerifier(""))(next), valid))
+}
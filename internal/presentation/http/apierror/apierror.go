@@ -0,0 +1,172 @@
+package apierror
+
+// apierror.goはAPIのエラーレスポンスを1か所で組み立てる
+// ハンドラー・ミドルウェア・ルーターは全てここを通してエラーを返す
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"Shittaka_back/internal/domain/shared"
+	presentationDTO "Shittaka_back/internal/presentation/dto"
+	"Shittaka_back/internal/presentation/http/i18n"
+	"Shittaka_back/internal/presentation/http/requestid"
+)
+
+// プレゼンテーション層で使うエラーコード
+// ドメイン層のコード（NOT_FOUND, FORBIDDEN など）は DomainError の Code をそのまま使う
+const (
+	CodeValidation             = "VALIDATION_ERROR"
+	CodeBadRequest             = "BAD_REQUEST"
+	CodeInvalidJSON            = "INVALID_JSON"
+	CodeUnauthorized           = "UNAUTHORIZED"
+	CodeInvalidToken           = "INVALID_TOKEN"
+	CodeNotFound               = "NOT_FOUND"
+	CodeMethodNotAllowed       = "METHOD_NOT_ALLOWED"
+	CodePayloadTooLarge        = "PAYLOAD_TOO_LARGE"
+	CodeUnsupportedMediaType   = "UNSUPPORTED_MEDIA_TYPE"
+	CodeRateLimited            = "RATE_LIMITED"
+	CodeInvalidIdempotencyKey  = "INVALID_IDEMPOTENCY_KEY"
+	CodeIdempotencyKeyInUse    = "IDEMPOTENCY_KEY_IN_USE"
+	CodeIdempotencyKeyMismatch = "IDEMPOTENCY_KEY_MISMATCH"
+	CodePreconditionRequired   = "PRECONDITION_REQUIRED"
+	CodeInternal               = "INTERNAL_ERROR"
+)
+
+// statusByCode はエラーコードとHTTPステータスの対応表
+// ここにないコードは 500 として扱う
+var statusByCode = map[string]int{
+	CodeValidation:            http.StatusBadRequest,
+	CodeBadRequest:            http.StatusBadRequest,
+	CodeInvalidJSON:           http.StatusBadRequest,
+	"INVALID_ID":              http.StatusBadRequest,
+	CodeInvalidIdempotencyKey: http.StatusBadRequest,
+
+	CodeUnauthorized: http.StatusUnauthorized,
+	CodeInvalidToken: http.StatusUnauthorized,
+	"AUTH_FAILED":    http.StatusUnauthorized,
+
+	"FORBIDDEN": http.StatusForbidden,
+
+	CodeNotFound: http.StatusNotFound,
+
+	CodeMethodNotAllowed: http.StatusMethodNotAllowed,
+
+	CodePayloadTooLarge: http.StatusRequestEntityTooLarge,
+
+	CodeUnsupportedMediaType: http.StatusUnsupportedMediaType,
+
+	CodeRateLimited:  http.StatusTooManyRequests,
+	"ACCOUNT_LOCKED": http.StatusTooManyRequests,
+
+	"USER_EXISTS":           http.StatusConflict,
+	"GENRE_EXISTS":          http.StatusConflict,
+	"CHOICE_EXISTS":         http.StatusConflict,
+	CodeIdempotencyKeyInUse: http.StatusConflict,
+
+	CodeIdempotencyKeyMismatch: http.StatusUnprocessableEntity,
+
+	"PRECONDITION_FAILED": http.StatusPreconditionFailed,
+
+	CodePreconditionRequired: http.StatusPreconditionRequired,
+
+	CodeInternal: http.StatusInternalServerError,
+}
+
+// StatusFor はエラーコードに対応するHTTPステータスを返す
+func StatusFor(code string) int {
+	if status, ok := statusByCode[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// Write はユースケースやサービスから返ったエラーをエラーレスポンスとして書き込む
+// ValidationError(s) と DomainError はコードとパラメータから Accept-Language に応じたメッセージを組み立て、
+// それ以外（外部サービスのエラーなど）は詳細をログにのみ出力してクライアントには汎用メッセージを返す
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	var validationErrs shared.ValidationErrors
+	var validationErr shared.ValidationError
+	var domainErr shared.DomainError
+	lang := i18n.FromRequest(r)
+
+	switch {
+	case errors.As(err, &validationErrs) && len(validationErrs) > 0:
+		writeValidation(w, r, lang, validationErrs)
+	case errors.As(err, &validationErr):
+		writeValidation(w, r, lang, shared.ValidationErrors{validationErr})
+	case errors.As(err, &domainErr):
+		detail := presentationDTO.ErrorDetail{
+			Code:    domainErr.Code,
+			Message: i18n.Message(lang, i18n.ErrorKey(domainErr.Code), domainErr.Params),
+		}
+		// 再試行までの時間があればヘッダーとレスポンスの両方で知らせる
+		if retryAfter, ok := domainErr.Params["retry_after"].(int); ok {
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			detail.RetryAfter = retryAfter
+		}
+		if unlockAt, ok := domainErr.Params["unlock_at"].(time.Time); ok {
+			detail.UnlockAt = unlockAt.UTC().Format(time.RFC3339)
+		}
+		if currentVersion, ok := domainErr.Params["current_version"].(int); ok {
+			detail.CurrentVersion = currentVersion
+		}
+		write(w, r, lang, StatusFor(domainErr.Code), detail)
+	default:
+		slog.ErrorContext(r.Context(), "unhandled error", "method", r.Method, "path", r.URL.Path, "error", err)
+		write(w, r, lang, http.StatusInternalServerError, presentationDTO.ErrorDetail{
+			Code:    CodeInternal,
+			Message: i18n.Message(lang, i18n.ErrorKey(CodeInternal), nil),
+		})
+	}
+}
+
+// Respond はエラーコードを指定してエラーレスポンスを書き込む（メッセージはカタログから組み立てる）
+func Respond(w http.ResponseWriter, r *http.Request, code string) {
+	lang := i18n.FromRequest(r)
+	write(w, r, lang, StatusFor(code), presentationDTO.ErrorDetail{
+		Code:    code,
+		Message: i18n.Message(lang, i18n.ErrorKey(code), nil),
+	})
+}
+
+// writeValidation はバリデーションエラーを全項目分のメッセージとともに書き込む
+// message と field には最初の項目を入れる
+func writeValidation(w http.ResponseWriter, r *http.Request, lang i18n.Lang, errs shared.ValidationErrors) {
+	fieldErrors := make([]presentationDTO.FieldError, len(errs))
+	for i, err := range errs {
+		params := map[string]interface{}{"field": err.Field}
+		for key, value := range err.Params {
+			params[key] = value
+		}
+		fieldErrors[i] = presentationDTO.FieldError{
+			Field:   err.Field,
+			Code:    err.Code,
+			Message: i18n.Message(lang, i18n.ValidationKey(err.Code), params),
+		}
+	}
+
+	write(w, r, lang, StatusFor(CodeValidation), presentationDTO.ErrorDetail{
+		Code:    CodeValidation,
+		Message: fieldErrors[0].Message,
+		Field:   fieldErrors[0].Field,
+		Errors:  fieldErrors,
+	})
+}
+
+// write はエラーレスポンスを書き込む
+func write(w http.ResponseWriter, r *http.Request, lang i18n.Lang, statusCode int, detail presentationDTO.ErrorDetail) {
+	detail.RequestID = requestid.FromContext(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Language", string(lang))
+	w.Header().Add("Vary", "Accept-Language")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(presentationDTO.ErrorResponse{Error: detail}); err != nil {
+		slog.ErrorContext(r.Context(), "failed to encode JSON response", "error", err)
+	}
+}
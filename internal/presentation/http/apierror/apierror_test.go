@@ -0,0 +1,145 @@
+package apierror
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Shittaka_back/internal/domain/shared"
+	presentationDTO "Shittaka_back/internal/presentation/dto"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantField  string
+	}{
+		{
+			name:       "バリデーションエラー",
+			err:        shared.NewValidationError("title", shared.ValidationRequired),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   CodeValidation,
+			wantField:  "title",
+		},
+		{
+			name:       "ドメインエラーは対応表のステータス",
+			err:        shared.NewDomainError("GENRE_EXISTS"),
+			wantStatus: http.StatusConflict,
+			wantCode:   "GENRE_EXISTS",
+		},
+		{
+			name:       "ラップされたドメインエラー",
+			err:        fmt.Errorf("wrapped: %w", shared.NewDomainError("NOT_FOUND").With("resource", "question")),
+			wantStatus: http.StatusNotFound,
+			wantCode:   "NOT_FOUND",
+		},
+		{
+			name:       "対応表にないコードは500",
+			err:        shared.NewDomainError("INVALID_ANSWER_KEY"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   "INVALID_ANSWER_KEY",
+		},
+		{
+			name:       "外部サービスのエラーは詳細を返さない",
+			err:        shared.NewInfrastructureError("find question", 500, `{"message":"relation does not exist"}`),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			Write(rec, httptest.NewRequest(http.MethodGet, "/api/questions/1", nil), tt.err)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.NotContains(t, rec.Body.String(), "relation does not exist")
+
+			var body presentationDTO.ErrorResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantCode, body.Error.Code)
+			assert.Equal(t, tt.wantField, body.Error.Field)
+		})
+	}
+}
+
+func TestWrite_Localized(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		err            error
+		wantLanguage   string
+		wantMessage    string
+	}{
+		{
+			name:         "指定がなければ日本語",
+			err:          shared.NewValidationError("title", shared.ValidationMaxLength).With("max", 200),
+			wantLanguage: "ja",
+			wantMessage:  "問題タイトルは200文字以内で入力してください",
+		},
+		{
+			name:           "英語を指定",
+			acceptLanguage: "en-US,en;q=0.9",
+			err:            shared.NewValidationError("title", shared.ValidationMaxLength).With("max", 200),
+			wantLanguage:   "en",
+			wantMessage:    "Title must be at most 200 characters",
+		},
+		{
+			name:           "リソース名を埋め込む",
+			acceptLanguage: "en",
+			err:            shared.NewDomainError("NOT_FOUND").With("resource", "question"),
+			wantLanguage:   "en",
+			wantMessage:    "Question not found",
+		},
+		{
+			name:           "対応していない言語は日本語",
+			acceptLanguage: "fr",
+			err:            shared.NewDomainError("FORBIDDEN"),
+			wantLanguage:   "ja",
+			wantMessage:    "この操作を行う権限がありません",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/questions/1", nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			rec := httptest.NewRecorder()
+			Write(rec, req, tt.err)
+
+			var body presentationDTO.ErrorResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantMessage, body.Error.Message)
+			assert.Equal(t, tt.wantLanguage, rec.Header().Get("Content-Language"))
+		})
+	}
+}
+
+func TestWrite_ValidationErrors(t *testing.T) {
+	err := shared.ValidationErrors{
+		shared.NewValidationError("genre_id", shared.ValidationRequired),
+		shared.NewValidationError("title", shared.ValidationMaxLength).With("max", 200),
+	}
+
+	rec := httptest.NewRecorder()
+	Write(rec, httptest.NewRequest(http.MethodPost, "/api/questions", nil), err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var body presentationDTO.ErrorResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, CodeValidation, body.Error.Code)
+	assert.Equal(t, "genre_id", body.Error.Field)
+	assert.Equal(t, []presentationDTO.FieldError{
+		{Field: "genre_id", Code: shared.ValidationRequired, Message: "ジャンルIDは必須です"},
+		{Field: "title", Code: shared.ValidationMaxLength, Message: "問題タイトルは200文字以内で入力してください"},
+	}, body.Error.Errors)
+}
@@ -0,0 +1,60 @@
+package clientip
+
+// clientip.goはクライアントのIPアドレスを取り出し、コンテキストで受け渡す
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type contextKey struct{}
+
+// WithIP はコンテキストにクライアントのIPアドレスを設定する
+func WithIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, contextKey{}, ip)
+}
+
+// FromContext はコンテキストからクライアントのIPアドレスを取得する（未設定なら空文字）
+func FromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(contextKey{}).(string)
+	return ip
+}
+
+// FromRequest はリクエストのクライアントのIPアドレスを返す
+// Middleware を通っていない場合は接続元のアドレスを使う
+func FromRequest(r *http.Request) string {
+	if ip := FromContext(r.Context()); ip != "" {
+		return ip
+	}
+	return remoteIP(r)
+}
+
+// Middleware はクライアントのIPアドレスをコンテキストに設定するミドルウェアを返す
+// header にはプロキシがクライアントのIPアドレスを設定するヘッダー（Fly.io では Fly-Client-IP）を指定する
+// header が空か、リクエストにない場合は接続元のアドレスを使う
+// プロキシを経由しない環境でヘッダーを指定すると、クライアントがIPアドレスを偽れることに注意
+func Middleware(header string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := ""
+			if header != "" {
+				ip = strings.TrimSpace(r.Header.Get(header))
+			}
+			if net.ParseIP(ip) == nil {
+				ip = remoteIP(r)
+			}
+			next.ServeHTTP(w, r.WithContext(WithIP(r.Context(), ip)))
+		})
+	}
+}
+
+// remoteIP は接続元のIPアドレスを返す
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,52 @@
+package etag
+
+// etag.goはリソースの版やレスポンスの内容を ETag として受け渡す
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// FromVersion は版を強い ETag（"v3" の形式）にする
+func FromVersion(version int) string {
+	return `"v` + strconv.Itoa(version) + `"`
+}
+
+// FromContent はレスポンスの内容から強い ETag を作る（内容がバイト単位で同じなら同じ値になる）
+func FromContent(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// ParseVersion は If-Match の ETag から版を取り出す
+// 版の一致を比べるため、弱い ETag（W/ 付き）や複数の ETag、* は受け付けない
+func ParseVersion(tag string) (int, bool) {
+	tag = strings.TrimSpace(tag)
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	digits, ok := strings.CutPrefix(tag[1:len(tag)-1], "v")
+	if !ok {
+		return 0, false
+	}
+	version, err := strconv.Atoi(digits)
+	if err != nil || version <= 0 || strconv.Itoa(version) != digits {
+		return 0, false
+	}
+	return version, true
+}
+
+// MatchNone は If-None-Match の値（カンマ区切りの ETag の一覧か *）が tag に一致するかを返す
+// If-None-Match は弱い比較を使うため、W/ の有無は区別しない（圧縮するプロキシなどが W/ を付けることがある）
+func MatchNone(header, tag string) bool {
+	tag = strings.TrimPrefix(tag, "W/")
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
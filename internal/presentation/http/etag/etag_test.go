@@ -0,0 +1,69 @@
+package etag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromVersion(t *testing.T) {
+	assert.Equal(t, `"v3"`, FromVersion(3))
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		tag     string
+		version int
+		ok      bool
+	}{
+		{tag: `"v3"`, version: 3, ok: true},
+		{tag: ` "v12" `, version: 12, ok: true},
+		{tag: `W/"v3"`},
+		{tag: `"v1", "v2"`},
+		{tag: `*`},
+		{tag: `v3`},
+		{tag: `"3"`},
+		{tag: `"v0"`},
+		{tag: `"v-1"`},
+		{tag: `"v03"`},
+		{tag: ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			version, ok := ParseVersion(tt.tag)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.version, version)
+		})
+	}
+}
+
+func TestFromContent(t *testing.T) {
+	tag := FromContent([]byte(`[{"id":1}]`))
+	assert.Equal(t, tag, FromContent([]byte(`[{"id":1}]`)))
+	assert.NotEqual(t, tag, FromContent([]byte(`[{"id":2}]`)))
+
+	// If-Match でも使えるよう強い ETag にする
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, tag)
+}
+
+func TestMatchNone(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		tag    string
+		want   bool
+	}{
+		{name: "一致", header: `"abc"`, tag: `"abc"`, want: true},
+		{name: "不一致", header: `"abc"`, tag: `"def"`},
+		{name: "一覧のいずれか", header: `"abc", "def"`, tag: `"def"`, want: true},
+		{name: "弱い ETag と強い ETag", header: `W/"abc"`, tag: `"abc"`, want: true},
+		{name: "強い ETag と弱い ETag", header: `"abc"`, tag: `W/"abc"`, want: true},
+		{name: "全て", header: `*`, tag: `"abc"`, want: true},
+		{name: "引用符のない値", header: `abc`, tag: `"abc"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MatchNone(tt.header, tt.tag))
+		})
+	}
+}
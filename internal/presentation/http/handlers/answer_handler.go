@@ -4,7 +4,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"strings"
 
@@ -12,6 +12,9 @@ import (
 	"Shittaka_back/internal/application/answer/usecases"
 	"Shittaka_back/internal/domain/shared"
 	presentationDTO "Shittaka_back/internal/presentation/dto"
+	"Shittaka_back/internal/presentation/http/apierror"
+	"Shittaka_back/internal/presentation/http/jsonbody"
+	"Shittaka_back/internal/presentation/http/versioning"
 )
 
 // AnswerHandler は回答関連のHTTPハンドラー
@@ -28,53 +31,58 @@ func NewAnswerHandler(answerUsecase *usecases.AnswerUsecase) *AnswerHandler {
 
 // CreateAnswerHandler は回答作成を処理
 func (h *AnswerHandler) CreateAnswerHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// 認証トークンの取得
 	userToken, err := h.extractToken(r)
 	if err != nil {
-		h.sendError(w, "認証が必要です", http.StatusUnauthorized)
+		apierror.Respond(w, r, apierror.CodeUnauthorized)
 		return
 	}
 
 	// ユーザーIDを取得
 	userID, err := h.getUserIDFromToken(userToken)
 	if err != nil {
-		h.sendError(w, "無効なトークンです", http.StatusUnauthorized)
+		apierror.Respond(w, r, apierror.CodeInvalidToken)
 		return
 	}
 
 	var req presentationDTO.CreateAnswerRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, "Invalid JSON format", http.StatusBadRequest)
+	if err := jsonbody.Decode(r, &req); err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
 	// DTOの変換
 	usecaseReq := answerDto.CreateAnswerRequest{
-		QuestionID: req.QuestionID,
-		ChoiceID:   req.ChoiceID,
+		QuestionID:    req.QuestionID,
+		ChoiceID:      req.ChoiceID,
+		ChoiceIDs:     req.ChoiceIDs,
+		BooleanAnswer: req.BooleanAnswer,
+		TextAnswer:    req.TextAnswer,
+		NumericAnswer: req.NumericAnswer,
 	}
 
 	answerResp, err := h.answerUsecase.CreateAnswer(r.Context(), usecaseReq, userID, userToken)
 	if err != nil {
-		h.handleUsecaseError(w, err)
+		apierror.Write(w, r, err)
 		return
 	}
 
 	// レスポンスDTOに変換
 	response := presentationDTO.AnswerResponse{
-		ID:         answerResp.ID,
-		UserID:     answerResp.UserID,
-		QuestionID: answerResp.QuestionID,
-		ChoiceID:   answerResp.ChoiceID,
-		AnsweredAt: answerResp.AnsweredAt,
-	}
-
-	h.sendJSON(w, response, http.StatusCreated)
+		ID:            answerResp.ID,
+		UserID:        answerResp.UserID,
+		QuestionID:    answerResp.QuestionID,
+		ChoiceID:      answerResp.ChoiceID,
+		ChoiceIDs:     answerResp.ChoiceIDs,
+		BooleanAnswer: answerResp.BooleanAnswer,
+		TextAnswer:    answerResp.TextAnswer,
+		NumericAnswer: answerResp.NumericAnswer,
+		IsCorrect:     answerResp.IsCorrect,
+		Score:         answerResp.Score,
+		AnsweredAt:    answerResp.AnsweredAt,
+	}
+
+	h.sendJSON(w, r, response, http.StatusCreated)
 }
 
 // ヘルパー関数
@@ -83,7 +91,7 @@ func (h *AnswerHandler) CreateAnswerHandler(w http.ResponseWriter, r *http.Reque
 func (h *AnswerHandler) extractToken(r *http.Request) (string, error) {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
-		return "", shared.NewDomainError("UNAUTHORIZED", "認証が必要です")
+		return "", shared.NewDomainError("UNAUTHORIZED")
 	}
 
 	// "Bearer " プレフィックスを除去
@@ -100,7 +108,7 @@ func (h *AnswerHandler) getUserIDFromToken(token string) (string, error) {
 	// JWTトークンを分割 (header.payload.signature)
 	parts := strings.Split(token, ".")
 	if len(parts) != 3 {
-		return "", shared.NewDomainError("INVALID_TOKEN", "Invalid JWT format")
+		return "", shared.NewDomainError("INVALID_TOKEN")
 	}
 
 	// payloadをデコード
@@ -120,45 +128,15 @@ func (h *AnswerHandler) getUserIDFromToken(token string) (string, error) {
 		return sub, nil
 	}
 
-	return "", shared.NewDomainError("INVALID_TOKEN", "User ID not found in token")
+	return "", shared.NewDomainError("INVALID_TOKEN")
 }
 
-// handleUsecaseError はユースケースエラーを適切なHTTPエラーに変換
-func (h *AnswerHandler) handleUsecaseError(w http.ResponseWriter, err error) {
-	switch e := err.(type) {
-	case shared.ValidationError:
-		h.sendError(w, e.Message, http.StatusBadRequest)
-	case shared.DomainError:
-		switch e.Code {
-		case "NOT_FOUND":
-			h.sendError(w, e.Message, http.StatusNotFound)
-		case "FORBIDDEN":
-			h.sendError(w, e.Message, http.StatusForbidden)
-		case "UNAUTHORIZED":
-			h.sendError(w, e.Message, http.StatusUnauthorized)
-		default:
-			h.sendError(w, e.Message, http.StatusInternalServerError)
-		}
-	default:
-		log.Printf("Answer usecase error: %v", err)
-		h.sendError(w, "Internal server error", http.StatusInternalServerError)
-	}
-}
-
-// sendJSON はJSONレスポンスを送信
-func (h *AnswerHandler) sendJSON(w http.ResponseWriter, data interface{}, statusCode int) {
+// sendJSON はJSONレスポンスを送信（APIバージョンに応じてレスポンスDTOを変換する）
+func (h *AnswerHandler) sendJSON(w http.ResponseWriter, r *http.Request, data interface{}, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		log.Printf("JSON encode error: %v", err)
+	if err := json.NewEncoder(w).Encode(versioning.MapResponse(r.Context(), data)); err != nil {
+		slog.ErrorContext(r.Context(), "failed to encode JSON response", "error", err)
 	}
 }
 
-// sendError はエラーレスポンスを送信
-func (h *AnswerHandler) sendError(w http.ResponseWriter, message string, statusCode int) {
-	response := presentationDTO.ErrorResponse{
-		Error:   http.StatusText(statusCode),
-		Message: message,
-	}
-	h.sendJSON(w, response, statusCode)
-}
\ No newline at end of file
@@ -5,14 +5,17 @@ package handlers
 
 import (
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
 
 	"Shittaka_back/internal/application/auth/dto"
 	"Shittaka_back/internal/application/auth/usecases"
-	"Shittaka_back/internal/domain/shared"
 	presentationDTO "Shittaka_back/internal/presentation/dto"
+	"Shittaka_back/internal/presentation/http/apierror"
+	"Shittaka_back/internal/presentation/http/jsonbody"
+	"Shittaka_back/internal/presentation/http/clientip"
+	"Shittaka_back/internal/presentation/http/versioning"
 )
 
 // AuthHandler は認証関連のHTTPハンドラー
@@ -29,14 +32,9 @@ func NewAuthHandler(authUsecase *usecases.AuthUsecase) *AuthHandler {
 
 // SignupHandler はユーザー登録を処理
 func (h *AuthHandler) SignupHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	var req presentationDTO.AuthRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, "Invalid JSON format", http.StatusBadRequest)
+	if err := jsonbody.Decode(r, &req); err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
@@ -49,7 +47,7 @@ func (h *AuthHandler) SignupHandler(w http.ResponseWriter, r *http.Request) {
 
 	authResp, err := h.authUsecase.SignUp(r.Context(), usecaseReq)
 	if err != nil {
-		h.handleUsecaseError(w, err)
+		apierror.Write(w, r, err)
 		return
 	}
 
@@ -65,19 +63,14 @@ func (h *AuthHandler) SignupHandler(w http.ResponseWriter, r *http.Request) {
 		ExpiresAt: authResp.ExpiresAt,
 	}
 
-	h.sendJSON(w, response, http.StatusCreated)
+	h.sendJSON(w, r, response, http.StatusCreated)
 }
 
 // LoginHandler はユーザーログインを処理
 func (h *AuthHandler) LoginHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	var req presentationDTO.AuthRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, "Invalid JSON format", http.StatusBadRequest)
+	if err := jsonbody.Decode(r, &req); err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
@@ -85,11 +78,12 @@ func (h *AuthHandler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	usecaseReq := dto.SignInRequest{
 		Email:    req.Email,
 		Password: req.Password,
+		ClientIP: clientip.FromRequest(r),
 	}
 
 	authResp, err := h.authUsecase.SignIn(r.Context(), usecaseReq)
 	if err != nil {
-		h.handleUsecaseError(w, err)
+		apierror.Write(w, r, err)
 		return
 	}
 
@@ -105,25 +99,49 @@ func (h *AuthHandler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		ExpiresAt: authResp.ExpiresAt,
 	}
 
-	h.sendJSON(w, response, http.StatusOK)
+	h.sendJSON(w, r, response, http.StatusOK)
 }
 
-// LogoutHandler はユーザーログアウトを処理
-func (h *AuthHandler) LogoutHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+// RefreshHandler はリフレッシュトークンによるトークンの更新を処理
+func (h *AuthHandler) RefreshHandler(w http.ResponseWriter, r *http.Request) {
+	var req presentationDTO.RefreshRequest
+	if err := jsonbody.Decode(r, &req); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	authResp, err := h.authUsecase.Refresh(r.Context(), dto.RefreshRequest{RefreshToken: req.RefreshToken})
+	if err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
+	// レスポンスDTOに変換
+	response := presentationDTO.AuthResponse{
+		Token:        authResp.Token,
+		RefreshToken: authResp.RefreshToken,
+		User: presentationDTO.UserDTO{
+			ID:       authResp.User.ID,
+			Email:    authResp.User.Email,
+			Username: authResp.User.Username,
+		},
+		ExpiresAt: authResp.ExpiresAt,
+	}
+
+	h.sendJSON(w, r, response, http.StatusOK)
+}
+
+// LogoutHandler はユーザーログアウトを処理
+func (h *AuthHandler) LogoutHandler(w http.ResponseWriter, r *http.Request) {
 	token := r.Header.Get("Authorization")
 	if token == "" {
-		h.sendError(w, "Authorization token required", http.StatusBadRequest)
+		apierror.Respond(w, r, apierror.CodeUnauthorized)
 		return
 	}
 
 	err := h.authUsecase.SignOut(r.Context(), token)
 	if err != nil {
-		h.handleUsecaseError(w, err)
+		apierror.Write(w, r, err)
 		return
 	}
 
@@ -133,20 +151,15 @@ func (h *AuthHandler) LogoutHandler(w http.ResponseWriter, r *http.Request) {
 
 // GetCurrentUserHandler は現在ログイン中のユーザー情報を取得
 func (h *AuthHandler) GetCurrentUserHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	token := r.Header.Get("Authorization")
 	if token == "" {
-		h.sendError(w, "Authorization token required", http.StatusUnauthorized)
+		apierror.Respond(w, r, apierror.CodeUnauthorized)
 		return
 	}
 
 	user, err := h.authUsecase.GetCurrentUser(r.Context(), token)
 	if err != nil {
-		h.handleUsecaseError(w, err)
+		apierror.Write(w, r, err)
 		return
 	}
 
@@ -157,61 +170,28 @@ func (h *AuthHandler) GetCurrentUserHandler(w http.ResponseWriter, r *http.Reque
 		Username: user.Username,
 	}
 
-	h.sendJSON(w, response, http.StatusOK)
+	h.sendJSON(w, r, response, http.StatusOK)
 }
 
 // TestConnectionHandler はSupabaseとの接続テストを行う
 func (h *AuthHandler) TestConnectionHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	response := map[string]interface{}{
 		"status":    "connected",
 		"message":   "Supabase connection is configured",
 		"timestamp": time.Now().Unix(),
 	}
 
-	h.sendJSON(w, response, http.StatusOK)
+	h.sendJSON(w, r, response, http.StatusOK)
 }
 
 // ヘルパー関数
 
-// handleUsecaseError はユースケースエラーを適切なHTTPエラーに変換
-func (h *AuthHandler) handleUsecaseError(w http.ResponseWriter, err error) {
-	switch e := err.(type) {
-	case shared.ValidationError:
-		h.sendError(w, e.Message, http.StatusBadRequest)
-	case shared.DomainError:
-		switch e.Code {
-		case "USER_EXISTS":
-			h.sendError(w, e.Message, http.StatusConflict)
-		case "AUTH_FAILED":
-			h.sendError(w, e.Message, http.StatusUnauthorized)
-		default:
-			h.sendError(w, e.Message, http.StatusInternalServerError)
-		}
-	default:
-		log.Printf("Usecase error: %v", err)
-		h.sendError(w, "Internal server error", http.StatusInternalServerError)
-	}
-}
-
-// sendJSON はJSONレスポンスを送信
-func (h *AuthHandler) sendJSON(w http.ResponseWriter, data interface{}, statusCode int) {
+// sendJSON はJSONレスポンスを送信（APIバージョンに応じてレスポンスDTOを変換する）
+func (h *AuthHandler) sendJSON(w http.ResponseWriter, r *http.Request, data interface{}, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		log.Printf("JSON encode error: %v", err)
+	if err := json.NewEncoder(w).Encode(versioning.MapResponse(r.Context(), data)); err != nil {
+		slog.ErrorContext(r.Context(), "failed to encode JSON response", "error", err)
 	}
 }
 
-// sendError はエラーレスポンスを送信
-func (h *AuthHandler) sendError(w http.ResponseWriter, message string, statusCode int) {
-	response := presentationDTO.ErrorResponse{
-		Error:   http.StatusText(statusCode),
-		Message: message,
-	}
-	h.sendJSON(w, response, statusCode)
-}
@@ -3,8 +3,10 @@ package handlers
 // choice_handler.goは選択肢に関するHTTPハンドラーを定義
 
 import (
+	"encoding/base64"
 	"encoding/json"
-	"log"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -13,6 +15,9 @@ import (
 	"Shittaka_back/internal/domain/choices/services"
 	"Shittaka_back/internal/domain/shared"
 	presentationDTO "Shittaka_back/internal/presentation/dto"
+	"Shittaka_back/internal/presentation/http/apierror"
+	"Shittaka_back/internal/presentation/http/jsonbody"
+	"Shittaka_back/internal/presentation/http/versioning"
 )
 
 // ChoiceHandler は選択肢関連のHTTPハンドラー
@@ -29,28 +34,22 @@ func NewChoiceHandler(choiceService *services.ChoiceService) *ChoiceHandler {
 
 // GetChoicesHandler は問題IDに紐づく選択肢を取得
 func (h *ChoiceHandler) GetChoicesHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+	// URLから問題IDを取得 (/api/questions/{id}/choices)
+	questionID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		apierror.Write(w, r, shared.NewValidationError("question_id", shared.ValidationInvalidFormat))
 		return
 	}
 
-	// URLから問題IDを取得 (/api/choices/{questionID})
-	path := r.URL.Path
-	parts := strings.Split(path, "/")
-	if len(parts) < 4 {
-		h.sendError(w, "Question ID is required", http.StatusBadRequest)
-		return
+	// ログイン中であれば利用者ごとのシャッフル順で返す（未ログインは共通の順序）
+	var userID string
+	if userToken, err := h.extractToken(r); err == nil {
+		userID, _ = h.getUserIDFromToken(userToken)
 	}
 
-	questionID, err := strconv.ParseInt(parts[3], 10, 64)
+	choices, err := h.choiceService.GetChoicesForUser(r.Context(), questionID, userID)
 	if err != nil {
-		h.sendError(w, "Invalid question ID", http.StatusBadRequest)
-		return
-	}
-
-	choices, err := h.choiceService.GetChoices(r.Context(), questionID)
-	if err != nil {
-		h.handleServiceError(w, err)
+		apierror.Write(w, r, err)
 		return
 	}
 
@@ -62,6 +61,7 @@ func (h *ChoiceHandler) GetChoicesHandler(w http.ResponseWriter, r *http.Request
 			QuestionID: choice.QuestionID,
 			Text:       choice.Text,
 			IsCorrect:  choice.IsCorrect,
+			Position:   choice.Position,
 		})
 	}
 
@@ -69,46 +69,45 @@ func (h *ChoiceHandler) GetChoicesHandler(w http.ResponseWriter, r *http.Request
 		Choices: choiceResponses,
 	}
 
-	h.sendJSON(w, response, http.StatusOK)
+	h.sendJSON(w, r, response, http.StatusOK)
 }
 
 // CreateChoiceHandler は新しい選択肢を作成
 func (h *ChoiceHandler) CreateChoiceHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// 認証トークンの取得
 	userToken, err := h.extractToken(r)
 	if err != nil {
-		h.sendError(w, "認証が必要です", http.StatusUnauthorized)
+		apierror.Respond(w, r, apierror.CodeUnauthorized)
 		return
 	}
-	
-	// デバッグ用ログ
-	if len(userToken) > 20 {
-		log.Printf("Choice Handler - UserToken: %s...", userToken[:20])
-	} else {
-		log.Printf("Choice Handler - UserToken: %s", userToken)
-	}
 
 	var req presentationDTO.CreateChoiceRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, "Invalid JSON format", http.StatusBadRequest)
+	if err := jsonbody.Decode(r, &req); err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
+	// POST /api/questions/{id}/choices ではURLの問題IDを優先する（旧パスはボディの question_id を使用）
+	if id := r.PathValue("id"); id != "" {
+		questionID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			apierror.Write(w, r, shared.NewValidationError("question_id", shared.ValidationInvalidFormat))
+			return
+		}
+		req.QuestionID = questionID
+	}
+
 	// エンティティに変換
 	choice := entities.Choice{
 		QuestionID: req.QuestionID,
 		Text:       req.Text,
 		IsCorrect:  req.IsCorrect,
+		Position:   req.Position,
 	}
 
 	createdChoice, err := h.choiceService.CreateChoiceWithAuth(r.Context(), choice, userToken)
 	if err != nil {
-		h.handleServiceError(w, err)
+		apierror.Write(w, r, err)
 		return
 	}
 
@@ -118,22 +117,34 @@ func (h *ChoiceHandler) CreateChoiceHandler(w http.ResponseWriter, r *http.Reque
 		QuestionID: createdChoice.QuestionID,
 		Text:       createdChoice.Text,
 		IsCorrect:  createdChoice.IsCorrect,
+		Position:   createdChoice.Position,
 	}
 
-	h.sendJSON(w, response, http.StatusCreated)
+	h.sendJSON(w, r, response, http.StatusCreated)
 }
 
 // UpdateChoiceHandler は既存の選択肢を更新
 func (h *ChoiceHandler) UpdateChoiceHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+	var req presentationDTO.UpdateChoiceRequest
+	if err := jsonbody.Decode(r, &req); err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
-	var req presentationDTO.UpdateChoiceRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, "Invalid JSON format", http.StatusBadRequest)
-		return
+	// PUT /api/questions/{id}/choices/{choiceID} ではURLのIDを優先する（旧パスはボディの id を使用）
+	if r.PathValue("choiceID") != "" {
+		questionID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			apierror.Write(w, r, shared.NewValidationError("question_id", shared.ValidationInvalidFormat))
+			return
+		}
+		choiceID, err := strconv.ParseInt(r.PathValue("choiceID"), 10, 64)
+		if err != nil {
+			apierror.Write(w, r, shared.NewValidationError("choice_id", shared.ValidationInvalidFormat))
+			return
+		}
+		req.QuestionID = questionID
+		req.ID = choiceID
 	}
 
 	// エンティティに変換
@@ -142,11 +153,12 @@ func (h *ChoiceHandler) UpdateChoiceHandler(w http.ResponseWriter, r *http.Reque
 		QuestionID: req.QuestionID,
 		Text:       req.Text,
 		IsCorrect:  req.IsCorrect,
+		Position:   req.Position,
 	}
 
 	updatedChoice, err := h.choiceService.UpdateChoice(r.Context(), choice)
 	if err != nil {
-		h.handleServiceError(w, err)
+		apierror.Write(w, r, err)
 		return
 	}
 
@@ -156,38 +168,148 @@ func (h *ChoiceHandler) UpdateChoiceHandler(w http.ResponseWriter, r *http.Reque
 		QuestionID: updatedChoice.QuestionID,
 		Text:       updatedChoice.Text,
 		IsCorrect:  updatedChoice.IsCorrect,
+		Position:   updatedChoice.Position,
 	}
 
-	h.sendJSON(w, response, http.StatusOK)
+	h.sendJSON(w, r, response, http.StatusOK)
 }
 
 // DeleteChoiceHandler は選択肢を削除
 func (h *ChoiceHandler) DeleteChoiceHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
+	// URLから選択肢IDを取得 (/api/questions/{id}/choices/{choiceID})
+	choiceID, err := strconv.ParseInt(r.PathValue("choiceID"), 10, 64)
+	if err != nil {
+		apierror.Write(w, r, shared.NewValidationError("choice_id", shared.ValidationInvalidFormat))
 		return
 	}
 
-	// URLから選択肢IDを取得 (/api/choices/{id}/delete)
-	path := r.URL.Path
-	parts := strings.Split(path, "/")
-	if len(parts) < 4 {
-		h.sendError(w, "Choice ID is required", http.StatusBadRequest)
+	// 問題IDは旧パス（/api/choices/delete/{choiceID}）にはないため、ある場合のみ確認する
+	var questionID int64
+	if r.PathValue("id") != "" {
+		questionID, err = strconv.ParseInt(r.PathValue("id"), 10, 64)
+		if err != nil {
+			apierror.Write(w, r, shared.NewValidationError("question_id", shared.ValidationInvalidFormat))
+			return
+		}
+	}
+
+	if err := h.choiceService.DeleteChoice(r.Context(), questionID, choiceID); err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
-	choiceID, err := strconv.ParseInt(parts[3], 10, 64)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ReorderChoicesHandler は問題の選択肢を並べ替える
+// PUT /api/questions/{id}/choices/order
+func (h *ChoiceHandler) ReorderChoicesHandler(w http.ResponseWriter, r *http.Request) {
+	// 認証トークンの取得
+	userToken, err := h.extractToken(r)
 	if err != nil {
-		h.sendError(w, "Invalid choice ID", http.StatusBadRequest)
+		apierror.Respond(w, r, apierror.CodeUnauthorized)
 		return
 	}
 
-	if err := h.choiceService.DeleteChoice(r.Context(), choiceID); err != nil {
-		h.handleServiceError(w, err)
+	// トークンからユーザーIDを取得
+	userID, err := h.getUserIDFromToken(userToken)
+	if err != nil {
+		apierror.Respond(w, r, apierror.CodeInvalidToken)
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	questionID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		apierror.Write(w, r, shared.NewValidationError("question_id", shared.ValidationInvalidFormat))
+		return
+	}
+
+	var req presentationDTO.ReorderChoicesRequest
+	if err := jsonbody.Decode(r, &req); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	choices, err := h.choiceService.ReorderChoices(r.Context(), questionID, req.ChoiceIDs, userID, userToken)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	// レスポンスDTOに変換
+	choiceResponses := make([]presentationDTO.ChoiceResponse, 0, len(choices))
+	for _, choice := range choices {
+		choiceResponses = append(choiceResponses, presentationDTO.ChoiceResponse{
+			ID:         choice.ID,
+			QuestionID: choice.QuestionID,
+			Text:       choice.Text,
+			IsCorrect:  choice.IsCorrect,
+			Position:   choice.Position,
+		})
+	}
+
+	h.sendJSON(w, r, presentationDTO.ChoicesResponse{Choices: choiceResponses}, http.StatusOK)
+}
+
+// ReplaceChoicesHandler は問題の選択肢一式を置き換える
+// PUT /api/questions/{id}/choices
+func (h *ChoiceHandler) ReplaceChoicesHandler(w http.ResponseWriter, r *http.Request) {
+	// 認証トークンの取得
+	userToken, err := h.extractToken(r)
+	if err != nil {
+		apierror.Respond(w, r, apierror.CodeUnauthorized)
+		return
+	}
+
+	// トークンからユーザーIDを取得
+	userID, err := h.getUserIDFromToken(userToken)
+	if err != nil {
+		apierror.Respond(w, r, apierror.CodeInvalidToken)
+		return
+	}
+
+	questionID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		apierror.Write(w, r, shared.NewValidationError("question_id", shared.ValidationInvalidFormat))
+		return
+	}
+
+	var req presentationDTO.ReplaceChoicesRequest
+	if err := jsonbody.Decode(r, &req); err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	// エンティティに変換
+	desired := make([]entities.Choice, len(req.Choices))
+	for i, c := range req.Choices {
+		desired[i] = entities.Choice{
+			ID:         c.ID,
+			QuestionID: questionID,
+			Text:       c.Text,
+			IsCorrect:  c.IsCorrect,
+		}
+	}
+
+	choices, err := h.choiceService.ReplaceChoices(r.Context(), questionID, desired, userID, userToken)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	// レスポンスDTOに変換
+	choiceResponses := make([]presentationDTO.ChoiceResponse, 0, len(choices))
+	for _, choice := range choices {
+		choiceResponses = append(choiceResponses, presentationDTO.ChoiceResponse{
+			ID:         choice.ID,
+			QuestionID: choice.QuestionID,
+			Text:       choice.Text,
+			IsCorrect:  choice.IsCorrect,
+			Position:   choice.Position,
+		})
+	}
+
+	h.sendJSON(w, r, presentationDTO.ChoicesResponse{Choices: choiceResponses}, http.StatusOK)
 }
 
 // ヘルパー関数
@@ -196,7 +318,7 @@ func (h *ChoiceHandler) DeleteChoiceHandler(w http.ResponseWriter, r *http.Reque
 func (h *ChoiceHandler) extractToken(r *http.Request) (string, error) {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
-		return "", shared.NewDomainError("UNAUTHORIZED", "認証が必要です")
+		return "", shared.NewDomainError("UNAUTHORIZED")
 	}
 
 	// "Bearer " プレフィックスを除去
@@ -208,40 +330,40 @@ func (h *ChoiceHandler) extractToken(r *http.Request) (string, error) {
 	return userToken, nil
 }
 
-// handleServiceError はサービスエラーを適切なHTTPエラーに変換
-func (h *ChoiceHandler) handleServiceError(w http.ResponseWriter, err error) {
-	switch e := err.(type) {
-	case shared.ValidationError:
-		h.sendError(w, e.Message, http.StatusBadRequest)
-	case shared.DomainError:
-		switch e.Code {
-		case "NOT_FOUND":
-			h.sendError(w, e.Message, http.StatusNotFound)
-		case "CHOICE_EXISTS":
-			h.sendError(w, e.Message, http.StatusConflict)
-		default:
-			h.sendError(w, e.Message, http.StatusInternalServerError)
-		}
-	default:
-		log.Printf("Choice service error: %v", err)
-		h.sendError(w, "Internal server error", http.StatusInternalServerError)
+// getUserIDFromToken はJWTトークンからユーザーIDを取得
+func (h *ChoiceHandler) getUserIDFromToken(token string) (string, error) {
+	// JWTトークンを分割 (header.payload.signature)
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", shared.NewDomainError("INVALID_TOKEN")
+	}
+
+	// payloadをデコード
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("failed to decode JWT payload: %w", err)
 	}
+
+	// JSONとしてパース
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("failed to parse JWT claims: %w", err)
+	}
+
+	// subクレームからユーザーIDを取得
+	if sub, ok := claims["sub"].(string); ok && sub != "" {
+		return sub, nil
+	}
+
+	return "", shared.NewDomainError("INVALID_TOKEN")
 }
 
-// sendJSON はJSONレスポンスを送信
-func (h *ChoiceHandler) sendJSON(w http.ResponseWriter, data interface{}, statusCode int) {
+// sendJSON はJSONレスポンスを送信（APIバージョンに応じてレスポンスDTOを変換する）
+func (h *ChoiceHandler) sendJSON(w http.ResponseWriter, r *http.Request, data interface{}, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		log.Printf("JSON encode error: %v", err)
+	if err := json.NewEncoder(w).Encode(versioning.MapResponse(r.Context(), data)); err != nil {
+		slog.ErrorContext(r.Context(), "failed to encode JSON response", "error", err)
 	}
 }
 
-// sendError はエラーレスポンスを送信
-func (h *ChoiceHandler) sendError(w http.ResponseWriter, message string, statusCode int) {
-	response := presentationDTO.ErrorResponse{
-		Error:   http.StatusText(statusCode),
-		Message: message,
-	}
-	h.sendJSON(w, response, statusCode)
-}
\ No newline at end of file
@@ -4,13 +4,15 @@ package handlers
 
 import (
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
 
 	genreDto "Shittaka_back/internal/application/genre/dto"
 	"Shittaka_back/internal/application/genre/usecases"
-	"Shittaka_back/internal/domain/shared"
 	presentationDTO "Shittaka_back/internal/presentation/dto"
+	"Shittaka_back/internal/presentation/http/apierror"
+	"Shittaka_back/internal/presentation/http/jsonbody"
+	"Shittaka_back/internal/presentation/http/versioning"
 )
 
 // GenreHandler はジャンル関連のHTTPハンドラー
@@ -27,15 +29,10 @@ func NewGenreHandler(genreUsecase *usecases.GenreUsecase) *GenreHandler {
 
 // CreateGenreHandler はジャンル作成を処理
 func (h *GenreHandler) CreateGenreHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// 認証トークンの取得
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
-		h.sendError(w, "認証が必要です", http.StatusUnauthorized)
+		apierror.Respond(w, r, apierror.CodeUnauthorized)
 		return
 	}
 	
@@ -46,8 +43,8 @@ func (h *GenreHandler) CreateGenreHandler(w http.ResponseWriter, r *http.Request
 	}
 
 	var req presentationDTO.CreateGenreRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, "Invalid JSON format", http.StatusBadRequest)
+	if err := jsonbody.Decode(r, &req); err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
@@ -58,7 +55,7 @@ func (h *GenreHandler) CreateGenreHandler(w http.ResponseWriter, r *http.Request
 
 	genreResp, err := h.genreUsecase.CreateGenre(r.Context(), usecaseReq, userToken)
 	if err != nil {
-		h.handleUsecaseError(w, err)
+		apierror.Write(w, r, err)
 		return
 	}
 
@@ -68,61 +65,28 @@ func (h *GenreHandler) CreateGenreHandler(w http.ResponseWriter, r *http.Request
 		Name: genreResp.Name,
 	}
 
-	h.sendJSON(w, response, http.StatusCreated)
+	h.sendJSON(w, r, response, http.StatusCreated)
 }
 
 // GetAllGenresHandler は全ジャンルの取得を処理
 func (h *GenreHandler) GetAllGenresHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	genres, err := h.genreUsecase.GetAllGenres(r.Context())
 	if err != nil {
-		h.handleUsecaseError(w, err)
+		apierror.Write(w, r, err)
 		return
 	}
 
-	h.sendJSON(w, genres, http.StatusOK)
+	h.sendJSON(w, r, genres, http.StatusOK)
 }
 
 // ヘルパー関数
 
-// handleUsecaseError はユースケースエラーを適切なHTTPエラーに変換
-func (h *GenreHandler) handleUsecaseError(w http.ResponseWriter, err error) {
-	switch e := err.(type) {
-	case shared.ValidationError:
-		h.sendError(w, e.Message, http.StatusBadRequest)
-	case shared.DomainError:
-		switch e.Code {
-		case "GENRE_EXISTS":
-			h.sendError(w, e.Message, http.StatusConflict)
-		case "NOT_FOUND":
-			h.sendError(w, e.Message, http.StatusNotFound)
-		default:
-			h.sendError(w, e.Message, http.StatusInternalServerError)
-		}
-	default:
-		log.Printf("Genre usecase error: %v", err)
-		h.sendError(w, "Internal server error", http.StatusInternalServerError)
-	}
-}
-
-// sendJSON はJSONレスポンスを送信
-func (h *GenreHandler) sendJSON(w http.ResponseWriter, data interface{}, statusCode int) {
+// sendJSON はJSONレスポンスを送信（APIバージョンに応じてレスポンスDTOを変換する）
+func (h *GenreHandler) sendJSON(w http.ResponseWriter, r *http.Request, data interface{}, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		log.Printf("JSON encode error: %v", err)
+	if err := json.NewEncoder(w).Encode(versioning.MapResponse(r.Context(), data)); err != nil {
+		slog.ErrorContext(r.Context(), "failed to encode JSON response", "error", err)
 	}
 }
 
-// sendError はエラーレスポンスを送信
-func (h *GenreHandler) sendError(w http.ResponseWriter, message string, statusCode int) {
-	response := presentationDTO.ErrorResponse{
-		Error:   http.StatusText(statusCode),
-		Message: message,
-	}
-	h.sendJSON(w, response, statusCode)
-}
\ No newline at end of file
@@ -0,0 +1,83 @@
+package handlers
+
+// health_handler.goはヘルスチェックに関するHTTPハンドラーを定義
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	healthDto "Shittaka_back/internal/application/health/dto"
+	"Shittaka_back/internal/application/health/usecases"
+	presentationDTO "Shittaka_back/internal/presentation/dto"
+	"Shittaka_back/internal/presentation/http/versioning"
+)
+
+// HealthHandler はヘルスチェック関連のHTTPハンドラー
+type HealthHandler struct {
+	healthUsecase *usecases.HealthUsecase
+}
+
+// NewHealthHandler は新しいHealthHandlerを作成
+func NewHealthHandler(healthUsecase *usecases.HealthUsecase) *HealthHandler {
+	return &HealthHandler{
+		healthUsecase: healthUsecase,
+	}
+}
+
+// LiveHandler はプロセスが応答できるかどうかを返す（依存サービスは確認しない）
+func (h *HealthHandler) LiveHandler(w http.ResponseWriter, r *http.Request) {
+	h.sendJSON(w, r, presentationDTO.HealthResponse{Status: healthDto.StatusOK}, http.StatusOK)
+}
+
+// ReadyHandler は依存サービス（PostgREST・GoTrue）に接続できるかどうかを返す
+// 接続できない依存サービスがあれば 503 を返す
+func (h *HealthHandler) ReadyHandler(w http.ResponseWriter, r *http.Request) {
+	readiness := h.healthUsecase.Readiness(r.Context())
+
+	checks := make([]presentationDTO.DependencyStatus, len(readiness.Checks))
+	for i, check := range readiness.Checks {
+		checks[i] = presentationDTO.DependencyStatus{
+			Name:      check.Name,
+			Status:    check.Status,
+			LatencyMs: float64(check.Latency.Microseconds()) / 1000,
+		}
+	}
+
+	statusCode := http.StatusOK
+	if readiness.Status != healthDto.StatusOK {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	h.sendJSON(w, r, presentationDTO.ReadinessResponse{
+		Status:    readiness.Status,
+		Checks:    checks,
+		CheckedAt: readiness.CheckedAt.UTC().Format(time.RFC3339),
+	}, statusCode)
+}
+
+// VersionHandler はビルド情報を返す
+func (h *HealthHandler) VersionHandler(w http.ResponseWriter, r *http.Request) {
+	version := h.healthUsecase.Version()
+
+	h.sendJSON(w, r, presentationDTO.VersionResponse{
+		Version:    version.Version,
+		Commit:     version.Commit,
+		CommitTime: version.CommitTime,
+		Modified:   version.Modified,
+		GoVersion:  version.GoVersion,
+	}, http.StatusOK)
+}
+
+// ヘルパー関数
+
+// sendJSON はJSONレスポンスを送信（APIバージョンに応じてレスポンスDTOを変換する）
+func (h *HealthHandler) sendJSON(w http.ResponseWriter, r *http.Request, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(versioning.MapResponse(r.Context(), data)); err != nil {
+		slog.ErrorContext(r.Context(), "failed to encode JSON response", "error", err)
+	}
+}
@@ -2,13 +2,16 @@ package handlers
 
 import (
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
 
 	"Shittaka_back/internal/application/profile/dto"
 	"Shittaka_back/internal/application/profile/usecases"
 	"Shittaka_back/internal/domain/shared"
 	presentationDTO "Shittaka_back/internal/presentation/dto"
+	"Shittaka_back/internal/presentation/http/apierror"
+	"Shittaka_back/internal/presentation/http/jsonbody"
+	"Shittaka_back/internal/presentation/http/versioning"
 )
 
 // ProfileHandler はプロフィール関連のHTTPハンドラー
@@ -25,21 +28,16 @@ func NewProfileHandler(profileUsecase *usecases.ProfileUsecase) *ProfileHandler
 
 // GetProfileHandler はプロフィールを取得
 func (h *ProfileHandler) GetProfileHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	// URLパスからuser_idを取得 (/api/profiles/{user_id})
-	userID := r.URL.Path[len("/api/profiles/"):]
+	// URLパスからuser_idを取得 (/api/profiles/{userID})
+	userID := r.PathValue("userID")
 	if userID == "" {
-		h.sendError(w, "User ID is required", http.StatusBadRequest)
+		apierror.Write(w, r, shared.NewValidationError("user_id", shared.ValidationRequired))
 		return
 	}
 
 	profile, err := h.profileUsecase.GetProfile(r.Context(), userID)
 	if err != nil {
-		h.handleUsecaseError(w, err)
+		apierror.Write(w, r, err)
 		return
 	}
 
@@ -49,19 +47,14 @@ func (h *ProfileHandler) GetProfileHandler(w http.ResponseWriter, r *http.Reques
 		Name: profile.Name,
 	}
 
-	h.sendJSON(w, response, http.StatusOK)
+	h.sendJSON(w, r, response, http.StatusOK)
 }
 
 // CreateProfileHandler はプロフィールを作成
 func (h *ProfileHandler) CreateProfileHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	var req presentationDTO.CreateProfileRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, "Invalid JSON format", http.StatusBadRequest)
+	if err := jsonbody.Decode(r, &req); err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
@@ -73,7 +66,7 @@ func (h *ProfileHandler) CreateProfileHandler(w http.ResponseWriter, r *http.Req
 
 	profile, err := h.profileUsecase.CreateProfile(r.Context(), usecaseReq)
 	if err != nil {
-		h.handleUsecaseError(w, err)
+		apierror.Write(w, r, err)
 		return
 	}
 
@@ -83,26 +76,21 @@ func (h *ProfileHandler) CreateProfileHandler(w http.ResponseWriter, r *http.Req
 		Name: profile.Name,
 	}
 
-	h.sendJSON(w, response, http.StatusCreated)
+	h.sendJSON(w, r, response, http.StatusCreated)
 }
 
 // UpdateProfileHandler はプロフィールを更新
 func (h *ProfileHandler) UpdateProfileHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "PUT" {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// URLパスからuser_idを取得
-	userID := r.URL.Path[len("/api/profiles/"):]
+	userID := r.PathValue("userID")
 	if userID == "" {
-		h.sendError(w, "User ID is required", http.StatusBadRequest)
+		apierror.Write(w, r, shared.NewValidationError("user_id", shared.ValidationRequired))
 		return
 	}
 
 	var req presentationDTO.UpdateProfileRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, "Invalid JSON format", http.StatusBadRequest)
+	if err := jsonbody.Decode(r, &req); err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
@@ -113,7 +101,7 @@ func (h *ProfileHandler) UpdateProfileHandler(w http.ResponseWriter, r *http.Req
 
 	profile, err := h.profileUsecase.UpdateProfile(r.Context(), userID, usecaseReq)
 	if err != nil {
-		h.handleUsecaseError(w, err)
+		apierror.Write(w, r, err)
 		return
 	}
 
@@ -123,43 +111,17 @@ func (h *ProfileHandler) UpdateProfileHandler(w http.ResponseWriter, r *http.Req
 		Name: profile.Name,
 	}
 
-	h.sendJSON(w, response, http.StatusOK)
+	h.sendJSON(w, r, response, http.StatusOK)
 }
 
 // ヘルパー関数
 
-// handleUsecaseError はユースケースエラーを適切なHTTPエラーに変換
-func (h *ProfileHandler) handleUsecaseError(w http.ResponseWriter, err error) {
-	switch e := err.(type) {
-	case shared.ValidationError:
-		h.sendError(w, e.Message, http.StatusBadRequest)
-	case shared.DomainError:
-		switch e.Code {
-		case "NOT_FOUND":
-			h.sendError(w, e.Message, http.StatusNotFound)
-		default:
-			h.sendError(w, e.Message, http.StatusInternalServerError)
-		}
-	default:
-		log.Printf("Profile usecase error: %v", err)
-		h.sendError(w, "Internal server error", http.StatusInternalServerError)
-	}
-}
-
-// sendJSON はJSONレスポンスを送信
-func (h *ProfileHandler) sendJSON(w http.ResponseWriter, data interface{}, statusCode int) {
+// sendJSON はJSONレスポンスを送信（APIバージョンに応じてレスポンスDTOを変換する）
+func (h *ProfileHandler) sendJSON(w http.ResponseWriter, r *http.Request, data interface{}, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		log.Printf("JSON encode error: %v", err)
+	if err := json.NewEncoder(w).Encode(versioning.MapResponse(r.Context(), data)); err != nil {
+		slog.ErrorContext(r.Context(), "failed to encode JSON response", "error", err)
 	}
 }
 
-// sendError はエラーレスポンスを送信
-func (h *ProfileHandler) sendError(w http.ResponseWriter, message string, statusCode int) {
-	response := presentationDTO.ErrorResponse{
-		Error:   http.StatusText(statusCode),
-		Message: message,
-	}
-	h.sendJSON(w, response, statusCode)
-}
\ No newline at end of file
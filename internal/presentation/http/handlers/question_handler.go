@@ -4,7 +4,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -13,6 +13,10 @@ import (
 	"Shittaka_back/internal/application/question/usecases"
 	"Shittaka_back/internal/domain/shared"
 	presentationDTO "Shittaka_back/internal/presentation/dto"
+	"Shittaka_back/internal/presentation/http/apierror"
+	"Shittaka_back/internal/presentation/http/etag"
+	"Shittaka_back/internal/presentation/http/jsonbody"
+	"Shittaka_back/internal/presentation/http/versioning"
 )
 
 // QuestionHandler は問題関連のHTTPハンドラー
@@ -29,28 +33,23 @@ func NewQuestionHandler(questionUsecase *usecases.QuestionUsecase) *QuestionHand
 
 // CreateQuestionHandler は問題作成を処理
 func (h *QuestionHandler) CreateQuestionHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// 認証トークンの取得
 	userToken, err := h.extractToken(r)
 	if err != nil {
-		h.sendError(w, "認証が必要です", http.StatusUnauthorized)
+		apierror.Respond(w, r, apierror.CodeUnauthorized)
 		return
 	}
 
 	// ユーザーIDを取得
 	userID, err := h.getUserIDFromToken(userToken)
 	if err != nil {
-		h.sendError(w, "無効なトークンです", http.StatusUnauthorized)
+		apierror.Respond(w, r, apierror.CodeInvalidToken)
 		return
 	}
 
 	var req presentationDTO.CreateQuestionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, "Invalid JSON format", http.StatusBadRequest)
+	if err := jsonbody.Decode(r, &req); err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
@@ -60,62 +59,64 @@ func (h *QuestionHandler) CreateQuestionHandler(w http.ResponseWriter, r *http.R
 		Title:       req.Title,
 		Body:        req.Body,
 		Explanation: req.Explanation,
+		Tags:        req.Tags,
+
+		Type:             req.Type,
+		PartialCredit:    req.PartialCredit,
+		AcceptedAnswers:  req.AcceptedAnswers,
+		CorrectBoolean:   req.CorrectBoolean,
+		NumericAnswer:    req.NumericAnswer,
+		NumericTolerance: req.NumericTolerance,
+		ShuffleChoices:   req.ShuffleChoices,
 	}
 
 	questionResp, err := h.questionUsecase.CreateQuestion(r.Context(), usecaseReq, userID, userToken)
 	if err != nil {
-		h.handleUsecaseError(w, err)
+		apierror.Write(w, r, err)
 		return
 	}
 
 	// レスポンスDTOに変換
-	response := presentationDTO.QuestionResponse{
-		ID:             questionResp.ID,
-		GenreID:        questionResp.GenreID,
-		UserID:         questionResp.UserID,
-		Title:          questionResp.Title,
-		Body:           questionResp.Body,
-		Explanation:    questionResp.Explanation,
-		CreatedAt:      questionResp.CreatedAt,
-		Views:          questionResp.Views,
-		CorrectCount:   questionResp.CorrectCount,
-		IncorrectCount: questionResp.IncorrectCount,
-	}
-
-	h.sendJSON(w, response, http.StatusCreated)
+	response := toOwnerQuestionResponse(*questionResp)
+
+	h.sendJSON(w, r, response, http.StatusCreated)
 }
 
 // UpdateQuestionHandler は問題更新を処理
 func (h *QuestionHandler) UpdateQuestionHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// 認証トークンの取得
 	userToken, err := h.extractToken(r)
 	if err != nil {
-		h.sendError(w, "認証が必要です", http.StatusUnauthorized)
+		apierror.Respond(w, r, apierror.CodeUnauthorized)
 		return
 	}
 
 	// ユーザーIDを取得
 	userID, err := h.getUserIDFromToken(userToken)
 	if err != nil {
-		h.sendError(w, "無効なトークンです", http.StatusUnauthorized)
+		apierror.Respond(w, r, apierror.CodeInvalidToken)
 		return
 	}
 
 	// URLから問題IDを取得
-	questionID, err := h.getQuestionIDFromPath(r.URL.Path)
+	questionID, err := h.getQuestionIDFromPath(r)
 	if err != nil {
-		h.sendError(w, "Invalid question ID", http.StatusBadRequest)
+		apierror.Write(w, r, shared.NewValidationError("question_id", shared.ValidationInvalidFormat))
 		return
 	}
 
+	// 他の変更を上書きしないよう、編集を始めたときの版を If-Match で必須にする
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		apierror.Respond(w, r, apierror.CodePreconditionRequired)
+		return
+	}
+	// 解釈できない ETag はどの版にも一致しない（0 は存在しない版）
+	version, _ := etag.ParseVersion(ifMatch)
+
 	var req presentationDTO.UpdateQuestionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.sendError(w, "Invalid JSON format", http.StatusBadRequest)
+	if err := jsonbody.Decode(r, &req); err != nil {
+		apierror.Write(w, r, err)
 		return
 	}
 
@@ -124,178 +125,182 @@ func (h *QuestionHandler) UpdateQuestionHandler(w http.ResponseWriter, r *http.R
 		Title:       req.Title,
 		Body:        req.Body,
 		Explanation: req.Explanation,
+		Tags:        req.Tags,
+
+		PartialCredit:    req.PartialCredit,
+		AcceptedAnswers:  req.AcceptedAnswers,
+		CorrectBoolean:   req.CorrectBoolean,
+		NumericAnswer:    req.NumericAnswer,
+		NumericTolerance: req.NumericTolerance,
+		ShuffleChoices:   req.ShuffleChoices,
+
+		Version: version,
 	}
 
-	err = h.questionUsecase.UpdateQuestion(r.Context(), questionID, usecaseReq, userID, userToken)
+	newVersion, err := h.questionUsecase.UpdateQuestion(r.Context(), questionID, usecaseReq, userID, userToken)
 	if err != nil {
-		h.handleUsecaseError(w, err)
+		apierror.Write(w, r, err)
 		return
 	}
 
-	h.sendJSON(w, map[string]string{"message": "問題が正常に更新されました"}, http.StatusOK)
+	response := presentationDTO.UpdateQuestionResponse{
+		Message: "問題が正常に更新されました",
+		Version: newVersion,
+	}
+	w.Header().Set("ETag", etag.FromVersion(newVersion))
+	h.sendJSON(w, r, response, http.StatusOK)
 }
 
 // DeleteQuestionHandler は問題削除を処理
 func (h *QuestionHandler) DeleteQuestionHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// 認証トークンの取得
 	userToken, err := h.extractToken(r)
 	if err != nil {
-		h.sendError(w, "認証が必要です", http.StatusUnauthorized)
+		apierror.Respond(w, r, apierror.CodeUnauthorized)
 		return
 	}
 
 	// ユーザーIDを取得
 	userID, err := h.getUserIDFromToken(userToken)
 	if err != nil {
-		h.sendError(w, "無効なトークンです", http.StatusUnauthorized)
+		apierror.Respond(w, r, apierror.CodeInvalidToken)
 		return
 	}
 
 	// URLから問題IDを取得
-	questionID, err := h.getQuestionIDFromPath(r.URL.Path)
+	questionID, err := h.getQuestionIDFromPath(r)
 	if err != nil {
-		h.sendError(w, "Invalid question ID", http.StatusBadRequest)
+		apierror.Write(w, r, shared.NewValidationError("question_id", shared.ValidationInvalidFormat))
 		return
 	}
 
 	err = h.questionUsecase.DeleteQuestion(r.Context(), questionID, userID, userToken)
 	if err != nil {
-		h.handleUsecaseError(w, err)
+		apierror.Write(w, r, err)
 		return
 	}
 
-	h.sendJSON(w, map[string]string{"message": "問題が正常に削除されました"}, http.StatusOK)
+	h.sendJSON(w, r, map[string]string{"message": "問題が正常に削除されました"}, http.StatusOK)
 }
 
 // GetQuestionHandler は問題取得を処理
 func (h *QuestionHandler) GetQuestionHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// URLから問題IDを取得
-	questionID, err := h.getQuestionIDFromPath(r.URL.Path)
+	questionID, err := h.getQuestionIDFromPath(r)
 	if err != nil {
-		h.sendError(w, "Invalid question ID", http.StatusBadRequest)
+		apierror.Write(w, r, shared.NewValidationError("question_id", shared.ValidationInvalidFormat))
 		return
 	}
 
 	questionResp, err := h.questionUsecase.GetQuestion(r.Context(), questionID)
 	if err != nil {
-		h.handleUsecaseError(w, err)
+		apierror.Write(w, r, err)
 		return
 	}
 
 	// レスポンスDTOに変換
-	response := presentationDTO.QuestionResponse{
-		ID:             questionResp.ID,
-		GenreID:        questionResp.GenreID,
-		UserID:         questionResp.UserID,
-		Title:          questionResp.Title,
-		Body:           questionResp.Body,
-		Explanation:    questionResp.Explanation,
-		CreatedAt:      questionResp.CreatedAt,
-		Views:          questionResp.Views,
-		CorrectCount:   questionResp.CorrectCount,
-		IncorrectCount: questionResp.IncorrectCount,
-	}
-
-	h.sendJSON(w, response, http.StatusOK)
+	response := toQuestionResponse(*questionResp)
+
+	// 更新時に If-Match で送り返してもらう版
+	w.Header().Set("ETag", etag.FromVersion(questionResp.Version))
+	h.sendJSON(w, r, response, http.StatusOK)
 }
 
-// GetQuestionsHandler は問題一覧取得を処理
+// GetQuestionsHandler は問題一覧取得を処理（?tag=go&tag=web または ?tags=go,web でタグ絞り込み）
 func (h *QuestionHandler) GetQuestionsHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+	filter := questionDto.QuestionFilter{
+		Tags: h.getTagsFromQuery(r),
 	}
 
-	questionResp, err := h.questionUsecase.GetAllQuestions(r.Context())
+	questionResp, err := h.questionUsecase.GetAllQuestions(r.Context(), filter)
 	if err != nil {
-		h.handleUsecaseError(w, err)
+		apierror.Write(w, r, err)
 		return
 	}
 
 	// レスポンスDTOに変換
 	responses := make([]presentationDTO.QuestionResponse, len(questionResp))
 	for i, q := range questionResp {
-		responses[i] = presentationDTO.QuestionResponse{
-			ID:             q.ID,
-			GenreID:        q.GenreID,
-			UserID:         q.UserID,
-			Title:          q.Title,
-			Body:           q.Body,
-			Explanation:    q.Explanation,
-			CreatedAt:      q.CreatedAt,
-			Views:          q.Views,
-			CorrectCount:   q.CorrectCount,
-			IncorrectCount: q.IncorrectCount,
-		}
+		responses[i] = toQuestionResponse(*q)
 	}
 
-	h.sendJSON(w, responses, http.StatusOK)
+	h.sendJSON(w, r, responses, http.StatusOK)
 }
 
 // GetMyQuestionsHandler はユーザーの問題一覧取得を処理
 func (h *QuestionHandler) GetMyQuestionsHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		h.sendError(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	// 認証トークンの取得
 	userToken, err := h.extractToken(r)
 	if err != nil {
-		h.sendError(w, "認証が必要です", http.StatusUnauthorized)
+		apierror.Respond(w, r, apierror.CodeUnauthorized)
 		return
 	}
 
 	// ユーザーIDを取得
 	userID, err := h.getUserIDFromToken(userToken)
 	if err != nil {
-		h.sendError(w, "無効なトークンです", http.StatusUnauthorized)
+		apierror.Respond(w, r, apierror.CodeInvalidToken)
 		return
 	}
 
 	questionResp, err := h.questionUsecase.GetQuestionsByUser(r.Context(), userID, userToken)
 	if err != nil {
-		h.handleUsecaseError(w, err)
+		apierror.Write(w, r, err)
 		return
 	}
 
 	// レスポンスDTOに変換
-	responses := make([]presentationDTO.QuestionResponse, len(questionResp))
+	// 自分の問題なので正解も返す
+	responses := make([]presentationDTO.OwnerQuestionResponse, len(questionResp))
 	for i, q := range questionResp {
-		responses[i] = presentationDTO.QuestionResponse{
-			ID:             q.ID,
-			GenreID:        q.GenreID,
-			UserID:         q.UserID,
-			Title:          q.Title,
-			Body:           q.Body,
-			Explanation:    q.Explanation,
-			CreatedAt:      q.CreatedAt,
-			Views:          q.Views,
-			CorrectCount:   q.CorrectCount,
-			IncorrectCount: q.IncorrectCount,
-		}
+		responses[i] = toOwnerQuestionResponse(*q)
 	}
 
-	h.sendJSON(w, responses, http.StatusOK)
+	h.sendJSON(w, r, responses, http.StatusOK)
 }
 
 // ヘルパー関数
 
+// toQuestionResponse は問題をHTTPのレスポンスDTOに変換（正解は含めない）
+func toQuestionResponse(q questionDto.QuestionResponse) presentationDTO.QuestionResponse {
+	return presentationDTO.QuestionResponse{
+		ID:             q.ID,
+		GenreID:        q.GenreID,
+		UserID:         q.UserID,
+		Title:          q.Title,
+		Body:           q.Body,
+		Explanation:    q.Explanation,
+		CreatedAt:      q.CreatedAt,
+		Views:          q.Views,
+		CorrectCount:   q.CorrectCount,
+		IncorrectCount: q.IncorrectCount,
+		Tags:           q.Tags,
+
+		Type:             q.Type,
+		PartialCredit:    q.PartialCredit,
+		NumericTolerance: q.NumericTolerance,
+		ShuffleChoices:   q.ShuffleChoices,
+
+		Version:   q.Version,
+		UpdatedAt: q.UpdatedAt,
+	}
+}
+
+// toOwnerQuestionResponse は作成者向けに正解を含めたレスポンスDTOに変換
+func toOwnerQuestionResponse(q questionDto.QuestionResponse) presentationDTO.OwnerQuestionResponse {
+	return presentationDTO.OwnerQuestionResponse{
+		QuestionResponse: toQuestionResponse(q),
+		AcceptedAnswers:  q.AcceptedAnswers,
+		CorrectBoolean:   q.CorrectBoolean,
+		NumericAnswer:    q.NumericAnswer,
+	}
+}
+
 // extractToken はリクエストからトークンを抽出
 func (h *QuestionHandler) extractToken(r *http.Request) (string, error) {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
-		return "", shared.NewDomainError("UNAUTHORIZED", "認証が必要です")
+		return "", shared.NewDomainError("UNAUTHORIZED")
 	}
 
 	// "Bearer " プレフィックスを除去
@@ -312,7 +317,7 @@ func (h *QuestionHandler) getUserIDFromToken(token string) (string, error) {
 	// JWTトークンを分割 (header.payload.signature)
 	parts := strings.Split(token, ".")
 	if len(parts) != 3 {
-		return "", shared.NewDomainError("INVALID_TOKEN", "Invalid JWT format")
+		return "", shared.NewDomainError("INVALID_TOKEN")
 	}
 
 	// payloadをデコード
@@ -332,62 +337,39 @@ func (h *QuestionHandler) getUserIDFromToken(token string) (string, error) {
 		return sub, nil
 	}
 
-	return "", shared.NewDomainError("INVALID_TOKEN", "User ID not found in token")
+	return "", shared.NewDomainError("INVALID_TOKEN")
 }
 
-// getQuestionIDFromPath はURLパスから問題IDを取得
-func (h *QuestionHandler) getQuestionIDFromPath(path string) (int64, error) {
-	// "/api/questions/{id}" の形式から ID を取得
-	parts := strings.Split(path, "/")
-	if len(parts) < 4 {
-		return 0, shared.NewDomainError("INVALID_PATH", "Invalid question ID path")
-	}
-
-	idStr := parts[len(parts)-1]
-	questionID, err := strconv.ParseInt(idStr, 10, 64)
+// getQuestionIDFromPath はURLパスの {id} から問題IDを取得
+func (h *QuestionHandler) getQuestionIDFromPath(r *http.Request) (int64, error) {
+	questionID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
 	if err != nil {
-		return 0, shared.NewDomainError("INVALID_ID", "Invalid question ID format")
+		return 0, shared.NewDomainError("INVALID_ID")
 	}
 
 	return questionID, nil
 }
 
-// handleUsecaseError はユースケースエラーを適切なHTTPエラーに変換
-func (h *QuestionHandler) handleUsecaseError(w http.ResponseWriter, err error) {
-	switch e := err.(type) {
-	case shared.ValidationError:
-		h.sendError(w, e.Message, http.StatusBadRequest)
-	case shared.DomainError:
-		switch e.Code {
-		case "NOT_FOUND":
-			h.sendError(w, e.Message, http.StatusNotFound)
-		case "FORBIDDEN":
-			h.sendError(w, e.Message, http.StatusForbidden)
-		case "UNAUTHORIZED":
-			h.sendError(w, e.Message, http.StatusUnauthorized)
-		default:
-			h.sendError(w, e.Message, http.StatusInternalServerError)
+// getTagsFromQuery はクエリパラメータから絞り込み用のタグ一覧を取得
+func (h *QuestionHandler) getTagsFromQuery(r *http.Request) []string {
+	query := r.URL.Query()
+	tags := query["tag"]
+	for _, value := range query["tags"] {
+		for _, tag := range strings.Split(value, ",") {
+			if strings.TrimSpace(tag) != "" {
+				tags = append(tags, tag)
+			}
 		}
-	default:
-		log.Printf("Question usecase error: %v", err)
-		h.sendError(w, "Internal server error", http.StatusInternalServerError)
 	}
+	return tags
 }
 
-// sendJSON はJSONレスポンスを送信
-func (h *QuestionHandler) sendJSON(w http.ResponseWriter, data interface{}, statusCode int) {
+// sendJSON はJSONレスポンスを送信（APIバージョンに応じてレスポンスDTOを変換する）
+func (h *QuestionHandler) sendJSON(w http.ResponseWriter, r *http.Request, data interface{}, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		log.Printf("JSON encode error: %v", err)
+	if err := json.NewEncoder(w).Encode(versioning.MapResponse(r.Context(), data)); err != nil {
+		slog.ErrorContext(r.Context(), "failed to encode JSON response", "error", err)
 	}
 }
 
-// sendError はエラーレスポンスを送信
-func (h *QuestionHandler) sendError(w http.ResponseWriter, message string, statusCode int) {
-	response := presentationDTO.ErrorResponse{
-		Error:   http.StatusText(statusCode),
-		Message: message,
-	}
-	h.sendJSON(w, response, statusCode)
-}
\ No newline at end of file
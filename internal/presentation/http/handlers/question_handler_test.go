@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	questionDto "Shittaka_back/internal/application/question/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuestionResponse_AnswerKeyOnlyForOwner(t *testing.T) {
+	correct := true
+	answer := 42.0
+	question := questionDto.QuestionResponse{
+		ID:              1,
+		Title:           "地球は丸い？",
+		Type:            "true_false",
+		AcceptedAnswers: []string{"はい"},
+		CorrectBoolean:  &correct,
+		NumericAnswer:   &answer,
+	}
+	answerKey := []string{"accepted_answers", "correct_boolean", "numeric_answer"}
+
+	t.Run("公開のレスポンスには正解を含めない", func(t *testing.T) {
+		fields := marshalFields(t, toQuestionResponse(question))
+		assert.Equal(t, "地球は丸い？", fields["title"])
+		for _, key := range answerKey {
+			assert.NotContains(t, fields, key)
+		}
+	})
+
+	t.Run("作成者向けのレスポンスには正解を含める", func(t *testing.T) {
+		fields := marshalFields(t, toOwnerQuestionResponse(question))
+		assert.Equal(t, "地球は丸い？", fields["title"])
+		for _, key := range answerKey {
+			assert.Contains(t, fields, key)
+		}
+	})
+}
+
+// marshalFields はレスポンスをJSONにしたときのフィールドを返す
+func marshalFields(t *testing.T, v any) map[string]any {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(data, &fields))
+	return fields
+}
@@ -0,0 +1,75 @@
+package handlers
+
+// tag_handler.goはタグに関するHTTPハンドラーを定義
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	tagDto "Shittaka_back/internal/application/tag/dto"
+	"Shittaka_back/internal/application/tag/usecases"
+	"Shittaka_back/internal/domain/shared"
+	presentationDTO "Shittaka_back/internal/presentation/dto"
+	"Shittaka_back/internal/presentation/http/apierror"
+	"Shittaka_back/internal/presentation/http/versioning"
+)
+
+// TagHandler はタグ関連のHTTPハンドラー
+type TagHandler struct {
+	tagUsecase *usecases.TagUsecase
+}
+
+// NewTagHandler は新しいTagHandlerを作成
+func NewTagHandler(tagUsecase *usecases.TagUsecase) *TagHandler {
+	return &TagHandler{
+		tagUsecase: tagUsecase,
+	}
+}
+
+// GetTagsHandler はタグ一覧の取得を処理（?prefix= による前方一致、?limit= で件数指定）
+func (h *TagHandler) GetTagsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := tagDto.SearchTagsRequest{
+		Prefix: query.Get("prefix"),
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			apierror.Write(w, r, shared.NewValidationError("limit", shared.ValidationInvalidFormat))
+			return
+		}
+		req.Limit = limit
+	}
+
+	tags, err := h.tagUsecase.SearchTags(r.Context(), req)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	// レスポンスDTOに変換
+	responses := make([]presentationDTO.TagResponse, len(tags))
+	for i, tag := range tags {
+		responses[i] = presentationDTO.TagResponse{
+			ID:         tag.ID,
+			Name:       tag.Name,
+			UsageCount: tag.UsageCount,
+		}
+	}
+
+	h.sendJSON(w, r, responses, http.StatusOK)
+}
+
+// ヘルパー関数
+
+// sendJSON はJSONレスポンスを送信（APIバージョンに応じてレスポンスDTOを変換する）
+func (h *TagHandler) sendJSON(w http.ResponseWriter, r *http.Request, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(versioning.MapResponse(r.Context(), data)); err != nil {
+		slog.ErrorContext(r.Context(), "failed to encode JSON response", "error", err)
+	}
+}
+
@@ -0,0 +1,114 @@
+package i18n
+
+// i18n.goはエラーメッセージの言語選択とメッセージの組み立てを行う
+// メッセージは言語ごとのカタログ（messages_ja.go / messages_en.go）にキーで登録する
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// Lang はメッセージの言語
+type Lang string
+
+const (
+	Japanese Lang = "ja"
+	English  Lang = "en"
+
+	// Default は Accept-Language で対応言語が見つからない場合の言語
+	Default = Japanese
+)
+
+// catalogs は言語ごとのメッセージカタログ
+var catalogs = map[Lang]map[string]string{
+	Japanese: messagesJa,
+	English:  messagesEn,
+}
+
+// supported は matcher に渡した順の対応言語
+var supported = []Lang{Japanese, English}
+
+var matcher = language.NewMatcher([]language.Tag{language.Japanese, language.English})
+
+// placeholder はメッセージ中の {name} 形式のプレースホルダー
+var placeholder = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// Negotiate は Accept-Language ヘッダーの値から使用する言語を決める
+func Negotiate(acceptLanguage string) Lang {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	return supported[index]
+}
+
+// FromRequest はリクエストの Accept-Language から使用する言語を決める
+func FromRequest(r *http.Request) Lang {
+	return Negotiate(r.Header.Get("Accept-Language"))
+}
+
+// ErrorKey はエラーコード（NOT_FOUND など）のメッセージキーを返す
+func ErrorKey(code string) string {
+	return "error." + code
+}
+
+// ValidationKey はバリデーションエラーのコード（required など）のメッセージキーを返す
+func ValidationKey(code string) string {
+	return "validation." + code
+}
+
+// Lookup はキーに対応するメッセージを返す
+// 指定した言語にない場合は既定の言語のカタログを参照する
+func Lookup(lang Lang, key string) (string, bool) {
+	if message, ok := catalogs[lang][key]; ok {
+		return message, true
+	}
+	message, ok := catalogs[Default][key]
+	return message, ok
+}
+
+// Message はキーに対応するメッセージのプレースホルダーを params で置き換えて返す
+// 文字列のパラメータは "<名前>.<値>" のラベル（field.title など）があればそれに置き換え、
+// パラメータがないプレースホルダーは "<名前>.default" のラベルを使う
+// キーがカタログにない場合はキーをそのまま返す
+func Message(lang Lang, key string, params map[string]interface{}) string {
+	template, ok := Lookup(lang, key)
+	if !ok {
+		return key
+	}
+
+	message := placeholder.ReplaceAllStringFunc(template, func(match string) string {
+		name := match[1 : len(match)-1]
+		value, ok := params[name]
+		if !ok {
+			label, _ := Lookup(lang, name+".default")
+			return label
+		}
+		if s, isString := value.(string); isString {
+			if label, found := Lookup(lang, name+"."+s); found {
+				return label
+			}
+			return s
+		}
+		return fmt.Sprint(value)
+	})
+	return capitalize(message)
+}
+
+// capitalize は先頭の文字を大文字にする（英語のラベルが文頭に来た場合のため）
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError || unicode.IsUpper(r) {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
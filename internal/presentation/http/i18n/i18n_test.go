@@ -0,0 +1,49 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogsHaveSameKeys(t *testing.T) {
+	for key := range messagesJa {
+		_, ok := messagesEn[key]
+		assert.True(t, ok, "英語のカタログに %s がありません", key)
+	}
+	for key := range messagesEn {
+		_, ok := messagesJa[key]
+		assert.True(t, ok, "日本語のカタログに %s がありません", key)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           Lang
+	}{
+		{"", Japanese},
+		{"ja-JP,ja;q=0.9", Japanese},
+		{"en-US,en;q=0.9,ja;q=0.8", English},
+		{"fr-FR,en;q=0.5", English},
+		{"fr-FR", Japanese},
+		{"invalid;;", Japanese},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Negotiate(tt.acceptLanguage), tt.acceptLanguage)
+	}
+}
+
+func TestMessage(t *testing.T) {
+	params := map[string]interface{}{"field": "choices", "min": 2}
+	assert.Equal(t, "選択肢は2個以上指定してください", Message(Japanese, ValidationKey("min_items"), params))
+	assert.Equal(t, "Choices must contain at least 2 items", Message(English, ValidationKey("min_items"), params))
+
+	// パラメータがなければ既定のラベル、ラベルがなければ値をそのまま使う
+	assert.Equal(t, "リソースが見つかりません", Message(Japanese, ErrorKey("NOT_FOUND"), nil))
+	assert.Equal(t, "Nickname is required", Message(English, ValidationKey("required"), map[string]interface{}{"field": "nickname"}))
+
+	// カタログにないキーはそのまま返す
+	assert.Equal(t, "error.UNKNOWN", Message(English, ErrorKey("UNKNOWN"), nil))
+}
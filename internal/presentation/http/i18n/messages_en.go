@@ -0,0 +1,97 @@
+package i18n
+
+// messages_en.goは英語のメッセージカタログを定義
+// 項目名は文中にも入るため小文字で書き、文頭に来た場合は Message が大文字にする
+
+var messagesEn = map[string]string{
+	// エラーコード
+	"error.VALIDATION_ERROR":          "Invalid input",
+	"error.BAD_REQUEST":               "Bad request",
+	"error.INVALID_JSON":              "Invalid JSON format",
+	"error.INVALID_ID":                "Invalid ID format",
+	"error.UNAUTHORIZED":              "Authentication required",
+	"error.INVALID_TOKEN":             "Invalid or expired token",
+	"error.AUTH_FAILED":               "Invalid credentials or email not confirmed",
+	"error.FORBIDDEN":                 "You do not have permission to perform this action",
+	"error.NOT_FOUND":                 "{resource} not found",
+	"error.METHOD_NOT_ALLOWED":        "Method not allowed",
+	"error.PAYLOAD_TOO_LARGE":         "Request body is too large (up to {limit} bytes)",
+	"error.UNSUPPORTED_MEDIA_TYPE":    "Content-Type must be application/json",
+	"error.USER_EXISTS":               "A user with this email already exists",
+	"error.GENRE_EXISTS":              "Genre already exists",
+	"error.CHOICE_EXISTS":             "Choice already exists",
+	"error.UNSUPPORTED_QUESTION_TYPE": "Unsupported question type",
+	"error.INVALID_ANSWER_KEY":        "The question has no correct answer configured",
+	"error.ACCOUNT_LOCKED":            "Too many failed login attempts. Please try again in {retry_after} seconds",
+	"error.RATE_LIMITED":              "Too many requests. Please try again in {retry_after} seconds",
+	"error.INVALID_IDEMPOTENCY_KEY":   "Idempotency-Key must be a single value of 1 to {max} characters",
+	"error.IDEMPOTENCY_KEY_IN_USE":    "A request with the same Idempotency-Key is still being processed. Please try again in {retry_after} seconds",
+	"error.IDEMPOTENCY_KEY_MISMATCH":  "This Idempotency-Key has already been used for a different request",
+	"error.PRECONDITION_FAILED":       "This resource has been changed by someone else (current version: {current_version}). Please reload and try again",
+	"error.PRECONDITION_REQUIRED":     "An If-Match header with the ETag of the resource is required",
+	"error.INTERNAL_ERROR":            "Internal server error",
+
+	// バリデーションエラー
+	"validation.required":       "{field} is required",
+	"validation.min_length":     "{field} must be at least {min} characters",
+	"validation.max_length":     "{field} must be at most {max} characters",
+	"validation.min_items":      "{field} must contain at least {min} items",
+	"validation.max_items":      "{field} must contain at most {max} items",
+	"validation.invalid_format": "{field} has an invalid format",
+	"validation.invalid_value":  "{field} has an invalid value",
+	"validation.non_negative":   "{field} must not be negative",
+	"validation.duplicate":      "{field} contains duplicates",
+	"validation.unknown_id":     "{field} contains IDs that do not belong to this question",
+	"validation.incomplete":     "{field} must include every item",
+	"validation.exactly_one":    "Exactly one {field} must be specified",
+	"validation.no_changes":     "Nothing to update",
+	"validation.not_applicable": "{field} cannot be set for this question type",
+	"validation.no_correct":     "At least one of the {field} must be correct",
+	"validation.invalid_type":   "{field} must be {expected}",
+	"validation.unknown_field":  "{field} is not a known field",
+
+	// 項目名
+	"field.default":           "field",
+	"field.id":                "ID",
+	"field.user_id":           "user ID",
+	"field.email":             "email",
+	"field.password":          "password",
+	"field.username":          "username",
+	"field.token":             "token",
+	"field.refresh_token":     "refresh token",
+	"field.name":              "name",
+	"field.genre_id":          "genre ID",
+	"field.title":             "title",
+	"field.type":              "question type",
+	"field.tags":              "tags",
+	"field.fields":            "fields",
+	"field.correct_boolean":   "correct answer (true/false)",
+	"field.accepted_answers":  "accepted answers",
+	"field.numeric_answer":    "numeric answer",
+	"field.numeric_tolerance": "numeric tolerance",
+	"field.question_id":       "question ID",
+	"field.answer":            "answer",
+	"field.boolean_answer":    "answer (true/false)",
+	"field.text_answer":       "answer",
+	"field.choices":           "choices",
+	"field.choice_text":       "choice text",
+	"field.choice_id":         "choice ID",
+	"field.choice_ids":        "choice IDs",
+	"field.limit":             "limit",
+	"field.body":              "request body",
+
+	// JSONの型
+	"expected.string":  "a string",
+	"expected.integer": "an integer",
+	"expected.number":  "a number",
+	"expected.boolean": "a boolean (true/false)",
+	"expected.array":   "an array",
+	"expected.object":  "an object",
+
+	// リソース名
+	"resource.default":  "resource",
+	"resource.question": "question",
+	"resource.genre":    "genre",
+	"resource.profile":  "profile",
+	"resource.choice":   "choice",
+}
@@ -0,0 +1,96 @@
+package i18n
+
+// messages_ja.goは日本語のメッセージカタログを定義
+
+var messagesJa = map[string]string{
+	// エラーコード
+	"error.VALIDATION_ERROR":          "入力内容が正しくありません",
+	"error.BAD_REQUEST":               "リクエストが正しくありません",
+	"error.INVALID_JSON":              "JSONの形式が正しくありません",
+	"error.INVALID_ID":                "IDの形式が正しくありません",
+	"error.UNAUTHORIZED":              "認証が必要です",
+	"error.INVALID_TOKEN":             "無効なトークンです",
+	"error.AUTH_FAILED":               "メールアドレスまたはパスワードが正しくないか、メールアドレスの確認が完了していません",
+	"error.FORBIDDEN":                 "この操作を行う権限がありません",
+	"error.NOT_FOUND":                 "{resource}が見つかりません",
+	"error.METHOD_NOT_ALLOWED":        "許可されていないメソッドです",
+	"error.PAYLOAD_TOO_LARGE":         "リクエストボディが大きすぎます（{limit}バイトまで）",
+	"error.UNSUPPORTED_MEDIA_TYPE":    "Content-Type には application/json を指定してください",
+	"error.USER_EXISTS":               "このメールアドレスのユーザーは既に存在します",
+	"error.GENRE_EXISTS":              "ジャンルが既に存在します",
+	"error.CHOICE_EXISTS":             "選択肢が既に存在します",
+	"error.UNSUPPORTED_QUESTION_TYPE": "対応していない問題形式です",
+	"error.INVALID_ANSWER_KEY":        "問題の正解が設定されていません",
+	"error.ACCOUNT_LOCKED":            "ログインの失敗が続いたため、一時的にログインできません。{retry_after}秒後にもう一度お試しください",
+	"error.RATE_LIMITED":              "リクエストが多すぎます。{retry_after}秒後にもう一度お試しください",
+	"error.INVALID_IDEMPOTENCY_KEY":   "Idempotency-Key は1〜{max}文字で1つだけ指定してください",
+	"error.IDEMPOTENCY_KEY_IN_USE":    "同じ Idempotency-Key のリクエストを処理中です。{retry_after}秒後にもう一度お試しください",
+	"error.IDEMPOTENCY_KEY_MISMATCH":  "この Idempotency-Key は別の内容のリクエストで使われています",
+	"error.PRECONDITION_FAILED":       "他の変更が先に保存されています（現在の版: {current_version}）。再読み込みしてからもう一度お試しください",
+	"error.PRECONDITION_REQUIRED":     "リソースの ETag を指定した If-Match ヘッダーが必要です",
+	"error.INTERNAL_ERROR":            "サーバー内部でエラーが発生しました",
+
+	// バリデーションエラー
+	"validation.required":       "{field}は必須です",
+	"validation.min_length":     "{field}は{min}文字以上で入力してください",
+	"validation.max_length":     "{field}は{max}文字以内で入力してください",
+	"validation.min_items":      "{field}は{min}個以上指定してください",
+	"validation.max_items":      "{field}は{max}個まで指定できます",
+	"validation.invalid_format": "{field}の形式が正しくありません",
+	"validation.invalid_value":  "{field}の値が正しくありません",
+	"validation.non_negative":   "{field}は0以上で指定してください",
+	"validation.duplicate":      "{field}が重複しています",
+	"validation.unknown_id":     "{field}にこの問題のものではないIDが含まれています",
+	"validation.incomplete":     "{field}には全ての項目を指定してください",
+	"validation.exactly_one":    "{field}は1つだけ指定してください",
+	"validation.no_changes":     "更新する内容を入力してください",
+	"validation.not_applicable": "この問題形式では{field}を設定できません",
+	"validation.no_correct":     "正解の{field}を1つ以上指定してください",
+	"validation.invalid_type":   "{field}は{expected}で指定してください",
+	"validation.unknown_field":  "{field}という項目はありません",
+
+	// 項目名
+	"field.default":           "入力項目",
+	"field.id":                "ID",
+	"field.user_id":           "ユーザーID",
+	"field.email":             "メールアドレス",
+	"field.password":          "パスワード",
+	"field.username":          "ユーザー名",
+	"field.token":             "トークン",
+	"field.refresh_token":     "リフレッシュトークン",
+	"field.name":              "名前",
+	"field.genre_id":          "ジャンルID",
+	"field.title":             "問題タイトル",
+	"field.type":              "問題形式",
+	"field.tags":              "タグ",
+	"field.fields":            "更新内容",
+	"field.correct_boolean":   "正解（true/false）",
+	"field.accepted_answers":  "正解とする解答",
+	"field.numeric_answer":    "数値の解答",
+	"field.numeric_tolerance": "許容誤差",
+	"field.question_id":       "問題ID",
+	"field.answer":            "解答",
+	"field.boolean_answer":    "解答（true/false）",
+	"field.text_answer":       "解答",
+	"field.choices":           "選択肢",
+	"field.choice_text":       "選択肢の本文",
+	"field.choice_id":         "選択肢ID",
+	"field.choice_ids":        "選択肢ID",
+	"field.limit":             "取得件数",
+	"field.body":              "リクエストボディ",
+
+	// JSONの型
+	"expected.string":  "文字列",
+	"expected.integer": "整数",
+	"expected.number":  "数値",
+	"expected.boolean": "真偽値（true/false）",
+	"expected.array":   "配列",
+	"expected.object":  "オブジェクト",
+
+	// リソース名
+	"resource.default":  "リソース",
+	"resource.question": "問題",
+	"resource.genre":    "ジャンル",
+	"resource.profile":  "プロフィール",
+	"resource.choice":   "選択肢",
+}
@@ -0,0 +1,96 @@
+package jsonbody
+
+// jsonbody.goはリクエストボディのJSONを厳密に読み込む
+// ハンドラーは json.NewDecoder の代わりに Decode を使い、エラーはそのまま apierror.Write に渡す
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/presentation/http/apierror"
+)
+
+// bodyField は特定の項目ではなくボディ全体の型が正しくない場合の項目名
+const bodyField = "body"
+
+// Decode はリクエストボディのJSONを dst に読み込む
+// 定義されていない項目や、JSONの値の後に続くデータがあればエラーにする
+// エラーは次のとおり apierror.Write で返せる形にする
+//   - 項目の型が正しくない: ValidationError（invalid_type、expected に期待する型）
+//   - 定義されていない項目: ValidationError（unknown_field）
+//   - JSONとして読めない・空・後ろにデータが続く: DomainError（INVALID_JSON）
+//   - ボディが大きすぎる: DomainError（PAYLOAD_TOO_LARGE、limit に上限のバイト数）
+func Decode(r *http.Request, dst interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+
+	// 1つ目の値の後は空白だけが許される
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return decodeError(err)
+		}
+		return shared.NewDomainError(apierror.CodeInvalidJSON)
+	}
+	return nil
+}
+
+// decodeError は json.Decoder のエラーをレスポンスにできるエラーに変換する
+func decodeError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	var typeErr *json.UnmarshalTypeError
+	var invalidErr *json.InvalidUnmarshalError
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return shared.NewDomainError(apierror.CodePayloadTooLarge).With("limit", maxBytesErr.Limit)
+	case errors.As(err, &typeErr):
+		field := typeErr.Field
+		if field == "" {
+			field = bodyField
+		}
+		return shared.NewValidationError(field, shared.ValidationInvalidType).With("expected", jsonType(typeErr.Type))
+	case errors.As(err, &invalidErr):
+		// dst にポインター以外を渡した実装の誤り
+		return err
+	}
+
+	// 定義されていない項目のエラーには専用の型がないため、メッセージから項目名を取り出す
+	if name, ok := strings.CutPrefix(err.Error(), `json: unknown field "`); ok {
+		return shared.NewValidationError(strings.TrimSuffix(name, `"`), shared.ValidationUnknownField)
+	}
+
+	// 構文の誤り、空のボディ、途中で終わったボディ、独自の UnmarshalJSON のエラー
+	return shared.NewDomainError(apierror.CodeInvalidJSON)
+}
+
+// jsonType はGoの型に対応するJSONの型の名前を返す
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
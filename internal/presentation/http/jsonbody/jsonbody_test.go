@@ -0,0 +1,96 @@
+package jsonbody
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"Shittaka_back/internal/domain/shared"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRequest struct {
+	Title     string   `json:"title"`
+	GenreID   int64    `json:"genre_id"`
+	Tags      []string `json:"tags"`
+	IsCorrect *bool    `json:"is_correct"`
+	Choice    struct {
+		Text string `json:"text"`
+	} `json:"choice"`
+}
+
+func decode(body string, maxBytes int64) (testRequest, error) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	if maxBytes > 0 {
+		r.Body = http.MaxBytesReader(httptest.NewRecorder(), r.Body, maxBytes)
+	}
+	var req testRequest
+	err := Decode(r, &req)
+	return req, err
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("正しいJSON", func(t *testing.T) {
+		req, err := decode(`{"title":"問題","genre_id":1,"tags":["a"]}`+"\n", 0)
+		require.NoError(t, err)
+		assert.Equal(t, "問題", req.Title)
+		assert.Equal(t, int64(1), req.GenreID)
+	})
+
+	validationTests := []struct {
+		name     string
+		body     string
+		field    string
+		code     string
+		expected string
+	}{
+		{"文字列の項目に数値", `{"title":1}`, "title", shared.ValidationInvalidType, "string"},
+		{"整数の項目に小数", `{"genre_id":1.5}`, "genre_id", shared.ValidationInvalidType, "integer"},
+		{"配列の項目に文字列", `{"tags":"a"}`, "tags", shared.ValidationInvalidType, "array"},
+		{"ポインターの真偽値の項目に文字列", `{"is_correct":"yes"}`, "is_correct", shared.ValidationInvalidType, "boolean"},
+		{"入れ子の項目", `{"choice":{"text":false}}`, "choice.text", shared.ValidationInvalidType, "string"},
+		{"ボディ全体が配列", `[]`, "body", shared.ValidationInvalidType, "object"},
+		{"定義されていない項目", `{"title":"a","is_admin":true}`, "is_admin", shared.ValidationUnknownField, ""},
+	}
+	for _, tt := range validationTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decode(tt.body, 0)
+
+			var validationErr shared.ValidationError
+			require.True(t, errors.As(err, &validationErr), "err = %v", err)
+			assert.Equal(t, tt.field, validationErr.Field)
+			assert.Equal(t, tt.code, validationErr.Code)
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, validationErr.Params["expected"])
+			}
+		})
+	}
+
+	domainTests := []struct {
+		name     string
+		body     string
+		maxBytes int64
+		code     string
+	}{
+		{"空のボディ", ``, 0, "INVALID_JSON"},
+		{"構文の誤り", `{"title":}`, 0, "INVALID_JSON"},
+		{"途中で終わっている", `{"title":"a"`, 0, "INVALID_JSON"},
+		{"後ろに値が続く", `{"title":"a"}{"title":"b"}`, 0, "INVALID_JSON"},
+		{"後ろに余分な文字が続く", `{"title":"a"} x`, 0, "INVALID_JSON"},
+		{"上限を超える", `{"title":"` + strings.Repeat("a", 100) + `"}`, 32, "PAYLOAD_TOO_LARGE"},
+		{"値の後ろで上限を超える", `{"title":"a"}` + strings.Repeat(" ", 100), 32, "PAYLOAD_TOO_LARGE"},
+	}
+	for _, tt := range domainTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decode(tt.body, tt.maxBytes)
+
+			var domainErr shared.DomainError
+			require.True(t, errors.As(err, &domainErr), "err = %v", err)
+			assert.Equal(t, tt.code, domainErr.Code)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+// body.goはリクエストボディの大きさと形式を検査するミドルウェアを定義
+
+import (
+	"mime"
+	"net/http"
+
+	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/presentation/http/apierror"
+)
+
+// RequestBody はリクエストボディを検査するミドルウェアを返す
+// ボディのあるリクエストには Content-Type: application/json を求め、違えば415（UNSUPPORTED_MEDIA_TYPE）を返す
+// maxBytes を超えるボディは413（PAYLOAD_TOO_LARGE）とする（0 以下なら大きさを制限しない）
+// Content-Length のないボディは読み込みながら検査し、超えた時点で jsonbody.Decode がエラーを返す
+func RequestBody(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// ボディのないリクエスト（ログアウトなど）は Content-Type を問わない
+			if r.ContentLength == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+				apierror.Respond(w, r, apierror.CodeUnsupportedMediaType)
+				return
+			}
+
+			if maxBytes > 0 {
+				if r.ContentLength > maxBytes {
+					apierror.Write(w, r, shared.NewDomainError(apierror.CodePayloadTooLarge).With("limit", maxBytes))
+					return
+				}
+				r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestBody(t *testing.T) {
+	var received string
+	handler := RequestBody(16)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		received = string(body)
+	}))
+
+	serve := func(body, contentType string, contentLength int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/genres", strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if contentLength != 0 {
+			req.ContentLength = contentLength
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("JSONのボディを通す", func(t *testing.T) {
+		rec := serve(`{"name":"a"}`, "application/json; charset=utf-8", 0)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `{"name":"a"}`, received)
+	})
+
+	t.Run("ボディがなければContent-Typeを問わない", func(t *testing.T) {
+		rec := serve("", "", 0)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("JSON以外のContent-Typeは415", func(t *testing.T) {
+		rec := serve(`name=a`, "application/x-www-form-urlencoded", 0)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"UNSUPPORTED_MEDIA_TYPE"`)
+	})
+
+	t.Run("Content-Typeがなければ415", func(t *testing.T) {
+		rec := serve(`{"name":"a"}`, "", 0)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	})
+
+	t.Run("Content-Lengthが上限を超えれば読まずに413", func(t *testing.T) {
+		rec := serve(`{"name":"aaaaaaaaaaaaaaaa"}`, "application/json", 0)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"PAYLOAD_TOO_LARGE"`)
+	})
+
+	t.Run("長さが不明なボディは読み込みながら制限する", func(t *testing.T) {
+		rec := serve(`{"name":"aaaaaaaaaaaaaaaa"}`, "application/json", -1)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+}
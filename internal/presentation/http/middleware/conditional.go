@@ -0,0 +1,180 @@
+package middleware
+
+// conditional.goはGETのレスポンスに ETag / Last-Modified / Cache-Control を付与し、
+// 条件付きリクエスト（If-None-Match / If-Modified-Since）に304を返すミドルウェアを定義
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"Shittaka_back/internal/presentation/http/etag"
+)
+
+// CachePolicy はレスポンスをキャッシュさせる方針
+type CachePolicy struct {
+	// Private が true ならユーザーごとのレスポンスとして、共有キャッシュ（CDN など）には保存させない
+	Private bool
+
+	// MaxAge は再検証せずにキャッシュを使える時間（0 なら毎回 ETag で再検証させる）
+	MaxAge time.Duration
+}
+
+// cacheControl は Cache-Control ヘッダーの値を返す
+func (p CachePolicy) cacheControl() string {
+	scope := "public"
+	if p.Private {
+		scope = "private"
+	}
+	if p.MaxAge <= 0 {
+		return scope + ", no-cache"
+	}
+	return scope + ", max-age=" + strconv.Itoa(int(p.MaxAge.Seconds()))
+}
+
+// ConditionalGET は条件付きGETに対応するミドルウェアを返す
+// 200のレスポンスに ETag（ハンドラーが付けていなければ内容から作る）と Cache-Control を付与し、
+// If-None-Match が一致する場合、または If-None-Match がなく If-Modified-Since 以降に変わっていない場合は304を返す
+// 内容の比較のためレスポンスをバッファするので、大きなレスポンスを返すルートには使わない
+func ConditionalGET(policy CachePolicy) func(http.Handler) http.Handler {
+	tracker := newLastModifiedTracker()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			buffered := &bufferedResponse{ResponseWriter: w}
+			next.ServeHTTP(buffered, r)
+
+			// エラーはキャッシュさせない
+			if buffered.status != http.StatusOK {
+				buffered.flush()
+				return
+			}
+
+			tag := w.Header().Get("ETag")
+			if tag == "" {
+				tag = etag.FromContent(buffered.body.Bytes())
+				w.Header().Set("ETag", tag)
+			}
+			w.Header().Set("Cache-Control", policy.cacheControl())
+
+			// ユーザーごとのレスポンスは内容が利用者によって変わるため、ETag でのみ比較する
+			var modified lastModifiedEntry
+			if policy.Private {
+				w.Header().Add("Vary", "Authorization")
+			} else {
+				modified = tracker.observe(r.URL.RequestURI(), tag)
+				w.Header().Set("Last-Modified", modified.at.Format(http.TimeFormat))
+			}
+
+			if notModified(r, tag, modified) {
+				w.Header().Del("Content-Type")
+				w.Header().Del("Content-Length")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			buffered.flush()
+		})
+	}
+}
+
+// notModified はクライアントのキャッシュが最新かどうかを返す
+// If-None-Match がある場合は If-Modified-Since より優先する（秒単位の日時より正確なため）
+func notModified(r *http.Request, tag string, modified lastModifiedEntry) bool {
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		return etag.MatchNone(header, tag)
+	}
+	if modified.at.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	// 同じ秒のうちに内容が変わった場合、その秒の日時だけではどちらの内容か区別できない
+	return modified.at.Before(since) || (modified.at.Equal(since) && !modified.ambiguous)
+}
+
+// bufferedResponse はハンドラーが書き込んだステータスとボディを送信せずに保持する
+type bufferedResponse struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// flush は保持しているレスポンスを送信する
+func (b *bufferedResponse) flush() {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	b.ResponseWriter.WriteHeader(b.status)
+	b.ResponseWriter.Write(b.body.Bytes())
+}
+
+// maxTrackedResources は Last-Modified を記録しておくURLの数の上限
+const maxTrackedResources = 1024
+
+// lastModifiedEntry はURLごとの現在の ETag と、その内容を初めて返した日時（秒単位）
+type lastModifiedEntry struct {
+	tag string
+	at  time.Time
+
+	// ambiguous は前の内容と同じ秒に変わったかどうか（If-Modified-Since では区別できない）
+	ambiguous bool
+}
+
+// lastModifiedTracker は内容（ETag）が変わった日時を Last-Modified として記録する
+// ジャンルや選択肢は更新日時を持たないため、このプロセスがその内容を初めて返した日時で代用する
+// 実際に変わった日時以降になるため、変わった後に古い内容を最新とみなすことはない
+type lastModifiedTracker struct {
+	mu      sync.Mutex
+	entries map[string]lastModifiedEntry
+	now     func() time.Time
+}
+
+func newLastModifiedTracker() *lastModifiedTracker {
+	return &lastModifiedTracker{
+		entries: make(map[string]lastModifiedEntry),
+		now:     time.Now,
+	}
+}
+
+// observe はURLの現在の ETag を記録し、その内容を初めて返した日時を返す
+func (t *lastModifiedTracker) observe(key, tag string) lastModifiedEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous, ok := t.entries[key]
+	if ok && previous.tag == tag {
+		return previous
+	}
+
+	// クエリの組み合わせでメモリを使い続けないよう、上限に達したら記録をやり直す
+	if len(t.entries) >= maxTrackedResources {
+		clear(t.entries)
+	}
+
+	at := t.now().UTC().Truncate(time.Second)
+	entry := lastModifiedEntry{tag: tag, at: at, ambiguous: ok && previous.at.Equal(at)}
+	t.entries[key] = entry
+	return entry
+}
@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionalGET(t *testing.T) {
+	body := `[{"id":1,"name":"数学"}]`
+	status := http.StatusOK
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	})
+	handler := ConditionalGET(CachePolicy{})(next)
+
+	serve := func(handler http.Handler, method string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/genres", nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := serve(handler, http.MethodGet, nil)
+	require.Equal(t, http.StatusOK, first.Code)
+	tag := first.Header().Get("ETag")
+	lastModified := first.Header().Get("Last-Modified")
+
+	t.Run("ETag と Last-Modified と Cache-Control を付与する", func(t *testing.T) {
+		assert.Equal(t, body, first.Body.String())
+		assert.Regexp(t, `^"[0-9a-f]+"$`, tag)
+		assert.NotEmpty(t, lastModified)
+		assert.Equal(t, "public, no-cache", first.Header().Get("Cache-Control"))
+	})
+
+	t.Run("If-None-Match が一致すれば304", func(t *testing.T) {
+		rec := serve(handler, http.MethodGet, map[string]string{"If-None-Match": tag})
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.String())
+		assert.Equal(t, tag, rec.Header().Get("ETag"))
+		assert.Empty(t, rec.Header().Get("Content-Type"))
+	})
+
+	t.Run("弱い ETag でも一致すれば304", func(t *testing.T) {
+		rec := serve(handler, http.MethodGet, map[string]string{"If-None-Match": "W/" + tag})
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+	})
+
+	t.Run("If-Modified-Since 以降に変わっていなければ304", func(t *testing.T) {
+		rec := serve(handler, http.MethodGet, map[string]string{"If-Modified-Since": lastModified})
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+	})
+
+	t.Run("If-None-Match は If-Modified-Since より優先する", func(t *testing.T) {
+		rec := serve(handler, http.MethodGet, map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": lastModified})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, body, rec.Body.String())
+	})
+
+	t.Run("内容が変われば200", func(t *testing.T) {
+		body = `[{"id":1,"name":"数学"},{"id":2,"name":"英語"}]`
+		t.Cleanup(func() { body = `[{"id":1,"name":"数学"}]` })
+
+		rec := serve(handler, http.MethodGet, map[string]string{"If-None-Match": tag})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEqual(t, tag, rec.Header().Get("ETag"))
+	})
+
+	t.Run("ハンドラーが付けた ETag を使う", func(t *testing.T) {
+		tagged := ConditionalGET(CachePolicy{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `W/"v1"`)
+			w.Write([]byte(body))
+		}))
+
+		rec := serve(tagged, http.MethodGet, map[string]string{"If-None-Match": `"v1"`})
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Equal(t, `W/"v1"`, rec.Header().Get("ETag"))
+	})
+
+	t.Run("ユーザーごとのレスポンスは共有キャッシュに保存させない", func(t *testing.T) {
+		private := ConditionalGET(CachePolicy{Private: true})(next)
+
+		rec := serve(private, http.MethodGet, nil)
+		assert.Equal(t, "private, no-cache", rec.Header().Get("Cache-Control"))
+		assert.Equal(t, "Authorization", rec.Header().Get("Vary"))
+		assert.Empty(t, rec.Header().Get("Last-Modified"))
+
+		rec = serve(private, http.MethodGet, map[string]string{"If-Modified-Since": lastModified})
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("max-age を指定できる", func(t *testing.T) {
+		rec := serve(ConditionalGET(CachePolicy{MaxAge: time.Minute})(next), http.MethodGet, nil)
+
+		assert.Equal(t, "public, max-age=60", rec.Header().Get("Cache-Control"))
+	})
+
+	t.Run("エラーはキャッシュさせない", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		t.Cleanup(func() { status = http.StatusOK })
+
+		rec := serve(handler, http.MethodGet, map[string]string{"If-None-Match": "*"})
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Empty(t, rec.Header().Get("ETag"))
+		assert.Empty(t, rec.Header().Get("Cache-Control"))
+		assert.Equal(t, body, rec.Body.String())
+	})
+}
+
+func TestLastModifiedTracker(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 200*int(time.Millisecond), time.UTC)
+	tracker := newLastModifiedTracker()
+	tracker.now = func() time.Time { return now }
+
+	first := tracker.observe("/api/genres", `"a"`)
+	assert.Equal(t, now.Truncate(time.Second), first.at)
+	assert.False(t, first.ambiguous)
+
+	t.Run("内容が同じなら最初に返した日時のまま", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		assert.Equal(t, first, tracker.observe("/api/genres", `"a"`))
+	})
+
+	t.Run("内容が変われば変わった日時", func(t *testing.T) {
+		changed := tracker.observe("/api/genres", `"b"`)
+		assert.Equal(t, now.Truncate(time.Second), changed.at)
+		assert.False(t, changed.ambiguous)
+	})
+
+	t.Run("同じ秒のうちに変われば区別できない", func(t *testing.T) {
+		now = now.Add(100 * time.Millisecond)
+		changed := tracker.observe("/api/genres", `"c"`)
+		assert.True(t, changed.ambiguous)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/genres", nil)
+		req.Header.Set("If-Modified-Since", changed.at.Format(http.TimeFormat))
+		assert.False(t, notModified(req, `"c"`, changed))
+	})
+
+	t.Run("URLごとに記録する", func(t *testing.T) {
+		other := tracker.observe("/api/genres?page=2", `"a"`)
+		assert.False(t, other.ambiguous)
+	})
+}
@@ -7,20 +7,126 @@ package middleware
 
 // オリジンとは、WebページのURLのこと
 
-import "net/http"
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSOptions はCORSの設定
+type CORSOptions struct {
+	// AllowedOrigins は許可するオリジン（空ならクロスオリジンのリクエストを許可しない）
+	// "*" は全てのオリジン、"https://*.vercel.app" のようにホストの先頭を * にするとそのサブドメインを許可する
+	AllowedOrigins []string
+
+	// AllowCredentials が true ならCookieや Authorization ヘッダー付きのリクエストを許可する
+	// 全てのオリジンを許可している場合も、許可したオリジンをそのまま返す（ブラウザは * と資格情報の組み合わせを拒否するため）
+	AllowCredentials bool
+
+	// MaxAge はプリフライトの結果をブラウザがキャッシュする時間（0 なら Access-Control-Max-Age を付与しない）
+	MaxAge time.Duration
+}
+
+const (
+	corsAllowMethods  = "GET, POST, PUT, DELETE, OPTIONS"
+	corsAllowHeaders  = "Content-Type, Authorization, Accept-Language, X-Request-ID, Idempotency-Key, If-Match, If-None-Match, If-Modified-Since"
+	corsExposeHeaders = "X-Request-ID, API-Version, Deprecation, Link, Retry-After, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, Idempotent-Replayed, ETag"
+)
 
 // CORS はCORS対応のミドルウェアを返す
-func CORS(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
+// 許可していないオリジンからのリクエストにはCORSのヘッダーを付与しない（ブラウザがレスポンスの読み取りを拒否する）
+func CORS(options CORSOptions) func(http.Handler) http.Handler {
+	allowAny, patterns := parseOrigins(options.AllowedOrigins)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			allowed := origin != "" && (allowAny || matchOrigin(patterns, origin))
+
+			// 許可するかどうかがオリジンによって変わる場合は、キャッシュがオリジンごとに分かれるようにする
+			if !allowAny || options.AllowCredentials {
+				w.Header().Add("Vary", "Origin")
+			}
+
+			if allowed {
+				if allowAny && !options.AllowCredentials {
+					w.Header().Set("Access-Control-Allow-Origin", "*")
+				} else {
+					w.Header().Set("Access-Control-Allow-Origin", origin)
+				}
+				if options.AllowCredentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+				w.Header().Set("Access-Control-Expose-Headers", corsExposeHeaders)
+			}
+
+			if r.Method == http.MethodOptions {
+				if allowed {
+					w.Header().Set("Access-Control-Allow-Methods", corsAllowMethods)
+					w.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders)
+					if options.MaxAge > 0 {
+						w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(options.MaxAge.Seconds())))
+					}
+				}
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// originPattern は許可するオリジンのパターン
+// ワイルドカードのない場合は suffix が空で、prefix と完全に一致するオリジンだけを許可する
+type originPattern struct {
+	prefix   string
+	suffix   string
+	wildcard bool
+}
+
+// parseOrigins は許可するオリジンの設定を解釈する
+func parseOrigins(origins []string) (allowAny bool, patterns []originPattern) {
+	for _, origin := range origins {
+		origin = normalizeOrigin(origin)
+		if origin == "*" {
+			allowAny = true
+			continue
+		}
+		// "https://*.vercel.app" は "https://" と ".vercel.app" の間に1文字以上あるオリジンに一致する
+		if prefix, suffix, ok := strings.Cut(origin, "*"); ok {
+			patterns = append(patterns, originPattern{prefix: prefix, suffix: suffix, wildcard: true})
+			continue
 		}
+		patterns = append(patterns, originPattern{prefix: origin})
+	}
+	return allowAny, patterns
+}
 
-		next.ServeHTTP(w, r)
+// matchOrigin はオリジンがいずれかのパターンに一致するかを返す
+func matchOrigin(patterns []originPattern, origin string) bool {
+	origin = normalizeOrigin(origin)
+	for _, p := range patterns {
+		if !p.wildcard {
+			if origin == p.prefix {
+				return true
+			}
+			continue
+		}
+		if len(origin) > len(p.prefix)+len(p.suffix) &&
+			strings.HasPrefix(origin, p.prefix) && strings.HasSuffix(origin, p.suffix) {
+			// サブドメインの部分にパスやポートが紛れ込んでいないことを確認する
+			sub := origin[len(p.prefix) : len(origin)-len(p.suffix)]
+			if !strings.ContainsAny(sub, "/:@") {
+				return true
+			}
+		}
 	}
+	return false
+}
+
+// normalizeOrigin は比較のためにオリジンを小文字にし、末尾の / を取り除く
+func normalizeOrigin(origin string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(origin)), "/")
 }
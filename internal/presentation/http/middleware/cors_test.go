@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// serveCORS はオリジンを付けたリクエストをCORSミドルウェアに通す
+func serveCORS(options CORSOptions, method, origin string) *httptest.ResponseRecorder {
+	handler := CORS(options)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	req := httptest.NewRequest(method, "/api/auth/login", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCORS(t *testing.T) {
+	allowlist := CORSOptions{
+		AllowedOrigins:   []string{"https://shittaka.app", "https://*.vercel.app"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+
+	t.Run("許可リストのオリジンはそのまま返す", func(t *testing.T) {
+		for _, origin := range []string{"https://shittaka.app", "https://shittaka-git-feature.vercel.app", "https://a.b.vercel.app"} {
+			rec := serveCORS(allowlist, http.MethodGet, origin)
+
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+			assert.Equal(t, origin, rec.Header().Get("Access-Control-Allow-Origin"), origin)
+			assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+			assert.Equal(t, "Origin", rec.Header().Get("Vary"))
+		}
+	})
+
+	t.Run("許可していないオリジンにはヘッダーを付与しない", func(t *testing.T) {
+		for _, origin := range []string{"https://evil.example", "http://shittaka.app", "https://vercel.app", "https://evil.example/.vercel.app", "https://shittaka.app.evil.example"} {
+			rec := serveCORS(allowlist, http.MethodGet, origin)
+
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+			assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"), origin)
+		}
+	})
+
+	t.Run("プリフライトにはメソッドとキャッシュ時間を返す", func(t *testing.T) {
+		rec := serveCORS(allowlist, http.MethodOptions, "https://shittaka.app")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "https://shittaka.app", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, rec.Header().Get("Access-Control-Allow-Methods"), "DELETE")
+		assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("許可するオリジンが空ならどのオリジンも許可しない", func(t *testing.T) {
+		for _, method := range []string{http.MethodGet, http.MethodOptions} {
+			rec := serveCORS(CORSOptions{}, method, "https://example.com")
+
+			assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"), method)
+			assert.Empty(t, rec.Header().Get("Access-Control-Allow-Methods"), method)
+		}
+	})
+
+	t.Run("全て許可する場合は * を返す", func(t *testing.T) {
+		rec := serveCORS(CORSOptions{AllowedOrigins: []string{"*"}}, http.MethodGet, "https://example.com")
+
+		assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Empty(t, rec.Header().Get("Vary"))
+	})
+}
@@ -0,0 +1,45 @@
+package middleware
+
+// deprecation.goは非推奨になった旧パス向けのミドルウェアを定義
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Deprecated は非推奨の旧パスであることをクライアントに知らせるミドルウェアを返す
+// 警告ログを出力し、Deprecation ヘッダーと移行先を示す Link ヘッダーを付与する
+// successor は移行先のパターン（/api/v1/questions/{id}/choices など）で、ワイルドカードはリクエストのパスの値で埋める
+// リクエストのパスから埋められないワイルドカードがある場合は、辿れないリンクになるため Link ヘッダーを付与しない
+func Deprecated(successor string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		link, ok := successorPath(successor, r)
+		slog.WarnContext(r.Context(), "deprecated endpoint called", "method", r.Method, "path", r.URL.Path, "successor", successor)
+
+		w.Header().Set("Deprecation", "true")
+		if ok {
+			w.Header().Set("Link", "<"+link+">; rel=\"successor-version\"")
+		}
+
+		next.ServeHTTP(w, r)
+	}
+}
+
+// successorPath は移行先のパターンのワイルドカードをリクエストのパスの値で埋めたパスを返す
+// 埋められないワイルドカードがあれば false を返す
+func successorPath(pattern string, r *http.Request) (string, bool) {
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+		value := r.PathValue(strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}"))
+		if value == "" {
+			return "", false
+		}
+		segments[i] = url.PathEscape(value)
+	}
+	return strings.Join(segments, "/"), true
+}
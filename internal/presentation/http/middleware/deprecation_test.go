@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecated(t *testing.T) {
+	next := func(w http.ResponseWriter, r *http.Request) {}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/choices/{id}", Deprecated("/api/v1/questions/{id}/choices", next))
+	mux.HandleFunc("DELETE /api/choices/delete/{choiceID}", Deprecated("/api/v1/questions/{id}/choices/{choiceID}", next))
+	mux.HandleFunc("POST /api/choices/create", Deprecated("/api/v1/questions/{id}/choices", next))
+
+	serve := func(method, path string) http.Header {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec.Header()
+	}
+
+	t.Run("移行先のリンクはリクエストのパスの値で埋める", func(t *testing.T) {
+		header := serve(http.MethodGet, "/api/choices/42")
+		assert.Equal(t, "true", header.Get("Deprecation"))
+		assert.Equal(t, `</api/v1/questions/42/choices>; rel="successor-version"`, header.Get("Link"))
+	})
+
+	t.Run("パスから埋められないワイルドカードがあればリンクを付与しない", func(t *testing.T) {
+		for _, req := range []struct{ method, path string }{
+			{http.MethodDelete, "/api/choices/delete/7"},
+			{http.MethodPost, "/api/choices/create"},
+		} {
+			header := serve(req.method, req.path)
+			assert.Equal(t, "true", header.Get("Deprecation"), req.path)
+			assert.Empty(t, header.Get("Link"), req.path)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package middleware
+
+// idempotency.goは Idempotency-Key ヘッダーで再送されたリクエストに最初のレスポンスを返すミドルウェアを定義
+// 通信が不安定なクライアントが再送しても、問題や回答が重複して作られないようにする
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"Shittaka_back/internal/application/idempotency"
+	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/presentation/http/accesstoken"
+	"Shittaka_back/internal/presentation/http/apierror"
+)
+
+// IdempotencyKeyHeader は再送の重複を防ぐキーを受け取るヘッダー名
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength は受け付けるキーの最大長
+const maxIdempotencyKeyLength = 255
+
+// idempotencyLockTTL は処理中のキーを確保しておく時間
+// 処理中にサーバーが止まっても、この時間が過ぎれば同じキーで再試行できる
+const idempotencyLockTTL = time.Minute
+
+// idempotencyScope は保存したレスポンスを返してよい利用者を表すキーを返す
+// 署名を検証できたトークンはユーザーID、検証できないトークンはトークンそのもののハッシュで区別する
+// （ユーザーIDは公開されているため、検証していないユーザーIDでは他のユーザーのレスポンスを取り出せてしまう）
+// トークンがなければクライアントのIPアドレスで区別する
+func idempotencyScope(r *http.Request) string {
+	if userID := accesstoken.FromContext(r.Context()); userID != "" {
+		return "user:" + userID
+	}
+	if token := accesstoken.Bearer(r); token != "" {
+		sum := sha256.Sum256([]byte(token))
+		return "token:" + hex.EncodeToString(sum[:])
+	}
+	return ClientIP(r)
+}
+
+// replayedHeaders は最初のレスポンスから保存し、再送に返すヘッダー
+var replayedHeaders = []string{"Content-Type", "Content-Language", "Location"}
+
+// Idempotency は Idempotency-Key ヘッダーのあるリクエストの最初のレスポンスを保存し、再送に同じレスポンスを返すミドルウェアを返す
+// キーは利用者ごと（idempotencyScope）に区別し、保存したレスポンスは ttl の間返す
+//   - 同じキーで内容（メソッド・パス・ボディ）の違うリクエストは422（IDEMPOTENCY_KEY_MISMATCH）
+//   - 最初のリクエストの処理中に届いた再送は409（IDEMPOTENCY_KEY_IN_USE）
+//   - 2xx 以外のレスポンスは保存しない（何も作られていないため、同じキーで再試行できる）
+//
+// ストアが失敗した場合は重複を防がずにリクエストを通す
+func Idempotency(store idempotency.Store, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			values := r.Header.Values(IdempotencyKeyHeader)
+			if len(values) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			key := values[0]
+			if len(values) > 1 || key == "" || len(key) > maxIdempotencyKeyLength {
+				apierror.Write(w, r, shared.NewDomainError(apierror.CodeInvalidIdempotencyKey).With("max", maxIdempotencyKeyLength))
+				return
+			}
+
+			// ボディはハッシュの計算とハンドラーの両方で使うため、先に読み込んでおく
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					apierror.Write(w, r, shared.NewDomainError(apierror.CodePayloadTooLarge).With("limit", maxBytesErr.Limit))
+					return
+				}
+				apierror.Respond(w, r, apierror.CodeBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			storeKey := idempotencyScope(r) + ":" + key
+			requestHash := idempotency.RequestHash(r, body)
+
+			record, err := store.Reserve(r.Context(), storeKey, requestHash, idempotencyLockTTL)
+			if err != nil {
+				slog.WarnContext(r.Context(), "idempotency store failed, processing request without deduplication", "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			switch {
+			case record == nil:
+				// 最初のリクエスト
+			case record.RequestHash != requestHash:
+				apierror.Respond(w, r, apierror.CodeIdempotencyKeyMismatch)
+				return
+			case !record.Completed:
+				apierror.Write(w, r, shared.NewDomainError(apierror.CodeIdempotencyKeyInUse).With("retry_after", 1))
+				return
+			default:
+				replay(w, *record)
+				return
+			}
+
+			capture := &responseCapture{statusRecorder: statusRecorder{ResponseWriter: w, status: http.StatusOK}}
+			next.ServeHTTP(capture, r)
+
+			// クライアントが切断していても結果は保存する
+			ctx := context.WithoutCancel(r.Context())
+			if capture.status < 200 || capture.status >= 300 {
+				if err := store.Release(ctx, storeKey); err != nil {
+					slog.WarnContext(ctx, "failed to release idempotency key", "error", err)
+				}
+				return
+			}
+
+			saved := idempotency.Record{
+				RequestHash: requestHash,
+				StatusCode:  capture.status,
+				Header:      make(map[string]string),
+				Body:        capture.body.Bytes(),
+			}
+			for _, name := range replayedHeaders {
+				if value := w.Header().Get(name); value != "" {
+					saved.Header[name] = value
+				}
+			}
+			if err := store.Complete(ctx, storeKey, saved, ttl); err != nil {
+				slog.WarnContext(ctx, "failed to save idempotent response", "error", err)
+			}
+		})
+	}
+}
+
+// replay は保存しておいた最初のレスポンスを書き込む
+func replay(w http.ResponseWriter, record idempotency.Record) {
+	for name, value := range record.Header {
+		w.Header().Set(name, value)
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.Header().Set("Content-Length", strconv.Itoa(len(record.Body)))
+	w.WriteHeader(record.StatusCode)
+	w.Write(record.Body)
+}
+
+// responseCapture はハンドラーが書き込んだステータスとボディを記録する
+type responseCapture struct {
+	statusRecorder
+	body bytes.Buffer
+}
+
+func (c *responseCapture) Write(b []byte) (int, error) {
+	c.body.Write(b)
+	return c.statusRecorder.Write(b)
+}
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	idempotencyStore "Shittaka_back/internal/infrastructure/idempotency"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotency(t *testing.T) {
+	var mu sync.Mutex
+	created := 0
+	// slow のボディのリクエストは started を閉じてから release が閉じられるまで待つ
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	handler := Idempotency(idempotencyStore.NewMemoryStore(), time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "slow") {
+			close(started)
+			<-release
+		}
+		if strings.Contains(string(body), "invalid") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		created++
+		id := created
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id":%d}`, id)
+	}))
+
+	serveAs := func(token, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/answers", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		req.RemoteAddr = "192.0.2.1:1234"
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	serve := func(key, body string) *httptest.ResponseRecorder {
+		return serveAs("", key, body)
+	}
+
+	t.Run("再送には最初のレスポンスを返す", func(t *testing.T) {
+		first := serve("key-1", `{"question_id":1}`)
+		retry := serve("key-1", `{"question_id":1}`)
+
+		assert.Equal(t, http.StatusCreated, first.Code)
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+		assert.Equal(t, "application/json", retry.Header().Get("Content-Type"))
+		assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+		assert.Empty(t, first.Header().Get("Idempotent-Replayed"))
+	})
+
+	t.Run("キーがなければ毎回処理する", func(t *testing.T) {
+		first := serve("", `{"question_id":1}`)
+		second := serve("", `{"question_id":1}`)
+
+		assert.NotEqual(t, first.Body.String(), second.Body.String())
+	})
+
+	t.Run("別の内容で同じキーを使うと422", func(t *testing.T) {
+		serve("key-2", `{"question_id":1}`)
+		rec := serve("key-2", `{"question_id":2}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"IDEMPOTENCY_KEY_MISMATCH"`)
+	})
+
+	t.Run("失敗したレスポンスは保存せず同じキーで再試行できる", func(t *testing.T) {
+		failed := serve("key-3", `{"invalid":true}`)
+		retry := serve("key-3", `{"invalid":true}`)
+
+		assert.Equal(t, http.StatusBadRequest, failed.Code)
+		assert.Equal(t, http.StatusBadRequest, retry.Code)
+		assert.Empty(t, retry.Header().Get("Idempotent-Replayed"))
+	})
+
+	t.Run("長すぎるキーは400", func(t *testing.T) {
+		rec := serve(strings.Repeat("a", 256), `{}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"INVALID_IDEMPOTENCY_KEY"`)
+	})
+
+	t.Run("検証していないトークンは同じユーザーIDでも別の利用者として区別する", func(t *testing.T) {
+		// どちらも {"sub":"user-1"} だが署名が異なる（偽のトークン）
+		first := serveAs("eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiJ1c2VyLTEifQ.sig-1", "key-5", `{"question_id":1}`)
+		forged := serveAs("eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiJ1c2VyLTEifQ.sig-2", "key-5", `{"question_id":1}`)
+
+		assert.Equal(t, http.StatusCreated, forged.Code)
+		assert.Empty(t, forged.Header().Get("Idempotent-Replayed"))
+		assert.NotEqual(t, first.Body.String(), forged.Body.String())
+	})
+
+	t.Run("処理中の再送は409", func(t *testing.T) {
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- serve("key-4", `{"slow":true}`) }()
+		<-started
+
+		rec := serve("key-4", `{"slow":true}`)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+		close(release)
+		assert.Equal(t, http.StatusCreated, (<-done).Code)
+	})
+}
@@ -0,0 +1,68 @@
+package middleware
+
+// logging.goはリクエストごとのアクセスログを出力するミドルウェアを定義
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"Shittaka_back/internal/presentation/http/accesstoken"
+)
+
+// Logging はリクエストの処理結果をアクセスログとして出力するミドルウェアを返す
+// メソッド・ルートのパターン・ステータス・処理時間・ユーザーIDを記録する
+// ユーザーIDは accesstoken.Middleware が署名を検証できた場合のみ記録する（検証していないIDは偽装できるため記録しない）
+// （リクエストIDは requestid.Middleware がコンテキストに設定したものをロガーが付与する）
+// ルートのパターンを取得するため、http.ServeMux の直前に置く
+func Logging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		level := slog.LevelInfo
+		if recorder.status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("route", r.Pattern),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", recorder.status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+		}
+		if userID := accesstoken.FromContext(r.Context()); userID != "" {
+			attrs = append(attrs, slog.String("user_id", userID))
+		}
+
+		slog.LogAttrs(r.Context(), level, "http request", attrs...)
+	})
+}
+
+// statusRecorder はハンドラーが書き込んだステータスを記録する
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap は http.ResponseController から元の ResponseWriter を使えるようにする
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
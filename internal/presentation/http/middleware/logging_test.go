@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Shittaka_back/internal/presentation/http/accesstoken"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/questions/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"user-1"}`))
+	token := "header." + payload + ".signature"
+
+	serve := func(t *testing.T, verifiedUserID string) map[string]interface{} {
+		t.Helper()
+		buf.Reset()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/questions/42", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		if verifiedUserID != "" {
+			req = req.WithContext(accesstoken.WithUserID(req.Context(), verifiedUserID))
+		}
+		Logging(mux).ServeHTTP(httptest.NewRecorder(), req)
+
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.NotContains(t, buf.String(), token)
+		return entry
+	}
+
+	t.Run("署名を検証したユーザーIDを記録する", func(t *testing.T) {
+		entry := serve(t, "user-1")
+		assert.Equal(t, "GET", entry["method"])
+		assert.Equal(t, "GET /api/questions/{id}", entry["route"])
+		assert.Equal(t, float64(http.StatusNotFound), entry["status"])
+		assert.Equal(t, "user-1", entry["user_id"])
+		assert.Contains(t, entry, "latency_ms")
+	})
+
+	t.Run("検証していないトークンのユーザーIDは記録しない", func(t *testing.T) {
+		entry := serve(t, "")
+		assert.NotContains(t, entry, "user_id")
+	})
+}
@@ -0,0 +1,33 @@
+package middleware
+
+// metrics.goはリクエストごとの件数と処理時間をメトリクスに記録するミドルウェアを定義
+
+import (
+	"net/http"
+	"time"
+
+	"Shittaka_back/internal/application/metrics"
+)
+
+// unmatchedRoute はどのルートにも一致しなかったリクエストのルート名
+// パスをそのままラベルにすると値の種類が際限なく増えるため、まとめて記録する
+const unmatchedRoute = "unmatched"
+
+// Metrics はリクエストのメソッド・ルートのパターン・ステータス・処理時間を記録するミドルウェアを返す
+// ルートのパターンを取得するため、Logging と同じく http.ServeMux の直前に置く
+func Metrics(recorder metrics.Recorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rec, r)
+
+			route := r.Pattern
+			if route == "" {
+				route = unmatchedRoute
+			}
+			recorder.ObserveHTTPRequest(r.Method, route, rec.status, time.Since(start))
+		})
+	}
+}
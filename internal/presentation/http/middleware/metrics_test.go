@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Shittaka_back/internal/application/metrics"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// httpObservation は記録されたHTTPリクエスト
+type httpObservation struct {
+	method string
+	route  string
+	status int
+}
+
+// fakeRecorder はHTTPリクエストの記録を保持する Recorder
+type fakeRecorder struct {
+	metrics.Noop
+	observations []httpObservation
+}
+
+func (r *fakeRecorder) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	r.observations = append(r.observations, httpObservation{method: method, route: route, status: status})
+}
+
+func TestMetrics(t *testing.T) {
+	recorder := &fakeRecorder{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/questions/{id}/choices", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	handler := Metrics(recorder)(mux)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/questions/42/choices", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/unknown/123", nil))
+
+	assert.Equal(t, []httpObservation{
+		// パスではなくルートのパターンで記録する
+		{method: http.MethodPost, route: "POST /api/questions/{id}/choices", status: http.StatusCreated},
+		// 一致するルートがない場合はまとめて記録する
+		{method: http.MethodGet, route: unmatchedRoute, status: http.StatusNotFound},
+	}, recorder.observations)
+}
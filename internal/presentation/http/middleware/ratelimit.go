@@ -0,0 +1,70 @@
+package middleware
+
+// ratelimit.goはトークンバケット方式でリクエストの頻度を制限するミドルウェアを定義
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"Shittaka_back/internal/application/ratelimit"
+	"Shittaka_back/internal/domain/shared"
+	"Shittaka_back/internal/presentation/http/accesstoken"
+	"Shittaka_back/internal/presentation/http/apierror"
+	"Shittaka_back/internal/presentation/http/clientip"
+)
+
+// RateLimitKey はリクエストからレート制限のキー（誰のリクエストか）を取り出す
+type RateLimitKey func(r *http.Request) string
+
+// ClientIP はクライアントのIPアドレスをキーにする
+func ClientIP(r *http.Request) string {
+	return "ip:" + clientip.FromRequest(r)
+}
+
+// UserOrClientIP は署名を検証したトークンのユーザーIDを、検証できなければクライアントのIPアドレスをキーにする
+// 検証していないユーザーIDを使うと、リクエストごとに別のユーザーIDの偽のトークンを送って制限を逃れられるため使わない
+func UserOrClientIP(r *http.Request) string {
+	if userID := accesstoken.FromContext(r.Context()); userID != "" {
+		return "user:" + userID
+	}
+	return ClientIP(r)
+}
+
+// RateLimit はキーごとにリクエストの頻度を制限するミドルウェアを返す
+// 全てのレスポンスに RateLimit-Limit / RateLimit-Remaining / RateLimit-Reset を付与し、
+// 制限を超えた場合は Retry-After を付けて429（RATE_LIMITED）を返す
+// ストアが失敗した場合は制限せずにリクエストを通す
+func RateLimit(store ratelimit.Store, policy ratelimit.Policy, key RateLimitKey) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			decision, err := store.Take(r.Context(), policy.Name+":"+key(r), policy)
+			if err != nil {
+				slog.WarnContext(r.Context(), "rate limit store failed, allowing request", "policy", policy.Name, "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(decision.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(seconds(decision.Reset)))
+
+			if !decision.Allowed {
+				slog.WarnContext(r.Context(), "rate limit exceeded", "policy", policy.Name, "method", r.Method, "path", r.URL.Path)
+
+				// Retry-After ヘッダーは apierror が retry_after から付与する
+				apierror.Write(w, r, shared.NewDomainError(apierror.CodeRateLimited).With("retry_after", seconds(decision.RetryAfter)))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// seconds は時間を秒に切り上げる（ヘッダーの値は整数の秒）
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	page := ContentSecurityPolicy(PageContentSecurityPolicy, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/genres", func(w http.ResponseWriter, r *http.Request) {})
+	mux.Handle("GET /docs", page)
+	handler := SecurityHeaders(365 * 24 * time.Hour)(mux)
+
+	t.Run("APIには厳しいCSPを付与する", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/genres", nil))
+
+		assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "strict-origin-when-cross-origin", rec.Header().Get("Referrer-Policy"))
+		assert.Equal(t, APIContentSecurityPolicy, rec.Header().Get("Content-Security-Policy"))
+		assert.Empty(t, rec.Header().Get("Strict-Transport-Security"))
+	})
+
+	t.Run("ページのCSPはSwagger UIの読み込みを許可する", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+		assert.Contains(t, rec.Header().Get("Content-Security-Policy"), "script-src 'self' 'unsafe-inline' https://unpkg.com")
+	})
+
+	t.Run("HTTPSのリクエストにはHSTSを付与する", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/genres", nil)
+		req.Header.Set("X-Forwarded-Proto", "https")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, "max-age=31536000; includeSubDomains", rec.Header().Get("Strict-Transport-Security"))
+	})
+}
@@ -0,0 +1,44 @@
+package middleware
+
+// tracing.goはリクエストごとにOpenTelemetryのスパンを作るミドルウェアを定義
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer はHTTPリクエストのスパンのトレーサー
+var tracer = otel.Tracer("Shittaka_back/internal/presentation/http/middleware")
+
+// Tracing はリクエストごとにサーバースパンを作り、コンテキストに設定するミドルウェアを返す
+// 受信した traceparent ヘッダーがあればその続きとしてスパンを作る
+// スパン名にルートのパターンを使うため、コンテキストを差し替えたリクエストを ServeMux まで渡す
+// （Logging と Metrics はこのリクエストのパターンを参照するため、Tracing はそれらより外側に置く）
+func Tracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, r.Method, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+			attribute.String("http.request.method", r.Method),
+			attribute.String("url.path", r.URL.Path),
+		))
+		defer span.End()
+
+		r = r.WithContext(ctx)
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		if r.Pattern != "" {
+			span.SetName(r.Pattern)
+			span.SetAttributes(attribute.String("http.route", r.Pattern))
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", rec.status))
+		if rec.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rec.status))
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package openapi
+
+// openapi.goはAPI仕様（OpenAPI 3.1）とドキュメントページを配信する
+// 仕様は openapi.json に手で書き、ルートを追加・変更したら合わせて更新する
+// （登録されているルートが仕様にない場合は router のテストが失敗する）
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+//go:embed openapi.json
+var spec []byte
+
+//go:embed docs.html
+var docsPage []byte
+
+// Spec はOpenAPIドキュメント（JSON）を返す
+func Spec() []byte {
+	return spec
+}
+
+// SpecHandler はOpenAPIドキュメントを返す
+func SpecHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(spec)
+}
+
+// DocsHandler はOpenAPIドキュメントを表示するSwagger UIのページを返す
+func DocsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(docsPage)
+}
@@ -0,0 +1,69 @@
+package requestid
+
+// requestid.goはリクエストごとのIDを発行し、コンテキストで受け渡す
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// HeaderName はリクエストIDを受け渡すヘッダー名
+const HeaderName = "X-Request-ID"
+
+// maxLength はクライアントから受け付けるリクエストIDの最大長
+const maxLength = 128
+
+type contextKey struct{}
+
+// WithID はコンテキストにリクエストIDを設定する
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext はコンテキストからリクエストIDを取得する（未設定なら空文字）
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Middleware はリクエストIDを発行するミドルウェア
+// クライアントが X-Request-ID を指定した場合はそれを引き継ぎ、レスポンスヘッダーにも付与する
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(HeaderName)
+		if !isValid(id) {
+			id = newID()
+		}
+
+		w.Header().Set(HeaderName, id)
+		next.ServeHTTP(w, r.WithContext(WithID(r.Context(), id)))
+	})
+}
+
+// newID はランダムなリクエストIDを生成する
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// isValid はクライアント指定のリクエストIDとして受け付けられるかを判定する
+// ログやヘッダーを汚さないよう、英数字と一部の記号のみを許可する
+func isValid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-' || c == '_' || c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package router
+
+// fallback.goはどのルートにも一致しないリクエストをJSONのエラーで返す
+
+import (
+	"net/http"
+	"strings"
+
+	"Shittaka_back/internal/presentation/http/apierror"
+)
+
+// staticPattern は静的ファイル配信のパターン
+const staticPattern = "GET /"
+
+// withJSONFallback は http.ServeMux が返す平文の 404 / 405 を、共通のエラーレスポンスに置き換える
+// /api 以下で静的ファイル配信にしか一致しないリクエストも 404 とする
+func withJSONFallback(mux *http.ServeMux) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, pattern := mux.Handler(r)
+		isAPI := strings.HasPrefix(r.URL.Path, "/api/")
+
+		switch {
+		case pattern == "":
+			// ServeMux の応答からステータスと Allow ヘッダーだけを取り出す
+			capture := &statusCapture{header: http.Header{}}
+			mux.ServeHTTP(capture, r)
+
+			if capture.status == http.StatusMethodNotAllowed && !(isAPI && onlyStaticAllows(mux, r)) {
+				w.Header().Set("Allow", capture.header.Get("Allow"))
+				apierror.Respond(w, r, apierror.CodeMethodNotAllowed)
+				return
+			}
+			apierror.Respond(w, r, apierror.CodeNotFound)
+		case pattern == staticPattern && isAPI:
+			apierror.Respond(w, r, apierror.CodeNotFound)
+		default:
+			mux.ServeHTTP(w, r)
+		}
+	}
+}
+
+// onlyStaticAllows はパスに一致するルートが静的ファイル配信だけかどうかを判定する
+func onlyStaticAllows(mux *http.ServeMux, r *http.Request) bool {
+	probe := r.Clone(r.Context())
+	probe.Method = http.MethodGet
+	_, pattern := mux.Handler(probe)
+	return pattern == staticPattern
+}
+
+// statusCapture はレスポンスを書き込まずにステータスとヘッダーを記録する
+type statusCapture struct {
+	header http.Header
+	status int
+}
+
+func (c *statusCapture) Header() http.Header { return c.header }
+
+func (c *statusCapture) Write(b []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	return len(b), nil
+}
+
+func (c *statusCapture) WriteHeader(status int) {
+	if c.status == 0 {
+		c.status = status
+	}
+}
@@ -4,98 +4,334 @@ package router
 
 import (
 	"net/http"
+	"strings"
+	"time"
 
+	"Shittaka_back/internal/application/idempotency"
+	"Shittaka_back/internal/application/metrics"
+	"Shittaka_back/internal/application/ratelimit"
+	"Shittaka_back/internal/presentation/http/accesstoken"
+	"Shittaka_back/internal/presentation/http/apierror"
+	"Shittaka_back/internal/presentation/http/clientip"
 	"Shittaka_back/internal/presentation/http/handlers"
 	"Shittaka_back/internal/presentation/http/middleware"
+	"Shittaka_back/internal/presentation/http/openapi"
+	"Shittaka_back/internal/presentation/http/requestid"
+	"Shittaka_back/internal/presentation/http/versioning"
 )
 
-// SetupRoutes はルーティングを設定
-func SetupRoutes(authHandler *handlers.AuthHandler, profileHandler *handlers.ProfileHandler, genreHandler *handlers.GenreHandler, questionHandler *handlers.QuestionHandler, answerHandler *handlers.AnswerHandler, choiceHandler *handlers.ChoiceHandler) *http.ServeMux {
-	mux := http.NewServeMux()
+// Handlers はルーティングに登録するハンドラーをまとめたもの
+type Handlers struct {
+	Auth     *handlers.AuthHandler
+	Profile  *handlers.ProfileHandler
+	Genre    *handlers.GenreHandler
+	Question *handlers.QuestionHandler
+	Answer   *handlers.AnswerHandler
+	Choice   *handlers.ChoiceHandler
+	Tag      *handlers.TagHandler
+	Health   *handlers.HealthHandler
 
-	// 認証関連のエンドポイント
-	mux.HandleFunc("/api/auth/signup", middleware.CORS(authHandler.SignupHandler))
-	mux.HandleFunc("/api/auth/login", middleware.CORS(authHandler.LoginHandler))
-	mux.HandleFunc("/api/auth/logout", middleware.CORS(authHandler.LogoutHandler))
-	mux.HandleFunc("/api/auth/me", middleware.CORS(authHandler.GetCurrentUserHandler))
-	mux.HandleFunc("/api/auth/test", middleware.CORS(authHandler.TestConnectionHandler))
-
-	// プロフィール関連のエンドポイント
-	mux.HandleFunc("/api/profiles/", middleware.CORS(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			profileHandler.GetProfileHandler(w, r)
-		case "PUT":
-			profileHandler.UpdateProfileHandler(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-	mux.HandleFunc("/api/profiles", middleware.CORS(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodPost:
-			profileHandler.CreateProfileHandler(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	// ジャンル関連のエンドポイント
-	mux.HandleFunc("/api/genres", middleware.CORS(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodPost:
-			genreHandler.CreateGenreHandler(w, r)
-		case http.MethodGet:
-			genreHandler.GetAllGenresHandler(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
-	}))
-
-	// 問題関連のエンドポイント
-	mux.HandleFunc("/api/questions", middleware.CORS(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodPost:
-			questionHandler.CreateQuestionHandler(w, r)
-		case http.MethodGet:
-			questionHandler.GetQuestionsHandler(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	// Metrics はメトリクスの記録と /metrics での公開を行う（nil ならどちらも行わない）
+	Metrics Metrics
+
+	// DisableDocs が true なら /docs と /api/openapi.json は404を返す
+	DisableDocs bool
+
+	// CORS はCORSの設定（AllowedOrigins が空ならクロスオリジンのリクエストを許可しない）
+	CORS middleware.CORSOptions
+
+	// HSTSMaxAge はHTTPSのレスポンスに付与する Strict-Transport-Security の max-age（0 なら付与しない）
+	HSTSMaxAge time.Duration
+
+	// RateLimit はレート制限の設定（Store が nil なら制限しない）
+	RateLimit RateLimit
+
+	// TokenVerifier はアクセストークンの署名を検証する（nil なら検証せず、レート制限などはIPアドレスごとに行う）
+	TokenVerifier *accesstoken.Verifier
+
+	// ClientIPHeader はプロキシがクライアントのIPアドレスを設定するヘッダー（空なら接続元のアドレスを使う）
+	ClientIPHeader string
+
+	// MaxBodyBytes はリクエストボディの最大サイズ（0 なら制限しない）
+	MaxBodyBytes int64
+
+	// Idempotency は再送の重複防止の設定（Store が nil なら Idempotency-Key ヘッダーを無視する）
+	Idempotency Idempotency
+
+	// PublicCacheMaxAge は公開の読み取りのレスポンスを再検証せずにキャッシュさせる時間（0 なら毎回 ETag で再検証させる）
+	PublicCacheMaxAge time.Duration
+}
+
+// Idempotency は Idempotent なルートに適用する再送の重複防止の設定
+type Idempotency struct {
+	Store idempotency.Store
+
+	// TTL は最初のレスポンスを保存しておく時間
+	TTL time.Duration
+}
+
+// RateLimit はルートに適用するレート制限の設定
+type RateLimit struct {
+	Store ratelimit.Store
+
+	// Auth はログイン・ユーザー登録・トークン更新に適用する方針（IPアドレスごと）
+	Auth ratelimit.Policy
+
+	// Write はその他の書き込み（GET 以外）に適用する方針（署名を検証したユーザーごと、検証できなければIPアドレスごと）
+	Write ratelimit.Policy
+
+	// Routes はルートごとの方針（キーは "POST /api/auth/login" のようなバージョンなしのパターン）
+	// 指定したルートには Auth や Write の代わりにこの方針を適用する
+	Routes map[string]RoutePolicy
+}
+
+// RoutePolicy はルートごとのレート制限の方針
+type RoutePolicy struct {
+	Policy ratelimit.Policy
+
+	// Key は制限を数える単位
+	Key middleware.RateLimitKey
+}
+
+// policy はリクエストが一致したパターンのルートに適用する方針を返す（制限しないなら false）
+// パターンはバージョンを除いて選ぶため、バージョン付きとバージョンなしのパスで同じ方針になる
+func (l RateLimit) policy(route Route, pattern string) (RoutePolicy, bool) {
+	method, path, _ := strings.Cut(pattern, " ")
+	if p, ok := l.Routes[method+" "+versioning.Unversioned(path)]; ok {
+		return p, true
+	}
+	switch {
+	case route.RateLimit == rateLimitAuth:
+		return RoutePolicy{Policy: l.Auth, Key: middleware.ClientIP}, true
+	case route.Method != http.MethodGet:
+		return RoutePolicy{Policy: l.Write, Key: middleware.UserOrClientIP}, true
+	default:
+		return RoutePolicy{}, false
+	}
+}
+
+// レート制限の種類（Route.RateLimit の値）
+const (
+	rateLimitAuth = "auth"
+)
+
+// キャッシュの種類（Route.Cache の値）
+const (
+	cachePublic  = "public"  // 誰が取得しても同じレスポンス
+	cachePrivate = "private" // ログイン中のユーザーごとのレスポンス
+)
+
+// cached はキャッシュできるルートに条件付きGETを適用したハンドラーを返す
+func (h Handlers) cached(route Route) http.HandlerFunc {
+	switch route.Cache {
+	case cachePublic:
+		return middleware.ConditionalGET(middleware.CachePolicy{MaxAge: h.PublicCacheMaxAge})(route.Handler).ServeHTTP
+	case cachePrivate:
+		return middleware.ConditionalGET(middleware.CachePolicy{Private: true})(route.Handler).ServeHTTP
+	default:
+		return route.Handler
+	}
+}
+
+// idempotent は Idempotent なルートに再送の重複防止を適用したハンドラーを返す
+func (h Handlers) idempotent(route Route) http.HandlerFunc {
+	if !route.Idempotent || h.Idempotency.Store == nil {
+		return route.Handler
+	}
+	return middleware.Idempotency(h.Idempotency.Store, h.Idempotency.TTL)(route.Handler).ServeHTTP
+}
+
+// withBodyCheck はGET以外のルートにリクエストボディの検査を適用したハンドラーを返す
+func (h Handlers) withBodyCheck(route Route) http.HandlerFunc {
+	if route.Method == http.MethodGet {
+		return route.Handler
+	}
+	return middleware.RequestBody(h.MaxBodyBytes)(route.Handler).ServeHTTP
+}
+
+// rateLimited はルートにレート制限を適用したハンドラーを返す
+// 方針はリクエストが一致したパターン（r.Pattern）から選ぶ
+func (h Handlers) rateLimited(route Route) http.HandlerFunc {
+	limit := h.RateLimit
+	if limit.Store == nil {
+		return route.Handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		policy, ok := limit.policy(route, r.Pattern)
+		if !ok {
+			route.Handler(w, r)
+			return
 		}
-	}))
-	mux.HandleFunc("/api/questions/", middleware.CORS(func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			questionHandler.GetQuestionHandler(w, r)
-		case "PUT":
-			questionHandler.UpdateQuestionHandler(w, r)
-		case http.MethodDelete:
-			questionHandler.DeleteQuestionHandler(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		middleware.RateLimit(limit.Store, policy.Policy, policy.Key)(route.Handler).ServeHTTP(w, r)
+	}
+}
+
+// Metrics はメトリクスを記録し、HTTPで公開する
+type Metrics interface {
+	metrics.Recorder
+	http.Handler
+}
+
+// metrics は設定されたメトリクスを返す（未設定なら何もしない実装）
+func (h Handlers) metrics() Metrics {
+	if h.Metrics == nil {
+		return noopMetrics{}
+	}
+	return h.Metrics
+}
+
+// noopMetrics は何も記録せず、/metrics には404を返す
+type noopMetrics struct {
+	metrics.Noop
+}
+
+func (noopMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	apierror.Respond(w, r, apierror.CodeNotFound)
+}
+
+// docs はAPIドキュメントのハンドラーを返す（無効なら404を返す）
+func (h Handlers) docs(handler http.HandlerFunc) http.HandlerFunc {
+	if h.DisableDocs {
+		return func(w http.ResponseWriter, r *http.Request) {
+			apierror.Respond(w, r, apierror.CodeNotFound)
 		}
-	}))
-	mux.HandleFunc("/api/my-questions", middleware.CORS(questionHandler.GetMyQuestionsHandler))
+	}
+	return middleware.ContentSecurityPolicy(middleware.PageContentSecurityPolicy, handler).ServeHTTP
+}
+
+// Route はAPIのルート定義
+type Route struct {
+	Method  string
+	Path    string
+	Handler http.HandlerFunc
+
+	// Successor は非推奨の旧パスの場合に移行先のパスを示す（空なら現行のルート）
+	Successor string
+
+	// RateLimit は適用するレート制限の種類（空なら GET 以外に書き込みの制限を適用する）
+	RateLimit string
+
+	// Idempotent が true なら Idempotency-Key ヘッダーによる再送の重複防止を適用する
+	Idempotent bool
+
+	// Cache はGETのレスポンスをキャッシュさせる種類（空ならキャッシュの制御をしない）
+	// ETag と Last-Modified を付与し、If-None-Match / If-Modified-Since に304を返す
+	Cache string
+}
+
+// Pattern は http.ServeMux に登録するパターン（"GET /api/questions/{id}" の形式）を返す
+func (r Route) Pattern() string {
+	return r.Method + " " + r.Path
+}
+
+// Routes はAPIのルート一覧を返す
+func Routes(h Handlers) []Route {
+	return []Route{
+		// 認証関連のエンドポイント
+		{Method: http.MethodPost, Path: "/api/auth/signup", Handler: h.Auth.SignupHandler, RateLimit: rateLimitAuth},
+		{Method: http.MethodPost, Path: "/api/auth/login", Handler: h.Auth.LoginHandler, RateLimit: rateLimitAuth},
+		{Method: http.MethodPost, Path: "/api/auth/refresh", Handler: h.Auth.RefreshHandler, RateLimit: rateLimitAuth},
+		{Method: http.MethodPost, Path: "/api/auth/logout", Handler: h.Auth.LogoutHandler},
+		{Method: http.MethodGet, Path: "/api/auth/me", Handler: h.Auth.GetCurrentUserHandler, Cache: cachePrivate},
+		{Method: http.MethodGet, Path: "/api/auth/test", Handler: h.Auth.TestConnectionHandler},
+
+		// プロフィール関連のエンドポイント
+		{Method: http.MethodPost, Path: "/api/profiles", Handler: h.Profile.CreateProfileHandler},
+		{Method: http.MethodGet, Path: "/api/profiles/{userID}", Handler: h.Profile.GetProfileHandler},
+		{Method: http.MethodPut, Path: "/api/profiles/{userID}", Handler: h.Profile.UpdateProfileHandler},
+
+		// ジャンル関連のエンドポイント
+		{Method: http.MethodGet, Path: "/api/genres", Handler: h.Genre.GetAllGenresHandler, Cache: cachePublic},
+		{Method: http.MethodPost, Path: "/api/genres", Handler: h.Genre.CreateGenreHandler},
+
+		// 問題関連のエンドポイント
+		{Method: http.MethodGet, Path: "/api/questions", Handler: h.Question.GetQuestionsHandler, Cache: cachePublic},
+		{Method: http.MethodPost, Path: "/api/questions", Handler: h.Question.CreateQuestionHandler, Idempotent: true},
+		{Method: http.MethodGet, Path: "/api/questions/{id}", Handler: h.Question.GetQuestionHandler},
+		{Method: http.MethodPut, Path: "/api/questions/{id}", Handler: h.Question.UpdateQuestionHandler},
+		{Method: http.MethodDelete, Path: "/api/questions/{id}", Handler: h.Question.DeleteQuestionHandler},
+		{Method: http.MethodGet, Path: "/api/my-questions", Handler: h.Question.GetMyQuestionsHandler, Cache: cachePrivate},
 
-	// 回答関連のエンドポイント
-	mux.HandleFunc("/api/answers", middleware.CORS(answerHandler.CreateAnswerHandler))
+		// 選択肢関連のエンドポイント
+		// 選択肢の並び順はユーザーごとにシャッフルするため、共有キャッシュには保存させない
+		{Method: http.MethodGet, Path: "/api/questions/{id}/choices", Handler: h.Choice.GetChoicesHandler, Cache: cachePrivate},
+		{Method: http.MethodPost, Path: "/api/questions/{id}/choices", Handler: h.Choice.CreateChoiceHandler},
+		{Method: http.MethodPut, Path: "/api/questions/{id}/choices", Handler: h.Choice.ReplaceChoicesHandler},
+		{Method: http.MethodPut, Path: "/api/questions/{id}/choices/order", Handler: h.Choice.ReorderChoicesHandler},
+		{Method: http.MethodPut, Path: "/api/questions/{id}/choices/{choiceID}", Handler: h.Choice.UpdateChoiceHandler},
+		{Method: http.MethodDelete, Path: "/api/questions/{id}/choices/{choiceID}", Handler: h.Choice.DeleteChoiceHandler},
 
-	// 選択肢関連のエンドポイント
-	mux.HandleFunc("/api/choices/", middleware.CORS(choiceHandler.GetChoicesHandler))          // GET /api/choices/{questionID}
-	mux.HandleFunc("/api/choices/create", middleware.CORS(choiceHandler.CreateChoiceHandler))  // POST /api/choices/create
-	mux.HandleFunc("/api/choices/update", middleware.CORS(choiceHandler.UpdateChoiceHandler))  // PUT /api/choices/update
-	mux.HandleFunc("/api/choices/delete/", middleware.CORS(choiceHandler.DeleteChoiceHandler)) // DELETE /api/choices/delete/{id}
+		// 選択肢関連の旧パス（非推奨）
+		{Method: http.MethodGet, Path: "/api/choices/{id}", Handler: h.Choice.GetChoicesHandler, Successor: "/api/questions/{id}/choices", Cache: cachePrivate},
+		{Method: http.MethodPost, Path: "/api/choices/create", Handler: h.Choice.CreateChoiceHandler, Successor: "/api/questions/{id}/choices"},
+		{Method: http.MethodPut, Path: "/api/choices/update", Handler: h.Choice.UpdateChoiceHandler, Successor: "/api/questions/{id}/choices/{choiceID}"},
+		{Method: http.MethodDelete, Path: "/api/choices/delete/{choiceID}", Handler: h.Choice.DeleteChoiceHandler, Successor: "/api/questions/{id}/choices/{choiceID}"},
 
-	// ヘルスチェック用エンドポイント
-	mux.HandleFunc("/health", middleware.CORS(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status": "ok"}`))
-	}))
+		// タグ関連のエンドポイント
+		{Method: http.MethodGet, Path: "/api/tags", Handler: h.Tag.GetTagsHandler},
+
+		// 回答関連のエンドポイント
+		{Method: http.MethodPost, Path: "/api/answers", Handler: h.Answer.CreateAnswerHandler, Idempotent: true},
+
+		// APIドキュメント
+		{Method: http.MethodGet, Path: "/api/openapi.json", Handler: h.docs(openapi.SpecHandler)},
+		{Method: http.MethodGet, Path: "/docs", Handler: h.docs(openapi.DocsHandler)},
+
+		// ヘルスチェック用エンドポイント（/health は /health/live と同じ）
+		{Method: http.MethodGet, Path: "/health", Handler: h.Health.LiveHandler},
+		{Method: http.MethodGet, Path: "/health/live", Handler: h.Health.LiveHandler},
+		{Method: http.MethodGet, Path: "/health/ready", Handler: h.Health.ReadyHandler},
+		{Method: http.MethodGet, Path: "/version", Handler: h.Health.VersionHandler},
+
+		// Prometheus のメトリクス
+		{Method: http.MethodGet, Path: "/metrics", Handler: h.metrics().ServeHTTP},
+	}
+}
+
+// SetupRoutes はルーティングを設定
+// APIは /api/{version}/... にマウントし、バージョンなしの /api/... は既定バージョンのエイリアスとする
+// CORSはプリフライト（OPTIONS）にも応答できるよう、ルーター全体に適用する
+func SetupRoutes(h Handlers) http.Handler {
+	mux := http.NewServeMux()
+
+	for _, route := range Routes(h) {
+		// 重複防止はボディを読み込むため、ボディの検査の内側で行う
+		// ボディの検査はレート制限の内側で行う（検査で拒否されるリクエストも回数に数える）
+		route.Handler = h.idempotent(route)
+		route.Handler = h.withBodyCheck(route)
+		route.Handler = h.cached(route)
+
+		// レート制限はバージョン付きとバージョンなしのパスで共有する
+		handler := h.rateLimited(route)
+
+		// 非推奨の旧パスはバージョンなしのみ登録し、移行先は既定バージョンのパスを案内する
+		if route.Successor != "" {
+			deprecated := middleware.Deprecated(versioning.Path(versioning.Default, route.Successor), handler)
+			mux.HandleFunc(route.Pattern(), versioning.Middleware(versioning.Default, deprecated))
+			continue
+		}
+
+		for _, v := range versioning.Supported {
+			if versioned := versioning.Path(v, route.Path); versioned != route.Path {
+				mux.HandleFunc(route.Method+" "+versioned, versioning.Middleware(v, handler))
+			}
+		}
+		mux.HandleFunc(route.Pattern(), versioning.Middleware(versioning.Default, handler))
+	}
 
 	// 静的ファイル配信
 	fs := http.FileServer(http.Dir("./static/"))
-	mux.Handle("/", http.StripPrefix("/", fs))
+	mux.Handle(staticPattern, middleware.ContentSecurityPolicy(middleware.PageContentSecurityPolicy, http.StripPrefix("/", fs)))
 
-	return mux
+	// 一致するルートがない場合もJSONのエラーを返し、全てのレスポンスにリクエストIDを付与する
+	// アクセスログとメトリクスはルートのパターンを記録するため ServeMux の直前で出力する
+	// トレースのスパンはアクセスログにトレースIDを付与できるよう、その外側で開始する
+	// セキュリティ関連のヘッダーとCORSのヘッダーは、プリフライトやエラーを含む全てのレスポンスに付与する
+	// アクセストークンの検証はレート制限と重複防止より前に行い、検証したユーザーIDだけをキーに使う
+	metricsMiddleware := middleware.Metrics(h.metrics())
+	securityMiddleware := middleware.SecurityHeaders(h.HSTSMaxAge)
+	corsMiddleware := middleware.CORS(h.CORS)
+	clientIPMiddleware := clientip.Middleware(h.ClientIPHeader)
+	tokenMiddleware := accesstoken.Middleware(h.TokenVerifier)
+	return securityMiddleware(corsMiddleware(requestid.Middleware(clientIPMiddleware(tokenMiddleware(middleware.Tracing(middleware.Logging(metricsMiddleware(withJSONFallback(mux)))))))))
 }
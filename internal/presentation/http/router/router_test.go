@@ -0,0 +1,201 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"Shittaka_back/internal/application/ratelimit"
+	ratelimitStore "Shittaka_back/internal/infrastructure/ratelimit"
+	presentationDTO "Shittaka_back/internal/presentation/dto"
+	"Shittaka_back/internal/presentation/http/middleware"
+	"Shittaka_back/internal/presentation/http/openapi"
+	"Shittaka_back/internal/presentation/http/versioning"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetupRoutes(t *testing.T) {
+	// パターンの衝突があると登録時に panic する
+	var handler http.Handler
+	assert.NotPanics(t, func() {
+		handler = SetupRoutes(Handlers{})
+	})
+
+	t.Run("プリフライトはルート全体でCORSが応答する", func(t *testing.T) {
+		handler := SetupRoutes(Handlers{CORS: middleware.CORSOptions{AllowedOrigins: []string{"https://shittaka.app"}}})
+		req := httptest.NewRequest(http.MethodOptions, "/api/questions/1/choices/order", nil)
+		req.Header.Set("Origin", "https://shittaka.app")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "https://shittaka.app", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+	})
+
+	t.Run("バージョン付きのパスにもマウントされる", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/genres", nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		// パスが一致しメソッドだけが異なる場合は405になる
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+
+	t.Run("非推奨の旧パスはバージョン付きでは提供しない", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/choices/create", nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("書き込みのボディはJSONに限る", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/genres", strings.NewReader("name=a"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+		assertErrorEnvelope(t, rec, "UNSUPPORTED_MEDIA_TYPE")
+	})
+
+	t.Run("未対応のメソッドは405", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/api/genres", nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.Equal(t, "GET, HEAD, POST", rec.Header().Get("Allow"))
+		assertErrorEnvelope(t, rec, "METHOD_NOT_ALLOWED")
+	})
+
+	t.Run("存在しないAPIはJSONの404", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/unknown", nil)
+		req.Header.Set("X-Request-ID", "test-request-1")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		body := assertErrorEnvelope(t, rec, "NOT_FOUND")
+		assert.Equal(t, "test-request-1", body.Error.RequestID)
+		assert.Equal(t, "test-request-1", rec.Header().Get("X-Request-ID"))
+	})
+
+	t.Run("ドキュメントを無効にすると404", func(t *testing.T) {
+		handler := SetupRoutes(Handlers{DisableDocs: true})
+		for _, path := range []string{"/docs", "/api/openapi.json"} {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusNotFound, rec.Code, path)
+		}
+	})
+}
+
+func TestSetupRoutes_RateLimitPerRoute(t *testing.T) {
+	handler := SetupRoutes(Handlers{RateLimit: RateLimit{
+		Store: ratelimitStore.NewMemoryStore(),
+		Auth:  ratelimit.Policy{Name: "auth", RequestsPerMinute: 10, Burst: 5},
+		Write: ratelimit.Policy{Name: "write", RequestsPerMinute: 120, Burst: 30},
+		Routes: map[string]RoutePolicy{
+			"POST /api/auth/login": {
+				Policy: ratelimit.Policy{Name: "POST /api/auth/login", RequestsPerMinute: 1, Burst: 1},
+				Key:    middleware.ClientIP,
+			},
+		},
+	}})
+
+	// 形式の誤ったボディはハンドラーを呼ばずに415で拒否されるが、レート制限の回数には数える
+	post := func(path string) int {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader("name=a"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	t.Run("指定したルートはその方針で制限する", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnsupportedMediaType, post("/api/v1/auth/login"))
+		assert.Equal(t, http.StatusTooManyRequests, post("/api/v1/auth/login"))
+		// バージョンなしのパスも同じルートとして数える
+		assert.Equal(t, http.StatusTooManyRequests, post("/api/auth/login"))
+	})
+
+	t.Run("指定していないルートは既定の方針で制限する", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, http.StatusUnsupportedMediaType, post("/api/v1/auth/signup"))
+		}
+		assert.Equal(t, http.StatusTooManyRequests, post("/api/v1/auth/signup"))
+	})
+}
+
+// assertErrorEnvelope は共通のエラーレスポンスの形であることを検証する
+func assertErrorEnvelope(t *testing.T, rec *httptest.ResponseRecorder, code string) presentationDTO.ErrorResponse {
+	t.Helper()
+
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var body presentationDTO.ErrorResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, code, body.Error.Code)
+	assert.NotEmpty(t, body.Error.Message)
+	return body
+}
+
+func TestRoutes_DeprecatedAliasesHaveSuccessor(t *testing.T) {
+	current := make(map[string]bool)
+	for _, route := range Routes(Handlers{}) {
+		if route.Successor == "" {
+			current[route.Path] = true
+		}
+	}
+
+	// 旧パスの移行先は現行のルートとして登録されている
+	for _, route := range Routes(Handlers{}) {
+		if route.Successor != "" {
+			assert.True(t, current[route.Successor], "successor of %s is not registered: %s", route.Pattern(), route.Successor)
+		}
+	}
+}
+
+func TestRoutes_ChoicesAreNotPublicCache(t *testing.T) {
+	// 選択肢の並び順はユーザーごとにシャッフルするため、共有キャッシュに保存させない
+	for _, route := range Routes(Handlers{}) {
+		if route.Method == http.MethodGet && strings.Contains(route.Path, "choices") {
+			assert.Equal(t, cachePrivate, route.Cache, route.Pattern())
+		}
+	}
+}
+
+func TestRoutesAreDocumented(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	assert.NoError(t, json.Unmarshal(openapi.Spec(), &spec))
+
+	// 仕様のパスは既定バージョンのパスで書く（旧パスはバージョンなしのみ提供）
+	documented := make(map[string]bool)
+	for _, route := range Routes(Handlers{}) {
+		path := versioning.Path(versioning.Default, route.Path)
+		if route.Successor != "" {
+			path = route.Path
+		}
+		method := strings.ToLower(route.Method)
+		documented[method+" "+path] = true
+
+		_, ok := spec.Paths[path][method]
+		assert.True(t, ok, "%s %s が openapi.json にありません", route.Method, path)
+	}
+
+	// 仕様にあるのにルートがないものも検出する
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			assert.True(t, documented[method+" "+path], "openapi.json の %s %s に対応するルートがありません", strings.ToUpper(method), path)
+		}
+	}
+}
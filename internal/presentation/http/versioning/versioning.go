@@ -0,0 +1,99 @@
+package versioning
+
+// versioning.goはAPIバージョンの管理とバージョンごとのレスポンス変換を定義
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Version はAPIのバージョン
+type Version string
+
+const (
+	// V1 は最初の公開バージョン
+	V1 Version = "v1"
+
+	// Default はバージョンを含まない /api/... のパスで使われるバージョン
+	Default = V1
+)
+
+// Supported は /api/{version}/... にマウントするバージョンの一覧
+var Supported = []Version{V1}
+
+// HeaderName はレスポンスに付与するバージョンのヘッダー名
+const HeaderName = "API-Version"
+
+type contextKey struct{}
+
+// WithVersion はコンテキストにAPIバージョンを設定する
+func WithVersion(ctx context.Context, v Version) context.Context {
+	return context.WithValue(ctx, contextKey{}, v)
+}
+
+// FromContext はコンテキストからAPIバージョンを取得する（未設定なら Default）
+func FromContext(ctx context.Context) Version {
+	if v, ok := ctx.Value(contextKey{}).(Version); ok {
+		return v
+	}
+	return Default
+}
+
+// Middleware はリクエストのコンテキストにAPIバージョンを設定するミドルウェアを返す
+func Middleware(v Version, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HeaderName, string(v))
+		next.ServeHTTP(w, r.WithContext(WithVersion(r.Context(), v)))
+	}
+}
+
+// Path は /api/... のパスを指定バージョンのパス（/api/v1/...）に変換する
+// /api 以外のパスはそのまま返す
+func Path(v Version, path string) string {
+	if path != "/api" && !strings.HasPrefix(path, "/api/") {
+		return path
+	}
+	return "/api/" + string(v) + strings.TrimPrefix(path, "/api")
+}
+
+// Unversioned は指定バージョンのパス（/api/v1/...）をバージョンなしのパス（/api/...）に戻す
+// バージョン付きでないパスはそのまま返す
+func Unversioned(path string) string {
+	for _, v := range Supported {
+		prefix := "/api/" + string(v)
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return "/api" + strings.TrimPrefix(path, prefix)
+		}
+	}
+	return path
+}
+
+// Mapper はハンドラーが返すレスポンスDTOをバージョンごとの形に変換する
+// 例えば v2 でレスポンスを {"data": ...} で包む場合は、そのように変換する Mapper を登録する
+type Mapper func(data interface{}) interface{}
+
+var (
+	mu      sync.RWMutex
+	mappers = map[Version]Mapper{}
+)
+
+// RegisterMapper はバージョンごとのレスポンス変換を登録する
+// 登録のないバージョン（v1）はハンドラーのDTOをそのまま返す
+func RegisterMapper(v Version, m Mapper) {
+	mu.Lock()
+	defer mu.Unlock()
+	mappers[v] = m
+}
+
+// MapResponse はリクエストのバージョンに応じてレスポンスDTOを変換する
+func MapResponse(ctx context.Context, data interface{}) interface{} {
+	mu.RLock()
+	m, ok := mappers[FromContext(ctx)]
+	mu.RUnlock()
+	if !ok {
+		return data
+	}
+	return m(data)
+}
@@ -0,0 +1,54 @@
+package versioning
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPath(t *testing.T) {
+	assert.Equal(t, "/api/v1/questions/{id}", Path(V1, "/api/questions/{id}"))
+	assert.Equal(t, "/api/v1", Path(V1, "/api"))
+	assert.Equal(t, "/health", Path(V1, "/health"))
+	assert.Equal(t, "/apidocs", Path(V1, "/apidocs"))
+}
+
+func TestUnversioned(t *testing.T) {
+	assert.Equal(t, "/api/questions/{id}", Unversioned("/api/v1/questions/{id}"))
+	assert.Equal(t, "/api", Unversioned("/api/v1"))
+	assert.Equal(t, "/api/questions", Unversioned("/api/questions"))
+	assert.Equal(t, "/api/v10/questions", Unversioned("/api/v10/questions"))
+	assert.Equal(t, "/health", Unversioned("/health"))
+}
+
+func TestMapResponse(t *testing.T) {
+	const v2 Version = "v2-test"
+	RegisterMapper(v2, func(data interface{}) interface{} {
+		return map[string]interface{}{"data": data}
+	})
+
+	data := map[string]string{"name": "go"}
+
+	// v1 はそのまま
+	assert.Equal(t, data, MapResponse(WithVersion(context.Background(), V1), data))
+	// 未設定は Default（v1）として扱う
+	assert.Equal(t, data, MapResponse(context.Background(), data))
+	// 登録したバージョンは変換される
+	assert.Equal(t, map[string]interface{}{"data": data}, MapResponse(WithVersion(context.Background(), v2), data))
+}
+
+func TestMiddleware(t *testing.T) {
+	var got Version
+	handler := Middleware(V1, func(w http.ResponseWriter, r *http.Request) {
+		got = FromContext(r.Context())
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/genres", nil))
+
+	assert.Equal(t, V1, got)
+	assert.Equal(t, "v1", rec.Header().Get(HeaderName))
+}
@@ -0,0 +1,18 @@
+package client
+
+// answers.goは回答APIのメソッドを定義
+
+import (
+	"context"
+	"net/http"
+)
+
+// CreateAnswer は問題に回答し、採点結果を返す（ログインが必要）
+// 再試行しても重複して回答しないよう Idempotency-Key を付ける（WithIdempotencyKey を参照）
+func (c *Client) CreateAnswer(ctx context.Context, req CreateAnswerRequest) (*Answer, error) {
+	var answer Answer
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/answers", body: req, out: &answer, idempotencyKey: idempotencyKey(ctx)}); err != nil {
+		return nil, err
+	}
+	return &answer, nil
+}
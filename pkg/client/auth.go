@@ -0,0 +1,54 @@
+package client
+
+// auth.goは認証APIのメソッドを定義
+
+import (
+	"context"
+	"net/http"
+)
+
+// SignUp はユーザー登録を行い、返ったトークンを保持する
+func (c *Client) SignUp(ctx context.Context, req AuthRequest) (*AuthResponse, error) {
+	var resp AuthResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/auth/signup", body: req, out: &resp, noAuth: true}); err != nil {
+		return nil, err
+	}
+	c.storeTokens(&resp)
+	return &resp, nil
+}
+
+// Login はログインを行い、返ったトークンを保持する
+func (c *Client) Login(ctx context.Context, email, password string) (*AuthResponse, error) {
+	req := AuthRequest{Email: email, Password: password}
+
+	var resp AuthResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/auth/login", body: req, out: &resp, noAuth: true}); err != nil {
+		return nil, err
+	}
+	c.storeTokens(&resp)
+	return &resp, nil
+}
+
+// Refresh はリフレッシュトークンでトークンを更新する
+// 通常は期限切れの前や401が返った際に自動で更新されるため、呼び出す必要はない
+func (c *Client) Refresh(ctx context.Context) error {
+	return c.refreshIfUnchanged(ctx, c.Tokens().AccessToken)
+}
+
+// Logout はログアウトし、保持しているトークンを破棄する
+func (c *Client) Logout(ctx context.Context) error {
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/auth/logout"}); err != nil {
+		return err
+	}
+	c.SetTokens(Tokens{})
+	return nil
+}
+
+// Me はログイン中のユーザーを取得する
+func (c *Client) Me(ctx context.Context) (*User, error) {
+	var user User
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/auth/me", out: &user}); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,74 @@
+package client
+
+// choices.goは選択肢APIのメソッドを定義
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	presentationDTO "Shittaka_back/internal/presentation/dto"
+)
+
+// ListChoices は問題の選択肢を表示順で取得する
+// ログイン中で問題がシャッフル設定の場合は、利用者ごとに固定されたシャッフル順になる
+func (c *Client) ListChoices(ctx context.Context, questionID int64) ([]Choice, error) {
+	var resp presentationDTO.ChoicesResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: choicesPath(questionID), out: &resp}); err != nil {
+		return nil, err
+	}
+	return resp.Choices, nil
+}
+
+// CreateChoice は選択肢を作成する（ログインが必要）
+func (c *Client) CreateChoice(ctx context.Context, questionID int64, req CreateChoiceRequest) (*Choice, error) {
+	var choice Choice
+	if err := c.do(ctx, request{method: http.MethodPost, path: choicesPath(questionID), body: req, out: &choice}); err != nil {
+		return nil, err
+	}
+	return &choice, nil
+}
+
+// UpdateChoice は選択肢を更新する
+func (c *Client) UpdateChoice(ctx context.Context, questionID, choiceID int64, req UpdateChoiceRequest) (*Choice, error) {
+	var choice Choice
+	if err := c.do(ctx, request{method: http.MethodPut, path: choicePath(questionID, choiceID), body: req, out: &choice}); err != nil {
+		return nil, err
+	}
+	return &choice, nil
+}
+
+// DeleteChoice は選択肢を削除する
+func (c *Client) DeleteChoice(ctx context.Context, questionID, choiceID int64) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: choicePath(questionID, choiceID)})
+}
+
+// ReorderChoices は選択肢を並べ替える（問題の作成者のみ、全ての選択肢IDを表示順で指定）
+func (c *Client) ReorderChoices(ctx context.Context, questionID int64, choiceIDs []int64) ([]Choice, error) {
+	var resp presentationDTO.ChoicesResponse
+	req := ReorderChoicesRequest{ChoiceIDs: choiceIDs}
+	if err := c.do(ctx, request{method: http.MethodPut, path: choicesPath(questionID) + "/order", body: req, out: &resp}); err != nil {
+		return nil, err
+	}
+	return resp.Choices, nil
+}
+
+// ReplaceChoices は選択肢一式を置き換える（問題の作成者のみ、choices の順が表示順）
+func (c *Client) ReplaceChoices(ctx context.Context, questionID int64, choices []ChoiceInput) ([]Choice, error) {
+	var resp presentationDTO.ChoicesResponse
+	req := ReplaceChoicesRequest{Choices: choices}
+	if err := c.do(ctx, request{method: http.MethodPut, path: choicesPath(questionID), body: req, out: &resp}); err != nil {
+		return nil, err
+	}
+	return resp.Choices, nil
+}
+
+// choicesPath は問題の選択肢一覧のパスを返す
+func choicesPath(questionID int64) string {
+	return fmt.Sprintf("/questions/%d/choices", questionID)
+}
+
+// choicePath は選択肢のパスを返す
+func choicePath(questionID, choiceID int64) string {
+	return fmt.Sprintf("/questions/%d/choices/%d", questionID, choiceID)
+}
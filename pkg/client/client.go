@@ -0,0 +1,290 @@
+package client
+
+// client.goはShittaka APIのクライアント本体（リクエストの送信とトークンの管理）を定義
+// ボットやバッチから利用する想定で、トークンの有効期限が近い場合や
+// 401（INVALID_TOKEN / UNAUTHORIZED）が返った場合はリフレッシュトークンで自動的に更新する
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiPrefix はクライアントが利用するAPIのバージョン付きパス
+const apiPrefix = "/api/v1"
+
+// refreshMargin は有効期限のどれだけ前にトークンを更新するか
+const refreshMargin = 30 * time.Second
+
+// Tokens はクライアントが保持する認証トークン
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    int64 // アクセストークンの有効期限（UNIX時刻、0なら不明）
+}
+
+// Client はShittaka APIのクライアント
+// 複数のゴルーチンから同時に利用できる
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	language   string
+	onRefresh  func(Tokens)
+
+	mu     sync.Mutex // tokens を保護する
+	tokens Tokens
+
+	refreshMu sync.Mutex // トークンの更新を1つずつ行う
+}
+
+// Option はClientの設定を変更する
+type Option func(*Client)
+
+// WithHTTPClient は利用するHTTPクライアントを指定する（既定は http.DefaultClient）
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTokens は保存しておいたトークンを設定する（ログインを省略する場合）
+func WithTokens(tokens Tokens) Option {
+	return func(c *Client) {
+		c.tokens = tokens
+	}
+}
+
+// WithLanguage はエラーメッセージの言語（Accept-Language）を指定する
+func WithLanguage(language string) Option {
+	return func(c *Client) {
+		c.language = language
+	}
+}
+
+// WithTokenRefreshHook はトークンが更新されたときに呼ばれる関数を指定する（トークンの保存などに使う）
+func WithTokenRefreshHook(hook func(Tokens)) Option {
+	return func(c *Client) {
+		c.onRefresh = hook
+	}
+}
+
+// New は新しいClientを作成する
+// baseURL はサーバーのURL（"https://shittaka-back.fly.dev" など、/api は含めない）
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Tokens は現在のトークンを返す
+func (c *Client) Tokens() Tokens {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.tokens
+}
+
+// SetTokens はトークンを設定する
+func (c *Client) SetTokens(tokens Tokens) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens = tokens
+}
+
+// request はAPIへのリクエスト内容
+type request struct {
+	method string
+	path   string     // apiPrefix 以降のパス（"/questions/1" など）
+	query  url.Values // クエリパラメータ
+	body   interface{}
+	out    interface{} // レスポンスのデコード先（nilなら読み捨てる）
+
+	noAuth bool // トークンを付けない（ログイン・トークン更新など）
+
+	idempotencyKey string // Idempotency-Key ヘッダーの値（トークンを更新して再送する場合も同じキーを送る）
+	ifMatch        string // If-Match ヘッダーの値（更新するリソースの ETag）
+}
+
+// do はリクエストを送信し、レスポンスを out にデコードする
+// トークンの期限が近ければ先に更新し、401が返った場合は1度だけ更新して再送する
+func (c *Client) do(ctx context.Context, req request) error {
+	var body []byte
+	if req.body != nil {
+		encoded, err := json.Marshal(req.body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = encoded
+	}
+
+	token := ""
+	if !req.noAuth {
+		token = c.validAccessToken(ctx)
+	}
+
+	err := c.send(ctx, req, body, token)
+	if token == "" || !isTokenError(err) {
+		return err
+	}
+
+	if refreshErr := c.refreshIfUnchanged(ctx, token); refreshErr != nil {
+		return err
+	}
+	return c.send(ctx, req, body, c.Tokens().AccessToken)
+}
+
+// send はリクエストを1回送信する
+func (c *Client) send(ctx context.Context, req request, body []byte, token string) error {
+	endpoint := c.baseURL + apiPrefix + req.path
+	if len(req.query) > 0 {
+		endpoint += "?" + req.query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Accept", "application/json")
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+	if c.language != "" {
+		httpReq.Header.Set("Accept-Language", c.language)
+	}
+	if req.idempotencyKey != "" {
+		httpReq.Header.Set("Idempotency-Key", req.idempotencyKey)
+	}
+	if req.ifMatch != "" {
+		httpReq.Header.Set("If-Match", req.ifMatch)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return decodeError(resp)
+	}
+
+	if req.out == nil || resp.StatusCode == http.StatusNoContent {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(req.out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// validAccessToken は送信に使うアクセストークンを返す
+// 有効期限が近い場合は先に更新する（更新に失敗した場合は手元のトークンで送信する）
+func (c *Client) validAccessToken(ctx context.Context) string {
+	tokens := c.Tokens()
+	if tokens.AccessToken == "" {
+		return ""
+	}
+
+	if tokens.RefreshToken != "" && tokens.ExpiresAt != 0 &&
+		time.Now().Add(refreshMargin).Unix() >= tokens.ExpiresAt {
+		if err := c.refreshIfUnchanged(ctx, tokens.AccessToken); err == nil {
+			return c.Tokens().AccessToken
+		}
+	}
+	return tokens.AccessToken
+}
+
+// refreshIfUnchanged はアクセストークンが staleToken のままであれば更新する
+// 他のゴルーチンが既に更新していた場合は何もしない
+func (c *Client) refreshIfUnchanged(ctx context.Context, staleToken string) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	tokens := c.Tokens()
+	if tokens.AccessToken != staleToken {
+		return nil
+	}
+	if tokens.RefreshToken == "" {
+		return errors.New("no refresh token")
+	}
+
+	var resp AuthResponse
+	err := c.send(ctx, request{
+		method: http.MethodPost,
+		path:   "/auth/refresh",
+		out:    &resp,
+	}, mustMarshal(RefreshRequest{RefreshToken: tokens.RefreshToken}), "")
+	if err != nil {
+		return err
+	}
+
+	c.storeTokens(&resp)
+	return nil
+}
+
+// storeTokens は認証レスポンスのトークンを保持し、フックを呼び出す
+func (c *Client) storeTokens(resp *AuthResponse) {
+	tokens := Tokens{
+		AccessToken:  resp.Token,
+		RefreshToken: resp.RefreshToken,
+		ExpiresAt:    resp.ExpiresAt,
+	}
+	c.SetTokens(tokens)
+
+	if c.onRefresh != nil {
+		c.onRefresh(tokens)
+	}
+}
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey は作成のリクエスト（CreateQuestion / CreateAnswer）に付ける Idempotency-Key を指定したコンテキストを返す
+// 通信エラーなどで再試行する場合に同じキーを指定すると、サーバーは最初のレスポンスを返して重複して作成しない
+// 指定しない場合は呼び出しごとに新しいキーを生成する
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// idempotencyKey はコンテキストで指定されたキーを、なければ新しいキーを返す
+func idempotencyKey(ctx context.Context) string {
+	if key, ok := ctx.Value(idempotencyKeyContextKey{}).(string); ok && key != "" {
+		return key
+	}
+	return rand.Text()
+}
+
+// isTokenError はトークンの更新で解決する可能性のあるエラーかを判定する
+func isTokenError(err error) bool {
+	return errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrUnauthorized)
+}
+
+// mustMarshal はJSONに変換する（失敗しない値にのみ使う）
+func mustMarshal(v interface{}) []byte {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return encoded
+}
@@ -0,0 +1,405 @@
+package client_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	authUsecases "Shittaka_back/internal/application/auth/usecases"
+	genreUsecases "Shittaka_back/internal/application/genre/usecases"
+	"Shittaka_back/internal/application/metrics"
+	authEntities "Shittaka_back/internal/domain/auth/entities"
+	authRepositories "Shittaka_back/internal/domain/auth/repositories"
+	authServices "Shittaka_back/internal/domain/auth/services"
+	genreEntities "Shittaka_back/internal/domain/genre/entities"
+	"Shittaka_back/internal/domain/shared"
+	authMemory "Shittaka_back/internal/infrastructure/auth/memory"
+	"Shittaka_back/internal/presentation/http/apierror"
+	"Shittaka_back/internal/presentation/http/handlers"
+	"Shittaka_back/internal/presentation/http/router"
+	"Shittaka_back/pkg/client"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUserRepository はトークンをメモリ上で発行・検証するUserRepository
+type fakeUserRepository struct {
+	mu            sync.Mutex
+	user          *authEntities.User
+	password      string
+	accessTokens  map[string]bool
+	refreshTokens map[string]bool
+	issued        int
+}
+
+func newFakeUserRepository() *fakeUserRepository {
+	return &fakeUserRepository{
+		user:          authEntities.NewUser("user-1", "user@example.com", "tester"),
+		password:      "password123",
+		accessTokens:  make(map[string]bool),
+		refreshTokens: make(map[string]bool),
+	}
+}
+
+// issue はJWT形式のアクセストークンとリフレッシュトークンを発行する
+func (r *fakeUserRepository) issue() *authRepositories.AuthResult {
+	r.issued++
+	payload, _ := json.Marshal(map[string]interface{}{"sub": r.user.ID, "n": r.issued})
+	accessToken := "header." + base64.RawURLEncoding.EncodeToString(payload) + ".signature"
+	refreshToken := fmt.Sprintf("refresh-%d", r.issued)
+	r.accessTokens[accessToken] = true
+	r.refreshTokens[refreshToken] = true
+
+	return &authRepositories.AuthResult{
+		User:         r.user,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+// revokeAll は発行済みのアクセストークンを全て無効にする（期限切れの代わり）
+func (r *fakeUserRepository) revokeAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.accessTokens = make(map[string]bool)
+}
+
+func (r *fakeUserRepository) Create(ctx context.Context, email, password string, metadata map[string]interface{}) (*authEntities.User, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeUserRepository) Authenticate(ctx context.Context, email, password string) (*authRepositories.AuthResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if email != r.user.Email || password != r.password {
+		return nil, authRepositories.ErrInvalidCredentials
+	}
+	return r.issue(), nil
+}
+
+func (r *fakeUserRepository) FindByID(ctx context.Context, id string) (*authEntities.User, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeUserRepository) FindByEmail(ctx context.Context, email string) (*authEntities.User, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeUserRepository) Update(ctx context.Context, user *authEntities.User) error {
+	return errors.New("not implemented")
+}
+
+func (r *fakeUserRepository) Delete(ctx context.Context, id string) error {
+	return errors.New("not implemented")
+}
+
+func (r *fakeUserRepository) Logout(ctx context.Context, token string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.accessTokens, token)
+	return nil
+}
+
+func (r *fakeUserRepository) GetCurrentUser(ctx context.Context, token string) (*authEntities.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.accessTokens[token] {
+		return nil, errors.New("invalid token")
+	}
+	return r.user, nil
+}
+
+func (r *fakeUserRepository) Refresh(ctx context.Context, refreshToken string) (*authRepositories.AuthResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.refreshTokens[refreshToken] {
+		return nil, errors.New("invalid refresh token")
+	}
+	delete(r.refreshTokens, refreshToken) // リフレッシュトークンは1回のみ有効
+	return r.issue(), nil
+}
+
+// fakeGenreRepository はメモリ上のGenreRepository
+type fakeGenreRepository struct {
+	mu     sync.Mutex
+	genres []*genreEntities.Genre
+}
+
+func (r *fakeGenreRepository) Create(ctx context.Context, genre *genreEntities.Genre, userToken string) (*genreEntities.Genre, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	created := &genreEntities.Genre{ID: int64(len(r.genres) + 1), Name: genre.Name}
+	r.genres = append(r.genres, created)
+	return created, nil
+}
+
+func (r *fakeGenreRepository) FindByID(ctx context.Context, id int64) (*genreEntities.Genre, error) {
+	return nil, shared.NewDomainError("NOT_FOUND").With("resource", "genre")
+}
+
+func (r *fakeGenreRepository) FindAll(ctx context.Context) ([]*genreEntities.Genre, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*genreEntities.Genre(nil), r.genres...), nil
+}
+
+func (r *fakeGenreRepository) FindByName(ctx context.Context, name string, userToken string) (*genreEntities.Genre, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, genre := range r.genres {
+		if genre.Name == name {
+			return genre, nil
+		}
+	}
+	return nil, shared.NewDomainError("NOT_FOUND").With("resource", "genre")
+}
+
+// newTestServer はフェイクのリポジトリで組み立てたサーバーを起動する
+func newTestServer(t *testing.T) (*httptest.Server, *fakeUserRepository) {
+	userRepo := newFakeUserRepository()
+	loginThrottle := authServices.NewLoginThrottle(authMemory.NewLoginAttemptRepository(), authServices.LockoutPolicy{
+		FreeAttempts:       3,
+		BackoffBase:        time.Second,
+		BackoffMax:         time.Minute,
+		LockoutThreshold:   10,
+		IPLockoutThreshold: 50,
+		LockoutDuration:    15 * time.Minute,
+	})
+	authUsecase := authUsecases.NewAuthUsecase(authServices.NewAuthService(userRepo, loginThrottle), metrics.Noop{})
+	genreUsecase := genreUsecases.NewGenreUsecase(&fakeGenreRepository{})
+
+	server := httptest.NewServer(router.SetupRoutes(router.Handlers{
+		Auth:  handlers.NewAuthHandler(authUsecase),
+		Genre: handlers.NewGenreHandler(genreUsecase),
+	}))
+	t.Cleanup(server.Close)
+	return server, userRepo
+}
+
+func TestClient_LoginAndCallAPI(t *testing.T) {
+	server, _ := newTestServer(t)
+	c := client.New(server.URL)
+	ctx := context.Background()
+
+	_, err := c.Login(ctx, "user@example.com", "password123")
+	require.NoError(t, err)
+	assert.NotEmpty(t, c.Tokens().AccessToken)
+
+	user, err := c.Me(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "tester", user.Username)
+
+	genre, err := c.CreateGenre(ctx, client.CreateGenreRequest{Name: "プログラミング"})
+	require.NoError(t, err)
+	assert.Equal(t, "プログラミング", genre.Name)
+
+	genres, err := c.ListGenres(ctx)
+	require.NoError(t, err)
+	assert.Len(t, genres, 1)
+
+	require.NoError(t, c.Logout(ctx))
+	assert.Empty(t, c.Tokens().AccessToken)
+}
+
+func TestClient_RefreshesExpiredToken(t *testing.T) {
+	server, _ := newTestServer(t)
+	ctx := context.Background()
+
+	var refreshed []client.Tokens
+	c := client.New(server.URL, client.WithTokenRefreshHook(func(tokens client.Tokens) {
+		refreshed = append(refreshed, tokens)
+	}))
+	_, err := c.Login(ctx, "user@example.com", "password123")
+	require.NoError(t, err)
+	loginTokens := c.Tokens()
+
+	// 有効期限を過ぎたことにすると、リクエストの前に更新される
+	expired := loginTokens
+	expired.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	c.SetTokens(expired)
+
+	_, err = c.Me(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, loginTokens.AccessToken, c.Tokens().AccessToken)
+	assert.NotEqual(t, loginTokens.RefreshToken, c.Tokens().RefreshToken)
+	assert.Len(t, refreshed, 2) // ログインと更新
+}
+
+func TestClient_RetriesAfterInvalidToken(t *testing.T) {
+	server, userRepo := newTestServer(t)
+	c := client.New(server.URL)
+	ctx := context.Background()
+
+	_, err := c.Login(ctx, "user@example.com", "password123")
+	require.NoError(t, err)
+	before := c.Tokens().AccessToken
+
+	// サーバー側でトークンが無効になっても、401を受けて更新・再送する
+	userRepo.revokeAll()
+	_, err = c.Me(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, before, c.Tokens().AccessToken)
+
+	// リフレッシュトークンも使えない場合は元のエラーを返す
+	userRepo.revokeAll()
+	c.SetTokens(client.Tokens{AccessToken: c.Tokens().AccessToken, RefreshToken: "unknown"})
+	_, err = c.Me(ctx)
+	assert.ErrorIs(t, err, client.ErrInvalidToken)
+}
+
+func TestClient_DecodesErrors(t *testing.T) {
+	server, _ := newTestServer(t)
+	ctx := context.Background()
+
+	t.Run("認証エラー", func(t *testing.T) {
+		c := client.New(server.URL)
+		_, err := c.Login(ctx, "user@example.com", "wrong")
+
+		var apiErr *client.APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, 401, apiErr.StatusCode)
+		assert.ErrorIs(t, err, client.ErrAuthFailed)
+		assert.NotEmpty(t, apiErr.RequestID)
+	})
+
+	t.Run("バリデーションエラーは全項目を返す", func(t *testing.T) {
+		c := client.New(server.URL, client.WithLanguage("en"))
+		_, err := c.Login(ctx, "", "")
+
+		var apiErr *client.APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.ErrorIs(t, err, client.ErrValidation)
+		require.Len(t, apiErr.Errors, 2)
+		assert.Equal(t, "Email is required", apiErr.Errors[0].Message)
+		assert.Equal(t, "password", apiErr.Errors[1].Field)
+	})
+
+	t.Run("ログインしていない", func(t *testing.T) {
+		c := client.New(server.URL)
+		_, err := c.CreateGenre(ctx, client.CreateGenreRequest{Name: "数学"})
+		assert.ErrorIs(t, err, client.ErrUnauthorized)
+	})
+}
+
+func TestClient_LoginLockout(t *testing.T) {
+	server, _ := newTestServer(t)
+	c := client.New(server.URL)
+	ctx := context.Background()
+
+	// 待ち時間なしで失敗できる回数（3回）を超えるまで失敗する
+	for i := 0; i < 4; i++ {
+		_, err := c.Login(ctx, "user@example.com", "wrong")
+		require.ErrorIs(t, err, client.ErrAuthFailed)
+	}
+
+	// 正しいパスワードでも待ち時間が過ぎるまではログインできない
+	_, err := c.Login(ctx, "user@example.com", "password123")
+
+	var apiErr *client.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.ErrorIs(t, err, client.ErrAccountLocked)
+	assert.Equal(t, 429, apiErr.StatusCode)
+	assert.Equal(t, time.Second, apiErr.RetryAfter)
+	assert.False(t, apiErr.UnlockAt.IsZero())
+}
+
+func TestClient_SendsIdempotencyKey(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	c := client.New(server.URL)
+
+	// 指定したキーは再試行でも同じ値を送る
+	ctx := client.WithIdempotencyKey(context.Background(), "retry-key")
+	_, err := c.CreateAnswer(ctx, client.CreateAnswerRequest{QuestionID: 1})
+	require.NoError(t, err)
+	_, err = c.CreateAnswer(ctx, client.CreateAnswerRequest{QuestionID: 1})
+	require.NoError(t, err)
+
+	// 指定しなければ呼び出しごとに新しいキーを生成する
+	_, err = c.CreateAnswer(context.Background(), client.CreateAnswerRequest{QuestionID: 1})
+	require.NoError(t, err)
+	_, err = c.CreateAnswer(context.Background(), client.CreateAnswerRequest{QuestionID: 1})
+	require.NoError(t, err)
+
+	require.Len(t, keys, 4)
+	assert.Equal(t, []string{"retry-key", "retry-key"}, keys[:2])
+	assert.NotEmpty(t, keys[2])
+	assert.NotEqual(t, keys[2], keys[3])
+}
+
+func TestClient_UpdateQuestionSendsIfMatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 版3だけを最新として受け付ける
+		if r.Header.Get("If-Match") != `"v3"` {
+			apierror.Write(w, r, shared.NewDomainError("PRECONDITION_FAILED").With("current_version", 3))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"ok","version":4}`))
+	}))
+	t.Cleanup(server.Close)
+	c := client.New(server.URL)
+	ctx := context.Background()
+
+	version, err := c.UpdateQuestion(ctx, 1, 3, client.UpdateQuestionRequest{Title: "新しいタイトル"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, version)
+
+	_, err = c.UpdateQuestion(ctx, 1, 2, client.UpdateQuestionRequest{Title: "新しいタイトル"})
+	var apiErr *client.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.ErrorIs(t, err, client.ErrPreconditionFailed)
+	assert.Equal(t, 412, apiErr.StatusCode)
+	assert.Equal(t, 3, apiErr.CurrentVersion)
+}
+
+func TestServer_ConditionalGET(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	get := func(header map[string]string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v1/genres", nil)
+		require.NoError(t, err)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	first := get(nil)
+	require.Equal(t, http.StatusOK, first.StatusCode)
+	assert.Equal(t, "public, no-cache", first.Header.Get("Cache-Control"))
+	require.NotEmpty(t, first.Header.Get("ETag"))
+	require.NotEmpty(t, first.Header.Get("Last-Modified"))
+
+	assert.Equal(t, http.StatusNotModified, get(map[string]string{"If-None-Match": first.Header.Get("ETag")}).StatusCode)
+	assert.Equal(t, http.StatusNotModified, get(map[string]string{"If-Modified-Since": first.Header.Get("Last-Modified")}).StatusCode)
+
+	// ジャンルが増えれば新しい内容を返す
+	c := client.New(server.URL)
+	_, err := c.Login(context.Background(), "user@example.com", "password123")
+	require.NoError(t, err)
+	_, err = c.CreateGenre(context.Background(), client.CreateGenreRequest{Name: "数学"})
+	require.NoError(t, err)
+
+	changed := get(map[string]string{"If-None-Match": first.Header.Get("ETag")})
+	assert.Equal(t, http.StatusOK, changed.StatusCode)
+	assert.NotEqual(t, first.Header.Get("ETag"), changed.Header.Get("ETag"))
+}
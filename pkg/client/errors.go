@@ -0,0 +1,95 @@
+package client
+
+// errors.goはAPIのエラーレスポンスを表す型を定義
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	presentationDTO "Shittaka_back/internal/presentation/dto"
+)
+
+// APIError はAPIが返したエラー
+// errors.Is(err, client.ErrNotFound) のようにエラーコードで判定できる
+type APIError struct {
+	StatusCode     int           // HTTPステータス
+	Code           string        // エラーコード（NOT_FOUND, VALIDATION_ERROR など）
+	Message        string        // 表示用のメッセージ
+	Field          string        // バリデーションエラーの対象項目（複数ある場合は最初の項目）
+	Errors         []FieldError  // バリデーションエラーの全項目
+	RetryAfter     time.Duration // 再試行できるまでの時間（RATE_LIMITED / ACCOUNT_LOCKED）
+	UnlockAt       time.Time     // ロックが解除される日時（ACCOUNT_LOCKED）
+	CurrentVersion int           // 保存されている現在の版（PRECONDITION_FAILED）
+	RequestID      string        // 問い合わせ用のリクエストID
+}
+
+func (e *APIError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("shittaka api error [%d %s]: %s (request_id=%s)", e.StatusCode, e.Code, e.Message, e.RequestID)
+	}
+	return fmt.Sprintf("shittaka api error [%d %s]: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Is はエラーコードが同じ APIError を同じエラーとみなす
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	return ok && t.Code != "" && t.Code == e.Code
+}
+
+// errors.Is で判定するためのエラー（エラーコードのみを持つ）
+var (
+	ErrValidation             = &APIError{Code: "VALIDATION_ERROR"}
+	ErrInvalidJSON            = &APIError{Code: "INVALID_JSON"}
+	ErrUnauthorized           = &APIError{Code: "UNAUTHORIZED"}
+	ErrInvalidToken           = &APIError{Code: "INVALID_TOKEN"}
+	ErrAuthFailed             = &APIError{Code: "AUTH_FAILED"}
+	ErrForbidden              = &APIError{Code: "FORBIDDEN"}
+	ErrNotFound               = &APIError{Code: "NOT_FOUND"}
+	ErrUserExists             = &APIError{Code: "USER_EXISTS"}
+	ErrGenreExists            = &APIError{Code: "GENRE_EXISTS"}
+	ErrPayloadTooLarge        = &APIError{Code: "PAYLOAD_TOO_LARGE"}
+	ErrPreconditionFailed     = &APIError{Code: "PRECONDITION_FAILED"}
+	ErrPreconditionRequired   = &APIError{Code: "PRECONDITION_REQUIRED"}
+	ErrIdempotencyKeyInUse    = &APIError{Code: "IDEMPOTENCY_KEY_IN_USE"}
+	ErrIdempotencyKeyMismatch = &APIError{Code: "IDEMPOTENCY_KEY_MISMATCH"}
+	ErrRateLimited            = &APIError{Code: "RATE_LIMITED"}
+	ErrAccountLocked          = &APIError{Code: "ACCOUNT_LOCKED"}
+	ErrInternal               = &APIError{Code: "INTERNAL_ERROR"}
+)
+
+// decodeError はエラーレスポンスを APIError に変換する
+// エラーの形式でない場合（プロキシのエラーページなど）はステータスのみを設定する
+func decodeError(resp *http.Response) error {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    http.StatusText(resp.StatusCode),
+		RequestID:  resp.Header.Get("X-Request-ID"),
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return apiErr
+	}
+
+	var envelope presentationDTO.ErrorResponse
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Error.Code == "" {
+		return apiErr
+	}
+
+	apiErr.Code = envelope.Error.Code
+	apiErr.Message = envelope.Error.Message
+	apiErr.Field = envelope.Error.Field
+	apiErr.Errors = envelope.Error.Errors
+	apiErr.RetryAfter = time.Duration(envelope.Error.RetryAfter) * time.Second
+	if unlockAt, err := time.Parse(time.RFC3339, envelope.Error.UnlockAt); err == nil {
+		apiErr.UnlockAt = unlockAt
+	}
+	apiErr.CurrentVersion = envelope.Error.CurrentVersion
+	if envelope.Error.RequestID != "" {
+		apiErr.RequestID = envelope.Error.RequestID
+	}
+	return apiErr
+}
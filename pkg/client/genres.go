@@ -0,0 +1,26 @@
+package client
+
+// genres.goはジャンルAPIのメソッドを定義
+
+import (
+	"context"
+	"net/http"
+)
+
+// ListGenres は全てのジャンルを取得する
+func (c *Client) ListGenres(ctx context.Context) ([]Genre, error) {
+	var genres []Genre
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/genres", out: &genres}); err != nil {
+		return nil, err
+	}
+	return genres, nil
+}
+
+// CreateGenre はジャンルを作成する（ログインが必要）
+func (c *Client) CreateGenre(ctx context.Context, req CreateGenreRequest) (*Genre, error) {
+	var genre Genre
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/genres", body: req, out: &genre}); err != nil {
+		return nil, err
+	}
+	return &genre, nil
+}
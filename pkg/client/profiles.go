@@ -0,0 +1,36 @@
+package client
+
+// profiles.goはプロフィールAPIのメソッドを定義
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// GetProfile はプロフィールを取得する
+func (c *Client) GetProfile(ctx context.Context, userID string) (*Profile, error) {
+	var profile Profile
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/profiles/" + url.PathEscape(userID), out: &profile}); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// CreateProfile はプロフィールを作成する
+func (c *Client) CreateProfile(ctx context.Context, req CreateProfileRequest) (*Profile, error) {
+	var profile Profile
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/profiles", body: req, out: &profile}); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// UpdateProfile はプロフィールを更新する
+func (c *Client) UpdateProfile(ctx context.Context, userID string, req UpdateProfileRequest) (*Profile, error) {
+	var profile Profile
+	if err := c.do(ctx, request{method: http.MethodPut, path: "/profiles/" + url.PathEscape(userID), body: req, out: &profile}); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
@@ -0,0 +1,93 @@
+package client
+
+// questions.goは問題APIとタグAPIのメソッドを定義
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"Shittaka_back/internal/presentation/http/etag"
+)
+
+// ListQuestions は問題一覧を取得する（tags を指定すると全てのタグを含む問題に絞り込む）
+func (c *Client) ListQuestions(ctx context.Context, tags ...string) ([]Question, error) {
+	query := url.Values{}
+	for _, tag := range tags {
+		query.Add("tag", tag)
+	}
+
+	var questions []Question
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/questions", query: query, out: &questions}); err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
+
+// ListMyQuestions はログイン中のユーザーの問題一覧を正解付きで取得する
+func (c *Client) ListMyQuestions(ctx context.Context) ([]OwnerQuestion, error) {
+	var questions []OwnerQuestion
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/my-questions", out: &questions}); err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
+
+// GetQuestion は問題を取得する
+func (c *Client) GetQuestion(ctx context.Context, id int64) (*Question, error) {
+	var question Question
+	if err := c.do(ctx, request{method: http.MethodGet, path: questionPath(id), out: &question}); err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+// CreateQuestion は問題を作成する（ログインが必要）
+// 再試行しても重複して作成しないよう Idempotency-Key を付ける（WithIdempotencyKey を参照）
+func (c *Client) CreateQuestion(ctx context.Context, req CreateQuestionRequest) (*OwnerQuestion, error) {
+	var question OwnerQuestion
+	if err := c.do(ctx, request{method: http.MethodPost, path: "/questions", body: req, out: &question, idempotencyKey: idempotencyKey(ctx)}); err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+// UpdateQuestion は問題を更新し、更新後の版を返す（作成者のみ）
+// version には取得したときの問題の版（Question.Version）を指定する
+// 先に他の更新が保存されていた場合は ErrPreconditionFailed（APIError.CurrentVersion に現在の版）を返す
+func (c *Client) UpdateQuestion(ctx context.Context, id int64, version int, req UpdateQuestionRequest) (int, error) {
+	var resp UpdateQuestionResponse
+	if err := c.do(ctx, request{method: http.MethodPut, path: questionPath(id), body: req, out: &resp, ifMatch: etag.FromVersion(version)}); err != nil {
+		return 0, err
+	}
+	return resp.Version, nil
+}
+
+// DeleteQuestion は問題を削除する（作成者のみ）
+func (c *Client) DeleteQuestion(ctx context.Context, id int64) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: questionPath(id)})
+}
+
+// ListTags はタグ一覧を取得する（prefix で前方一致、limit が0なら既定の件数）
+func (c *Client) ListTags(ctx context.Context, prefix string, limit int) ([]Tag, error) {
+	query := url.Values{}
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	var tags []Tag
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/tags", query: query, out: &tags}); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// questionPath は問題のパスを返す
+func questionPath(id int64) string {
+	return fmt.Sprintf("/questions/%d", id)
+}
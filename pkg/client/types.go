@@ -0,0 +1,58 @@
+package client
+
+// types.goはAPIのリクエスト・レスポンスの型を定義
+// サーバーのHTTP DTOをそのまま使い、モジュールの外からも型名で参照できるよう別名を付ける
+
+import presentationDTO "Shittaka_back/internal/presentation/dto"
+
+// 認証
+type (
+	AuthRequest    = presentationDTO.AuthRequest
+	AuthResponse   = presentationDTO.AuthResponse
+	RefreshRequest = presentationDTO.RefreshRequest
+	User           = presentationDTO.UserDTO
+)
+
+// プロフィール
+type (
+	Profile              = presentationDTO.ProfileResponse
+	CreateProfileRequest = presentationDTO.CreateProfileRequest
+	UpdateProfileRequest = presentationDTO.UpdateProfileRequest
+)
+
+// ジャンル
+type (
+	Genre              = presentationDTO.GenreResponse
+	CreateGenreRequest = presentationDTO.CreateGenreRequest
+)
+
+// 問題
+type (
+	Question               = presentationDTO.QuestionResponse
+	OwnerQuestion          = presentationDTO.OwnerQuestionResponse
+	CreateQuestionRequest  = presentationDTO.CreateQuestionRequest
+	UpdateQuestionRequest  = presentationDTO.UpdateQuestionRequest
+	UpdateQuestionResponse = presentationDTO.UpdateQuestionResponse
+)
+
+// 選択肢
+type (
+	Choice                = presentationDTO.ChoiceResponse
+	CreateChoiceRequest   = presentationDTO.CreateChoiceRequest
+	UpdateChoiceRequest   = presentationDTO.UpdateChoiceRequest
+	ChoiceInput           = presentationDTO.ChoiceInput
+	ReorderChoicesRequest = presentationDTO.ReorderChoicesRequest
+	ReplaceChoicesRequest = presentationDTO.ReplaceChoicesRequest
+)
+
+// 回答
+type (
+	Answer              = presentationDTO.AnswerResponse
+	CreateAnswerRequest = presentationDTO.CreateAnswerRequest
+)
+
+// タグ
+type Tag = presentationDTO.TagResponse
+
+// エラー
+type FieldError = presentationDTO.FieldError
@@ -0,0 +1,58 @@
+-- 問題に自由入力のタグを付けるためのテーブル
+
+create table if not exists public.tags (
+    id         bigint generated by default as identity primary key,
+    name       text not null unique,
+    created_at timestamptz not null default now()
+);
+
+create table if not exists public.question_tags (
+    question_id bigint not null references public.questions (id) on delete cascade,
+    tag_id      bigint not null references public.tags (id) on delete cascade,
+    primary key (question_id, tag_id)
+);
+
+create index if not exists question_tags_tag_id_idx on public.question_tags (tag_id);
+
+-- タグごとの使用数（オートコンプリート用）
+create or replace view public.tag_usage as
+select t.id, t.name, count(qt.question_id)::int as usage_count
+from public.tags t
+left join public.question_tags qt on qt.tag_id = t.id
+group by t.id, t.name;
+
+alter table public.tags enable row level security;
+alter table public.question_tags enable row level security;
+
+create policy "tags are readable by everyone"
+    on public.tags for select using (true);
+
+create policy "authenticated users can create tags"
+    on public.tags for insert to authenticated with check (true);
+
+create policy "question tags are readable by everyone"
+    on public.question_tags for select using (true);
+
+create policy "question owners can manage tags"
+    on public.question_tags for all to authenticated
+    using (exists (select 1 from public.questions q where q.id = question_id and q.user_id = auth.uid()))
+    with check (exists (select 1 from public.questions q where q.id = question_id and q.user_id = auth.uid()));
+
+-- 問題のタグを一括で置き換える（1トランザクションで実行される）
+create or replace function public.set_question_tags(p_question_id bigint, p_names text[])
+returns void
+language plpgsql
+as $$
+begin
+    insert into public.tags (name)
+    select distinct unnest(p_names)
+    on conflict (name) do nothing;
+
+    delete from public.question_tags where question_id = p_question_id;
+
+    insert into public.question_tags (question_id, tag_id)
+    select p_question_id, t.id
+    from public.tags t
+    where t.name = any (p_names);
+end;
+$$;
@@ -0,0 +1,20 @@
+-- 問題形式（複数選択・○×・記述・数値）と採点結果の保存
+
+alter table public.questions
+    add column if not exists type text not null default 'single_choice'
+        check (type in ('single_choice', 'multi_select', 'true_false', 'free_text', 'numeric')),
+    add column if not exists partial_credit boolean not null default false,
+    add column if not exists accepted_answers text[] not null default '{}',
+    add column if not exists correct_boolean boolean,
+    add column if not exists numeric_answer double precision,
+    add column if not exists numeric_tolerance double precision not null default 0
+        check (numeric_tolerance >= 0);
+
+alter table public.answers
+    alter column choice_id drop not null,
+    add column if not exists choice_ids bigint[] not null default '{}',
+    add column if not exists boolean_answer boolean,
+    add column if not exists text_answer text,
+    add column if not exists numeric_answer double precision,
+    add column if not exists is_correct boolean not null default false,
+    add column if not exists score double precision not null default 0;
@@ -0,0 +1,18 @@
+-- 選択肢の表示順と、問題ごとのシャッフル設定
+
+alter table public.choices
+    add column if not exists position integer not null default 0;
+
+-- 既存の選択肢はID順に番号を振る
+update public.choices c
+set position = numbered.rn
+from (
+    select id, row_number() over (partition by question_id order by id) as rn
+    from public.choices
+) numbered
+where c.id = numbered.id and c.position = 0;
+
+create index if not exists choices_question_id_position_idx on public.choices (question_id, position);
+
+alter table public.questions
+    add column if not exists shuffle_choices boolean not null default false;
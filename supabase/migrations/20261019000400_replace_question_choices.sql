@@ -0,0 +1,31 @@
+-- 問題の選択肢一式を1トランザクションで置き換える
+-- 呼び出し元の権限（RLS）で実行されるため、問題の作成者以外は変更できない
+
+create or replace function public.replace_question_choices(
+    p_question_id bigint,
+    p_inserts     jsonb,
+    p_updates     jsonb,
+    p_deletes     bigint[]
+)
+returns setof public.choices
+language plpgsql
+as $$
+begin
+    delete from public.choices
+    where question_id = p_question_id and id = any (p_deletes);
+
+    update public.choices c
+    set text = u.text, is_correct = u.is_correct, position = u.position
+    from jsonb_to_recordset(p_updates) as u(id bigint, text text, is_correct boolean, position integer)
+    where c.id = u.id and c.question_id = p_question_id;
+
+    insert into public.choices (question_id, text, is_correct, position)
+    select p_question_id, i.text, i.is_correct, i.position
+    from jsonb_to_recordset(p_inserts) as i(text text, is_correct boolean, position integer);
+
+    return query
+    select * from public.choices
+    where question_id = p_question_id
+    order by position, id;
+end;
+$$;
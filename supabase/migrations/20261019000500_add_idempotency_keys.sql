@@ -0,0 +1,47 @@
+-- Idempotency-Key ヘッダーで再送されたリクエストに最初のレスポンスを返すための記録
+-- サーバーがサービスロールのキーで読み書きする（RLSのポリシーがないためクライアントからは参照できない）
+
+create table if not exists public.idempotency_keys (
+    key          text primary key,
+    request_hash text not null,
+    completed    boolean not null default false,
+    status_code  integer,
+    headers      jsonb,
+    body         text,
+    created_at   timestamptz not null default now(),
+    expires_at   timestamptz not null
+);
+
+create index if not exists idempotency_keys_expires_at_idx on public.idempotency_keys (expires_at);
+
+alter table public.idempotency_keys enable row level security;
+
+-- キーを処理中として確保する（1トランザクションで実行される）
+-- 確保できた場合は reserved = true、既に記録がある場合はその記録を返す
+create or replace function public.reserve_idempotency_key(p_key text, p_request_hash text, p_ttl_seconds integer)
+returns table (
+    reserved     boolean,
+    request_hash text,
+    completed    boolean,
+    status_code  integer,
+    headers      jsonb,
+    body         text
+)
+language plpgsql
+as $$
+declare
+    v_inserted integer;
+begin
+    delete from public.idempotency_keys k where k.expires_at <= now();
+
+    insert into public.idempotency_keys (key, request_hash, expires_at)
+    values (p_key, p_request_hash, now() + make_interval(secs => p_ttl_seconds))
+    on conflict (key) do nothing;
+    get diagnostics v_inserted = row_count;
+
+    return query
+    select v_inserted > 0, k.request_hash, k.completed, k.status_code, k.headers, k.body
+    from public.idempotency_keys k
+    where k.key = p_key;
+end;
+$$;
@@ -0,0 +1,6 @@
+-- 同時編集で他の変更を上書きしないよう、問題に版と更新日時を持たせる
+-- 版はAPIが更新のたびに1ずつ増やし、保存されている版と一致する場合のみ更新する
+
+alter table public.questions
+    add column if not exists version    integer     not null default 1,
+    add column if not exists updated_at timestamptz not null default now();
@@ -0,0 +1,41 @@
+-- 問題の選択肢の表示順を1トランザクションで並べ替える
+-- p_choice_ids の順に 1 から番号を振る（問題の全ての選択肢を重複なく指定する必要がある）
+-- 同時の並べ替えが混ざらないよう、問題の選択肢の行をロックしてから更新する
+-- 呼び出し元の権限（RLS）で実行されるため、問題の作成者以外は変更できない
+
+create or replace function public.reorder_question_choices(
+    p_question_id bigint,
+    p_choice_ids  bigint[]
+)
+returns setof public.choices
+language plpgsql
+as $$
+declare
+    current_ids bigint[];
+begin
+    select coalesce(array_agg(id order by id), '{}')
+    into current_ids
+    from (
+        select id from public.choices
+        where question_id = p_question_id
+        for update
+    ) locked;
+
+    -- 読み込んだ後に選択肢が追加・削除された場合は並べ替えない
+    if cardinality(p_choice_ids) <> cardinality(current_ids)
+        or (select array_agg(distinct x order by x) from unnest(p_choice_ids) as x) is distinct from current_ids then
+        raise exception 'choice_ids must list every choice of question % exactly once', p_question_id
+            using errcode = '22023';
+    end if;
+
+    update public.choices c
+    set position = o.ordinality
+    from unnest(p_choice_ids) with ordinality as o(id, ordinality)
+    where c.id = o.id and c.question_id = p_question_id;
+
+    return query
+    select * from public.choices
+    where question_id = p_question_id
+    order by position, id;
+end;
+$$;
@@ -0,0 +1,67 @@
+-- 問題とタグを1トランザクションで保存する
+-- タグの保存に失敗したときに問題だけが作成・更新された状態を残さない
+-- 呼び出し元の権限（RLS）で実行されるため、問題の作成者以外は更新できない
+
+-- 問題を作成し、タグを設定する
+create or replace function public.create_question(p_question jsonb, p_tags text[])
+returns setof public.questions
+language plpgsql
+as $$
+declare
+    v_id bigint;
+begin
+    insert into public.questions (
+        genre_id, user_id, title, body, explanation, type, partial_credit,
+        accepted_answers, correct_boolean, numeric_answer, numeric_tolerance, shuffle_choices
+    )
+    select q.genre_id, q.user_id, q.title, q.body, q.explanation, q.type, q.partial_credit,
+           q.accepted_answers, q.correct_boolean, q.numeric_answer, q.numeric_tolerance, q.shuffle_choices
+    from jsonb_populate_record(null::public.questions, p_question) as q
+    returning id into v_id;
+
+    perform public.set_question_tags(v_id, coalesce(p_tags, '{}'));
+
+    return query
+    select * from public.questions where id = v_id;
+end;
+$$;
+
+-- 版が p_version と一致する場合のみ問題を更新し、p_tags が null でなければタグを置き換える
+-- 版が一致しない（他の更新が先に保存された）場合は何も変更せず、空の結果を返す
+create or replace function public.update_question(
+    p_id       bigint,
+    p_version  integer,
+    p_question jsonb,
+    p_tags     text[]
+)
+returns setof public.questions
+language plpgsql
+as $$
+begin
+    update public.questions t
+    set version           = t.version + 1,
+        updated_at        = now(),
+        title             = q.title,
+        body              = q.body,
+        explanation       = q.explanation,
+        partial_credit    = q.partial_credit,
+        accepted_answers  = q.accepted_answers,
+        correct_boolean   = q.correct_boolean,
+        numeric_answer    = q.numeric_answer,
+        numeric_tolerance = q.numeric_tolerance,
+        shuffle_choices   = q.shuffle_choices
+    from jsonb_populate_record(null::public.questions, p_question) as q
+    where t.id = p_id and t.version = p_version;
+
+    if not found then
+        return;
+    end if;
+
+    if p_tags is not null then
+        perform public.set_question_tags(p_id, p_tags);
+    end if;
+
+    return query
+    select * from public.questions where id = p_id;
+end;
+$$;